@@ -0,0 +1,214 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// MorphCompatible converts the two Paths a and b into the FloatPaths of the
+// compatible structure, which have the same number of the closed paths and the
+// same number of the vertices in each pair of the corresponding closed paths,
+// so that they can be interpolated vertex by vertex. The closed paths are
+// paired greedily by the distance between the centers of their bounding boxes,
+// outlines with outlines and holes with holes. Each closed path left unpaired
+// is paired with a closed path collapsed into the center of itself, which
+// appears or disappears in the interpolation. In each pair, the vertices are
+// rotated so that the starts are nearest to each other, and the shorter one is
+// supplemented with the vertices splitting its longest segments. Both results
+// have the canvas large enough for a and b.
+func MorphCompatible(a, b *Path) (*FloatPath, *FloatPath) {
+	w, h := a.Width, a.Height
+	if w < b.Width {
+		w = b.Width
+	}
+	if h < b.Height {
+		h = b.Height
+	}
+	fa := &FloatPath{Width: float64(w), Height: float64(h)}
+	fb := &FloatPath{Width: float64(w), Height: float64(h)}
+	for _, pr := range morphPairs(a, b) {
+		va, vb := pr.vertices(a, b)
+		n := len(va)
+		if n < len(vb) {
+			n = len(vb)
+		}
+		fa.Vertices = append(fa.Vertices, subdivide(va, n))
+		fb.Vertices = append(fb.Vertices, subdivide(vb, n))
+	}
+	return fa, fb
+}
+
+// morphPair is a pair of the indexes of the closed paths of two Paths, either
+// of which may be -1 for the missing one.
+type morphPair struct{ i, j int }
+
+// morphPairs pairs the closed paths of a and b.
+func morphPairs(a, b *Path) []morphPair {
+	type cand struct {
+		i, j int
+		d    float64
+	}
+	center := func(p *Path, n int) FloatVertex {
+		r := p.PathBounds(n)
+		return FloatVertex{float64(r.Min.X+r.Max.X) / 2, float64(r.Min.Y+r.Max.Y) / 2}
+	}
+	var cands []cand
+	for i, vs := range a.Vertices {
+		if len(vs) == 0 {
+			continue
+		}
+		for j, us := range b.Vertices {
+			if len(us) == 0 || (signedArea(vs) < 0) != (signedArea(us) < 0) {
+				continue
+			}
+			ca, cb := center(a, i), center(b, j)
+			cands = append(cands, cand{i, j, math.Hypot(ca[0]-cb[0], ca[1]-cb[1])})
+		}
+	}
+	sort.SliceStable(cands, func(x, y int) bool { return cands[x].d < cands[y].d })
+	pa, pb := make(map[int]int), make(map[int]bool)
+	for _, c := range cands {
+		if _, ok := pa[c.i]; !ok && !pb[c.j] {
+			pa[c.i], pb[c.j] = c.j, true
+		}
+	}
+	var ret []morphPair
+	for i, vs := range a.Vertices {
+		if len(vs) == 0 {
+			continue
+		}
+		if j, ok := pa[i]; ok {
+			ret = append(ret, morphPair{i, j})
+		} else {
+			ret = append(ret, morphPair{i, -1})
+		}
+	}
+	for j, us := range b.Vertices {
+		if len(us) != 0 && !pb[j] {
+			ret = append(ret, morphPair{-1, j})
+		}
+	}
+	return ret
+}
+
+// vertices returns the vertices of the pair pr of the closed paths of a and b,
+// with the missing one collapsed into the center of the other, and the second
+// one rotated to start at the vertex nearest to the start of the first one.
+func (pr morphPair) vertices(a, b *Path) ([]FloatVertex, []FloatVertex) {
+	collapse := func(p *Path, n int) []FloatVertex {
+		r := p.PathBounds(n)
+		c := FloatVertex{float64(r.Min.X+r.Max.X) / 2, float64(r.Min.Y+r.Max.Y) / 2}
+		ret := make([]FloatVertex, len(p.Vertices[n]))
+		for i := range ret {
+			ret[i] = c
+		}
+		return ret
+	}
+	switch {
+	case pr.j < 0:
+		return floatVertices(a.Vertices[pr.i]), collapse(a, pr.i)
+	case pr.i < 0:
+		return collapse(b, pr.j), floatVertices(b.Vertices[pr.j])
+	}
+	va, vb := floatVertices(a.Vertices[pr.i]), floatVertices(b.Vertices[pr.j])
+	k, d := 0, math.Inf(1)
+	for i, v := range vb {
+		if dv := math.Hypot(v[0]-va[0][0], v[1]-va[0][1]); dv < d {
+			k, d = i, dv
+		}
+	}
+	return va, append(vb[k:len(vb):len(vb)], vb[:k]...)
+}
+
+// subdivide returns the closed path vs supplemented with the vertices up to n
+// vertices, each of which splits the longest segment at the time in half.
+func subdivide(vs []FloatVertex, n int) []FloatVertex {
+	ret := append(make([]FloatVertex, 0, n), vs...)
+	for len(ret) < n {
+		k, l := 0, -1.0
+		for i, v := range ret {
+			u := ret[(i+1)%len(ret)]
+			if d := math.Hypot(u[0]-v[0], u[1]-v[1]); l < d {
+				k, l = i, d
+			}
+		}
+		v, u := ret[k], ret[(k+1)%len(ret)]
+		ret = append(ret, FloatVertex{})
+		copy(ret[k+2:], ret[k+1:])
+		ret[k+1] = FloatVertex{(v[0] + u[0]) / 2, (v[1] + u[1]) / 2}
+	}
+	return ret
+}
+
+// MorphOptions represents the options for WriteSVGMorph.
+type MorphOptions struct {
+	// Duration is the duration of the morphing from a to b. Zero means the
+	// default value of 1 second.
+	Duration time.Duration
+
+	// Once morphs from a to b only once and holds b. Otherwise, the shape
+	// morphs back and forth indefinitely.
+	Once bool
+}
+
+// WriteSVGMorph writes an SVG document in which the shape of a morphs into the
+// shape of b with the <animate> element on the d attribute. The Paths are made
+// compatible with MorphCompatible, and the 'd' strings are written with the
+// absolute coordinates and the same sequence of the commands, as the animation
+// of the d attribute requires. nil opts is the same as the zero value of
+// MorphOptions.
+func WriteSVGMorph(w io.Writer, a, b *Path, opts *MorphOptions) error {
+	if opts == nil {
+		opts = &MorphOptions{}
+	}
+	dur := opts.Duration
+	if dur == 0 {
+		dur = time.Second
+	}
+	fa, fb := MorphCompatible(a, b)
+	da, db := morphSVGD(fa), morphSVGD(fb)
+	f := FormatCoord(3, "s")
+
+	var sb strings.Builder
+	fmt.Fprintln(&sb, `<?xml version="1.0" encoding="utf-8"?>`)
+	fmt.Fprint(&sb, `<svg version="1.1" xmlns="http://www.w3.org/2000/svg"`)
+	fmt.Fprintf(&sb, ` viewBox="0 0 %s %s">`+"\n", defaultCoordFormatter(fa.Width), defaultCoordFormatter(fa.Height))
+	fmt.Fprintf(&sb, "<path d=\"%s\">\n", da)
+	if opts.Once {
+		fmt.Fprintf(&sb, "<animate attributeName=\"d\" dur=\"%s\" fill=\"freeze\" values=\"%s;%s\"/>\n", f(dur.Seconds()), da, db)
+	} else {
+		fmt.Fprintf(&sb, "<animate attributeName=\"d\" dur=\"%s\" repeatCount=\"indefinite\" values=\"%s;%s;%s\"/>\n", f(2*dur.Seconds()), da, db, da)
+	}
+	fmt.Fprintln(&sb, `</path>`)
+	fmt.Fprintln(&sb, `</svg>`)
+	if _, err := io.WriteString(w, sb.String()); err != nil {
+		return fmt.Errorf("write failure: %w", err)
+	}
+	return nil
+}
+
+// morphSVGD returns the 'd' string of fp consisting of the commands M, L, and Z
+// with the absolute coordinates.
+func morphSVGD(fp *FloatPath) string {
+	f := FormatCoord(3, "")
+	var sb strings.Builder
+	for _, vs := range fp.Vertices {
+		for i, v := range vs {
+			cmd := "L"
+			if i == 0 {
+				cmd = "M"
+			}
+			fmt.Fprintf(&sb, "%s%s,%s", cmd, f(v[0]), f(v[1]))
+		}
+		sb.WriteString("Z")
+	}
+	return sb.String()
+}
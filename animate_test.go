@@ -0,0 +1,81 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath_test
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tunabay/go-bitarray"
+	"github.com/tunabay/go-bmppath"
+)
+
+func ExampleMorphCompatible() {
+	frame := func(rows ...string) *bmppath.Path {
+		bmp := bitarray.NewBufferFromBitArray(bitarray.MustParse(strings.Join(rows, "")))
+		path, err := bmppath.New(bmp, len(rows[0]))
+		if err != nil {
+			panic(err)
+		}
+		return path
+	}
+	a := frame(
+		"1100",
+		"1100",
+		"0001",
+	)
+	b := frame(
+		"0000",
+		"1110",
+		"0000",
+	)
+
+	fa, fb := bmppath.MorphCompatible(a, b)
+	for i := 0; i < fa.NumPath(); i++ {
+		fmt.Println(fa.PathString(i))
+		fmt.Println(fb.PathString(i))
+	}
+	if err := bmppath.WriteSVGMorph(os.Stdout, a, b, &bmppath.MorphOptions{Duration: 500 * time.Millisecond}); err != nil {
+		panic(err)
+	}
+
+	// Output:
+	// (0, 0), (2, 0), (2, 2), (0, 2)
+	// (0, 1), (3, 1), (3, 2), (0, 2)
+	// (3, 2), (4, 2), (4, 3), (3, 3)
+	// (3.5, 2.5), (3.5, 2.5), (3.5, 2.5), (3.5, 2.5)
+	// <?xml version="1.0" encoding="utf-8"?>
+	// <svg version="1.1" xmlns="http://www.w3.org/2000/svg" viewBox="0 0 4 3">
+	// <path d="M0,0L2,0L2,2L0,2ZM3,2L4,2L4,3L3,3Z">
+	// <animate attributeName="d" dur="1s" repeatCount="indefinite" values="M0,0L2,0L2,2L0,2ZM3,2L4,2L4,3L3,3Z;M0,1L3,1L3,2L0,2ZM3.5,2.5L3.5,2.5L3.5,2.5L3.5,2.5Z;M0,0L2,0L2,2L0,2ZM3,2L4,2L4,3L3,3Z"/>
+	// </path>
+	// </svg>
+}
+
+func TestMorphCompatible(t *testing.T) {
+	a := &bmppath.Path{Width: 4, Height: 4, Vertices: [][]bmppath.Vertex{
+		{{0, 0}, {4, 0}, {4, 4}, {0, 4}},
+		{{1, 1}, {1, 3}, {3, 3}, {3, 1}},
+	}}
+	b := &bmppath.Path{Width: 4, Height: 4, Vertices: [][]bmppath.Vertex{
+		{{0, 0}, {2, 0}, {2, 1}, {3, 1}, {3, 3}, {0, 3}},
+	}}
+	fa, fb := bmppath.MorphCompatible(a, b)
+	if fa.NumPath() != 2 || fb.NumPath() != 2 {
+		t.Fatalf("unexpected number of paths: %d, %d", fa.NumPath(), fb.NumPath())
+	}
+	for i := 0; i < fa.NumPath(); i++ {
+		if fa.PathLen(i) != fb.PathLen(i) {
+			t.Errorf("path #%d: vertex counts differ: %d != %d", i, fa.PathLen(i), fb.PathLen(i))
+		}
+	}
+	// the hole of a disappears into its center
+	if got, want := fb.PathString(1), "(2, 2), (2, 2), (2, 2), (2, 2)"; got != want {
+		t.Errorf("unexpected collapsed path: got %s, want %s", got, want)
+	}
+}
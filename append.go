@@ -0,0 +1,56 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath
+
+// Rasterizer is the interface implemented by the path builders accepting the
+// closed paths in float32 coordinates, such as the Rasterizer of
+// golang.org/x/image/vector, which renders them with anti-aliasing.
+type Rasterizer interface {
+	MoveTo(x, y float32)
+	LineTo(x, y float32)
+	ClosePath()
+}
+
+// AppendTo adds the closed paths to the rasterizer r, scaled by scale and then
+// translated by (offsetX, offsetY), without the round trips through the string
+// representations such as SVG. The rasterizer of golang.org/x/image/vector
+// fills them with the nonzero winding rule, in the same way as WriteSVG. For
+// example, the following draws p at 4x with anti-aliasing:
+//
+//	z := vector.NewRasterizer(p.Width*4, p.Height*4)
+//	p.AppendTo(z, 4, 0, 0)
+//	z.Draw(dst, dst.Bounds(), image.Opaque, image.Point{})
+func (p *Path) AppendTo(r Rasterizer, scale, offsetX, offsetY float32) {
+	for _, vs := range p.Vertices {
+		for i, v := range vs {
+			x, y := offsetX+float32(v[0])*scale, offsetY+float32(v[1])*scale
+			if i == 0 {
+				r.MoveTo(x, y)
+				continue
+			}
+			r.LineTo(x, y)
+		}
+		if len(vs) != 0 {
+			r.ClosePath()
+		}
+	}
+}
+
+// AppendTo is identical to Path.AppendTo except that it adds fp.
+func (fp *FloatPath) AppendTo(r Rasterizer, scale, offsetX, offsetY float32) {
+	for _, vs := range fp.Vertices {
+		for i, v := range vs {
+			x, y := offsetX+float32(v[0])*scale, offsetY+float32(v[1])*scale
+			if i == 0 {
+				r.MoveTo(x, y)
+				continue
+			}
+			r.LineTo(x, y)
+		}
+		if len(vs) != 0 {
+			r.ClosePath()
+		}
+	}
+}
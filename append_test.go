@@ -0,0 +1,41 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath_test
+
+import (
+	"fmt"
+
+	"github.com/tunabay/go-bitarray"
+	"github.com/tunabay/go-bmppath"
+)
+
+// recorder is a bmppath.Rasterizer printing the calls.
+type recorder struct{}
+
+func (recorder) MoveTo(x, y float32) { fmt.Printf("MoveTo(%g, %g)\n", x, y) }
+func (recorder) LineTo(x, y float32) { fmt.Printf("LineTo(%g, %g)\n", x, y) }
+func (recorder) ClosePath()          { fmt.Println("ClosePath()") }
+
+func ExamplePath_AppendTo() {
+	bmp := bitarray.NewBufferFromBitArray(bitarray.MustParse("01101"))
+	path, err := bmppath.New(bmp, 5)
+	if err != nil {
+		panic(err)
+	}
+
+	path.AppendTo(recorder{}, 2.5, 10, 0.5)
+
+	// Output:
+	// MoveTo(12.5, 0.5)
+	// LineTo(17.5, 0.5)
+	// LineTo(17.5, 3)
+	// LineTo(12.5, 3)
+	// ClosePath()
+	// MoveTo(20, 0.5)
+	// LineTo(22.5, 0.5)
+	// LineTo(22.5, 3)
+	// LineTo(20, 3)
+	// ClosePath()
+}
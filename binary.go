@@ -0,0 +1,200 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidEncoding is the error thrown when the encoded data to decode is
+// broken, or when the Path cannot be encoded.
+var ErrInvalidEncoding = errors.New("invalid encoding")
+
+// appendBinary appends the compact binary representation of p to b. All the
+// integers are varints: the Width, the Height, the number of closed paths, and
+// for each closed path, the number of vertices followed by the coordinates of
+// the vertices, each of which is the difference from the previous vertex. The
+// first vertex of each closed path follows the first vertex of the previous
+// one. The labels follow them as the number of labels and the length-prefixed
+// strings. The Width and the Height must not be negative, and the differences
+// between the coordinates must fit in int64, otherwise it returns
+// ErrInvalidEncoding.
+func (p *Path) appendBinary(b []byte) ([]byte, error) {
+	if p.Width < 0 || p.Height < 0 {
+		return nil, fmt.Errorf("%w: negative size %dx%d", ErrInvalidEncoding, p.Width, p.Height)
+	}
+	b = appendUvarint(b, uint64(p.Width))
+	b = appendUvarint(b, uint64(p.Height))
+	b = appendUvarint(b, uint64(len(p.Vertices)))
+	var c, z Vertex
+	for _, vs := range p.Vertices {
+		b = appendUvarint(b, uint64(len(vs)))
+		c = z
+		for _, v := range vs {
+			dx, okx := subInt64(v[0], c[0])
+			dy, oky := subInt64(v[1], c[1])
+			if !okx || !oky {
+				return nil, fmt.Errorf("%w: vertex %v too far from %v", ErrInvalidEncoding, v, c)
+			}
+			b = appendVarint(b, dx)
+			b = appendVarint(b, dy)
+			c = v
+		}
+		if len(vs) != 0 {
+			z = vs[0]
+		}
+	}
+	b = appendUvarint(b, uint64(len(p.Labels)))
+	for _, l := range p.Labels {
+		b = appendUvarint(b, uint64(len(l)))
+		b = append(b, l...)
+	}
+	return b, nil
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface. The result
+// is the compact representation of p, where the vertices are encoded as the
+// varints of the differences from the previous vertices. It is usually much
+// smaller than the JSON representation, and faster to decode. It is the same
+// as EncodeBinary with CodecRaw. It returns ErrInvalidEncoding if the Width or
+// the Height is negative, which can be made by Translate.
+func (p *Path) MarshalBinary() ([]byte, error) {
+	return p.appendBinary(nil)
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface. It
+// decodes the data written by MarshalBinary into p, and returns
+// ErrInvalidEncoding if the data is broken.
+func (p *Path) UnmarshalBinary(data []byte) error {
+	ret, err := decodeBinary(data)
+	if err != nil {
+		return err
+	}
+	*p = *ret
+	return nil
+}
+
+// decodeBinary decodes the binary representation written by appendBinary.
+func decodeBinary(b []byte) (*Path, error) {
+	d := &binaryDecoder{b: b}
+	ret := &Path{Width: d.int(), Height: d.int()}
+	n := d.count()
+	ret.Vertices = make([][]Vertex, 0, n)
+	var c, z Vertex
+	for i := 0; i < n && d.err == nil; i++ {
+		vs := make([]Vertex, d.count())
+		c = z
+		for j := range vs {
+			c = Vertex{d.coord(c[0]), d.coord(c[1])}
+			vs[j] = c
+		}
+		if len(vs) != 0 {
+			z = vs[0]
+		}
+		ret.Vertices = append(ret.Vertices, vs)
+	}
+	if n := d.count(); n != 0 {
+		ret.Labels = make([]string, 0, n)
+		for i := 0; i < n && d.err == nil; i++ {
+			ret.Labels = append(ret.Labels, string(d.bytes(d.count())))
+		}
+	}
+	if d.err == nil && len(d.b) != 0 {
+		d.err = fmt.Errorf("%w: %d trailing bytes", ErrInvalidEncoding, len(d.b))
+	}
+	if d.err != nil {
+		return nil, d.err
+	}
+	return ret, nil
+}
+
+// subInt64 returns a - b in int64, and reports whether it fits in int64, which
+// can fail only on the 64-bit platforms.
+func subInt64(a, b int) (int64, bool) {
+	d := int64(a) - int64(b)
+	return d, (int64(b) <= int64(a)) == (0 <= d)
+}
+
+func appendUvarint(b []byte, v uint64) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	return append(b, buf[:binary.PutUvarint(buf[:], v)]...)
+}
+
+func appendVarint(b []byte, v int64) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	return append(b, buf[:binary.PutVarint(buf[:], v)]...)
+}
+
+// binaryDecoder reads the varints from b. Once an error occurs, it is kept in
+// err and all the subsequent reads return zero.
+type binaryDecoder struct {
+	b   []byte
+	err error
+}
+
+func (d *binaryDecoder) uint() uint64 {
+	if d.err != nil {
+		return 0
+	}
+	v, n := binary.Uvarint(d.b)
+	if n <= 0 {
+		d.err = fmt.Errorf("%w: broken varint", ErrInvalidEncoding)
+		return 0
+	}
+	d.b = d.b[n:]
+	return v
+}
+
+// coord reads a difference and returns the coordinate c moved by it. The
+// result must fit in int, which is narrower than the difference on the 32-bit
+// platforms.
+func (d *binaryDecoder) coord(c int) int {
+	if d.err != nil {
+		return 0
+	}
+	v, n := binary.Varint(d.b)
+	if n <= 0 {
+		d.err = fmt.Errorf("%w: broken varint", ErrInvalidEncoding)
+		return 0
+	}
+	d.b = d.b[n:]
+	r := int64(c) + v
+	if (0 <= v) != (int64(c) <= r) || int64(int(r)) != r {
+		d.err = fmt.Errorf("%w: coordinate out of range", ErrInvalidEncoding)
+		return 0
+	}
+	return int(r)
+}
+
+func (d *binaryDecoder) int() int {
+	v := d.uint()
+	if uint64(int(^uint(0)>>1)) < v {
+		d.err = fmt.Errorf("%w: value out of range", ErrInvalidEncoding)
+		return 0
+	}
+	return int(v)
+}
+
+// count reads a number of elements, each of which takes at least one byte, so
+// that broken data cannot cause huge allocations.
+func (d *binaryDecoder) count() int {
+	v := d.uint()
+	if uint64(len(d.b)) < v {
+		d.err = fmt.Errorf("%w: count %d exceeds the data", ErrInvalidEncoding, v)
+		return 0
+	}
+	return int(v)
+}
+
+func (d *binaryDecoder) bytes(n int) []byte {
+	if d.err != nil {
+		return nil
+	}
+	ret := d.b[:n]
+	d.b = d.b[n:]
+	return ret
+}
@@ -0,0 +1,151 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath_test
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/tunabay/go-bitarray"
+	"github.com/tunabay/go-bmppath"
+)
+
+var (
+	_ encoding.BinaryMarshaler   = (*bmppath.Path)(nil)
+	_ encoding.BinaryUnmarshaler = (*bmppath.Path)(nil)
+)
+
+func ExamplePath_MarshalBinary() {
+	bmp := bitarray.NewBufferFromBitArray(bitarray.MustParse("01101"))
+	path, err := bmppath.New(bmp, 5)
+	if err != nil {
+		panic(err)
+	}
+
+	data, err := path.MarshalBinary()
+	if err != nil {
+		panic(err)
+	}
+	fmt.Printf("% x\n", data)
+
+	var decoded bmppath.Path
+	if err := decoded.UnmarshalBinary(data); err != nil {
+		panic(err)
+	}
+	fmt.Println(decoded.Equal(path))
+
+	// Output:
+	// 05 01 02 04 02 00 04 00 00 02 03 00 04 06 00 02 00 00 02 01 00 00
+	// true
+}
+
+func TestPath_UnmarshalBinary(t *testing.T) {
+	bmp := bitarray.NewBufferFromByteSlice([]byte{
+		0x18, 0x3c, 0x7e, 0xdb, 0xff, 0x24, 0x5a, 0xa5,
+	})
+	path, err := bmppath.New(bmp, 8)
+	if err != nil {
+		t.Fatalf("New(): %v", err)
+	}
+	data, err := path.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary(): %v", err)
+	}
+	js, err := json.Marshal(path)
+	if err != nil {
+		t.Fatalf("Marshal(): %v", err)
+	}
+	if len(js) < len(data)*3 {
+		t.Errorf("binary %d bytes, JSON %d bytes", len(data), len(js))
+	}
+
+	var decoded bmppath.Path
+	if err := decoded.UnmarshalBinary(data); err != nil {
+		t.Fatalf("UnmarshalBinary(): %v", err)
+	}
+	if !decoded.Equal(path) {
+		t.Errorf("round trip failed: %s", bmppath.Diff(&decoded, path))
+	}
+	for _, broken := range [][]byte{data[:len(data)-1], append(data, 0), {0x80}} {
+		if err := decoded.UnmarshalBinary(broken); !errors.Is(err, bmppath.ErrInvalidEncoding) {
+			t.Errorf("% x: unexpected error: %v", broken, err)
+		}
+	}
+}
+
+func TestPath_MarshalBinary_negativeSize(t *testing.T) {
+	for _, p := range []*bmppath.Path{{Width: -1, Height: 2}, {Width: 2, Height: -1}} {
+		if _, err := p.MarshalBinary(); !errors.Is(err, bmppath.ErrInvalidEncoding) {
+			t.Errorf("%dx%d: unexpected error: %v", p.Width, p.Height, err)
+		}
+		if _, err := p.EncodeBinary(bmppath.CodecFlate); !errors.Is(err, bmppath.ErrInvalidEncoding) {
+			t.Errorf("%dx%d: unexpected error: %v", p.Width, p.Height, err)
+		}
+	}
+}
+
+func TestPath_UnmarshalBinary_coordinateRange(t *testing.T) {
+	// a closed path with the vertices (0, 0), (2^40, 0), (2^40, 1), (0, 1)
+	// on a canvas of 1x1, which can be written on the 64-bit platforms
+	var data []byte
+	var buf [binary.MaxVarintLen64]byte
+	for _, v := range []uint64{1, 1, 1, 4} {
+		data = append(data, buf[:binary.PutUvarint(buf[:], v)]...)
+	}
+	for _, v := range []int64{0, 0, 1 << 40, 0, 0, 1, -1 << 40, 0} {
+		data = append(data, buf[:binary.PutVarint(buf[:], v)]...)
+	}
+	data = append(data, 0)
+
+	var p bmppath.Path
+	err := p.UnmarshalBinary(data)
+	switch intSize {
+	case 32:
+		if !errors.Is(err, bmppath.ErrInvalidEncoding) {
+			t.Errorf("unexpected error: %v", err)
+		}
+	default:
+		if err != nil {
+			t.Fatalf("UnmarshalBinary(): %v", err)
+		}
+		if got := p.Vertices[0][1]; int64(got[0]) != 1<<40 || got[1] != 0 {
+			t.Errorf("unexpected vertex: %v", got)
+		}
+		enc, err := p.MarshalBinary()
+		if err != nil {
+			t.Fatalf("MarshalBinary(): %v", err)
+		}
+		if !bytes.Equal(enc, data) {
+			t.Errorf("unexpected encoding: % x", enc)
+		}
+	}
+
+	// the coordinate overflowing int64 on all the platforms
+	data = data[:0]
+	for _, v := range []uint64{1, 1, 1, 3} {
+		data = append(data, buf[:binary.PutUvarint(buf[:], v)]...)
+	}
+	for _, v := range []int64{math.MaxInt64, 0, 1, 0, 0, 1} {
+		data = append(data, buf[:binary.PutVarint(buf[:], v)]...)
+	}
+	data = append(data, 0)
+	if err := p.UnmarshalBinary(data); !errors.Is(err, bmppath.ErrInvalidEncoding) {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	if intSize == 64 {
+		const maxInt = int(^uint(0) >> 1)
+		far := &bmppath.Path{Width: 1, Height: 1, Vertices: [][]bmppath.Vertex{{{-maxInt, 0}, {maxInt, 0}, {maxInt, 1}}}}
+		if _, err := far.MarshalBinary(); !errors.Is(err, bmppath.ErrInvalidEncoding) {
+			t.Errorf("unexpected error: %v", err)
+		}
+	}
+}
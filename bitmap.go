@@ -0,0 +1,56 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath
+
+import (
+	"github.com/tunabay/go-bitarray"
+)
+
+// bitmap is a monochrome bitmap image used for the preprocessing. It holds one
+// bool per pixel for fast random access.
+type bitmap struct {
+	width, height int
+	pix           []bool
+}
+
+func newBitmap(bm *bitarray.Buffer, width, height int) *bitmap {
+	img := &bitmap{width: width, height: height, pix: make([]bool, width*height)}
+	for i := range img.pix {
+		img.pix[i] = bm.BitAt(i) != 0
+	}
+	return img
+}
+
+func (img *bitmap) buffer() *bitarray.Buffer {
+	bm := bitarray.NewBuffer(img.width * img.height)
+	for i, b := range img.pix {
+		if b {
+			bm.PutBitAt(i, 1)
+		}
+	}
+	return bm
+}
+
+// at returns the pixel at (x, y). The pixels outside the image are treated as
+// background.
+func (img *bitmap) at(x, y int) bool {
+	if x < 0 || y < 0 || img.width <= x || img.height <= y {
+		return false
+	}
+	return img.pix[img.width*y+x]
+}
+
+// upscale returns a new bitmap magnified by the integer factor k with the
+// nearest neighbor interpolation.
+func (img *bitmap) upscale(k int) *bitmap {
+	w, h := img.width*k, img.height*k
+	ret := &bitmap{width: w, height: h, pix: make([]bool, w*h)}
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			ret.pix[w*y+x] = img.pix[img.width*(y/k)+x/k]
+		}
+	}
+	return ret
+}
@@ -0,0 +1,138 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath
+
+import (
+	"sort"
+)
+
+// Union returns a new Path that covers the area covered by either p or q.
+func (p *Path) Union(q *Path) *Path {
+	return p.boolean(q, func(a, b byte) byte { return a | b })
+}
+
+// Intersect returns a new Path that covers the area covered by both p and q.
+func (p *Path) Intersect(q *Path) *Path {
+	return p.boolean(q, func(a, b byte) byte { return a & b })
+}
+
+// Subtract returns a new Path that covers the area covered by p but not by q.
+func (p *Path) Subtract(q *Path) *Path {
+	return p.boolean(q, func(a, b byte) byte { return a &^ b })
+}
+
+// Xor returns a new Path that covers the area covered by exactly one of p and
+// q.
+func (p *Path) Xor(q *Path) *Path {
+	return p.boolean(q, func(a, b byte) byte { return a ^ b })
+}
+
+// boolean performs the boolean operation op between p and q, filling the
+// closed paths with the even-odd rule. Since all the edges of both paths are
+// axis-aligned, the operation is done by a sweep over the vertical edges from
+// left to right rather than by general polygon clipping, keeping the coverage
+// of p and q on the intervals between the distinct y coordinates of the
+// vertical edges. Therefore, it takes O(m*n) time for m distinct x coordinates
+// and n distinct y coordinates, regardless of the area of the paths. The size
+// of the result is large enough to contain both canvases.
+func (p *Path) boolean(q *Path, op func(a, b byte) byte) *Path {
+	width, height := p.Width, p.Height
+	if width < q.Width {
+		width = q.Width
+	}
+	if height < q.Height {
+		height = q.Height
+	}
+
+	type vEdge struct {
+		x, y0, y1 int
+		q         bool
+	}
+	var edges []vEdge
+	var ys []int
+	for i, vss := range [2][][]Vertex{p.Vertices, q.Vertices} {
+		for _, vs := range vss {
+			for j, v0 := range vs {
+				v1 := vs[(j+1)%len(vs)]
+				if v0[0] != v1[0] || v0[1] == v1[1] {
+					continue
+				}
+				y0, y1 := v0[1], v1[1]
+				if y1 < y0 {
+					y0, y1 = y1, y0
+				}
+				edges = append(edges, vEdge{x: v0[0], y0: y0, y1: y1, q: i == 1})
+				ys = append(ys, y0, y1)
+			}
+		}
+	}
+	if len(edges) == 0 {
+		return &Path{Width: width, Height: height}
+	}
+	sort.Slice(edges, func(i, j int) bool { return edges[i].x < edges[j].x })
+	sort.Ints(ys)
+	n := 0
+	for _, y := range ys {
+		if n == 0 || ys[n-1] != y {
+			ys[n] = y
+			n++
+		}
+	}
+	ys = ys[:n]
+
+	// the coverage of p, q, and the result on the interval ys[k]-ys[k+1]
+	pc, qc := make([]byte, n-1), make([]byte, n-1)
+	prev, cur := make([]byte, n-1), make([]byte, n-1)
+	l := newLinker()
+	for i := 0; i < len(edges); {
+		x := edges[i].x
+		for ; i < len(edges) && edges[i].x == x; i++ {
+			e := edges[i]
+			c := pc
+			if e.q {
+				c = qc
+			}
+			for k := sort.SearchInts(ys, e.y0); ys[k] < e.y1; k++ {
+				c[k] ^= 1
+			}
+		}
+		for k := range cur {
+			// the coverage beyond the last edge is cleared, so that
+			// the edges are always closed even if some of the
+			// closed paths are malformed
+			cur[k] = 0
+			if i < len(edges) {
+				cur[k] = op(pc[k], qc[k]) & 1
+			}
+			switch {
+			case cur[k] == prev[k]:
+			case cur[k] != 0:
+				l.addSegment(Vertex{x, ys[k+1]}, Vertex{x, ys[k]}, 0)
+			default:
+				l.addSegment(Vertex{x, ys[k]}, Vertex{x, ys[k+1]}, 2)
+			}
+		}
+		if i < len(edges) {
+			nx := edges[i].x
+			for k, y := range ys {
+				above := 0 < k && cur[k-1] != 0
+				below := k < n-1 && cur[k] != 0
+				switch {
+				case below && !above:
+					l.addSegment(Vertex{x, y}, Vertex{nx, y}, 1)
+				case above && !below:
+					l.addSegment(Vertex{nx, y}, Vertex{x, y}, 3)
+				}
+			}
+		}
+		prev, cur = cur, prev
+	}
+
+	ret, err := l.link(width, height)
+	if err != nil {
+		panic("bmppath: " + err.Error())
+	}
+	return ret
+}
@@ -0,0 +1,102 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath_test
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/tunabay/go-bitarray"
+	"github.com/tunabay/go-bmppath"
+)
+
+func ExamplePath_Subtract() {
+	frame, err := bmppath.New(bitarray.NewBufferFromBitArray(
+		bitarray.MustParse(strings.Repeat("1", 16)),
+	), 4)
+	if err != nil {
+		panic(err)
+	}
+	logo, err := bmppath.New(bitarray.NewBufferFromBitArray(
+		bitarray.MustParse("1111"),
+	), 2)
+	if err != nil {
+		panic(err)
+	}
+	logo = logo.Translate(1, 1)
+
+	fmt.Println(frame.Subtract(logo).SVGDString())
+	fmt.Println(frame.Intersect(logo).SVGDString())
+	fmt.Println(logo.Union(logo.Translate(1, 1)).SVGDString())
+	fmt.Println(logo.Xor(logo.Translate(1, 0)).SVGDString())
+
+	// Output:
+	// m0,0h4v4h-4zm1,1v2h2v-2z
+	// m1,1h2v2h-2z
+	// m1,1h2v1h1v2h-2v-1h-1z
+	// m1,1h1v2h-1zm2,0h1v2h-1z
+}
+
+func TestPath_Union_random(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	random := func() *bmppath.Path {
+		w, h := 1+r.Intn(12), 1+r.Intn(12)
+		bmp := bitarray.NewBuffer(w * h)
+		for i := 0; i < w*h; i++ {
+			bmp.PutBitAt(i, byte(r.Intn(2)))
+		}
+		p, err := bmppath.New(bmp, w)
+		if err != nil {
+			t.Fatalf("New(): %v", err)
+		}
+		return p.Translate(r.Intn(5), r.Intn(5))
+	}
+	// at returns the pixel of p at (x, y) rasterized by p itself
+	at := func(p *bmppath.Path, bmp *bitarray.Buffer, x, y int) byte {
+		if p.Width <= x || p.Height <= y {
+			return 0
+		}
+		return bmp.BitAt(p.Width*y + x)
+	}
+	ops := []struct {
+		name string
+		f    func(p, q *bmppath.Path) *bmppath.Path
+		op   func(a, b byte) byte
+	}{
+		{"Union", (*bmppath.Path).Union, func(a, b byte) byte { return a | b }},
+		{"Intersect", (*bmppath.Path).Intersect, func(a, b byte) byte { return a & b }},
+		{"Subtract", (*bmppath.Path).Subtract, func(a, b byte) byte { return a &^ b }},
+		{"Xor", (*bmppath.Path).Xor, func(a, b byte) byte { return a ^ b }},
+	}
+	for n := 0; n < 100; n++ {
+		p, q := random(), random()
+		pb, qb := p.Rasterize(), q.Rasterize()
+		width, height := p.Width, p.Height
+		if width < q.Width {
+			width = q.Width
+		}
+		if height < q.Height {
+			height = q.Height
+		}
+		for _, o := range ops {
+			bmp := bitarray.NewBuffer(width * height)
+			for y := 0; y < height; y++ {
+				for x := 0; x < width; x++ {
+					bmp.PutBitAt(width*y+x, o.op(at(p, pb, x, y), at(q, qb, x, y)))
+				}
+			}
+			want, err := bmppath.New(bmp, width)
+			if err != nil {
+				t.Fatalf("New(): %v", err)
+			}
+			if got := o.f(p, q); !reflect.DeepEqual(got, want) {
+				t.Errorf("#%d: %s: unexpected result:\n%s", n, o.name, bmppath.Diff(want, got))
+			}
+		}
+	}
+}
@@ -0,0 +1,70 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// WritePath2D writes the closed paths as a JavaScript snippet constructing a
+// Path2D object of the HTML canvas, assigned to the constant name, with the
+// moveTo, lineTo, and closePath methods. If name is empty, "path" is used. The
+// result can be drawn with ctx.fill(path), where the default nonzero winding
+// rule fills the paths in the same way as WriteSVG.
+func (p *Path) WritePath2D(w io.Writer, name string) error {
+	if name == "" {
+		name = "path"
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "const %s = new Path2D();\n", name)
+	for _, vs := range p.Vertices {
+		for i, v := range vs {
+			op := "lineTo"
+			if i == 0 {
+				op = "moveTo"
+			}
+			fmt.Fprintf(&sb, "%s.%s(%d, %d);\n", name, op, v[0], v[1])
+		}
+		if len(vs) != 0 {
+			fmt.Fprintf(&sb, "%s.closePath();\n", name)
+		}
+	}
+	if _, err := io.WriteString(w, sb.String()); err != nil {
+		return fmt.Errorf("write failure: %w", err)
+	}
+	return nil
+}
+
+// WriteCanvasJSON writes the closed paths as a JSON array of the drawing
+// commands, ["M", x, y] for moveTo, ["L", x, y] for lineTo, and ["Z"] for
+// closePath, which the web applications can replay on a canvas context or a
+// Path2D object without parsing the SVG path data.
+func (p *Path) WriteCanvasJSON(w io.Writer) error {
+	var sb strings.Builder
+	sb.WriteString("[")
+	for _, vs := range p.Vertices {
+		if len(vs) == 0 {
+			continue
+		}
+		for i, v := range vs {
+			cmd := "L"
+			if i == 0 {
+				cmd = "M"
+			}
+			if sb.Len() != 1 {
+				sb.WriteString(",")
+			}
+			fmt.Fprintf(&sb, `["%s",%d,%d]`, cmd, v[0], v[1])
+		}
+		sb.WriteString(`,["Z"]`)
+	}
+	sb.WriteString("]\n")
+	if _, err := io.WriteString(w, sb.String()); err != nil {
+		return fmt.Errorf("write failure: %w", err)
+	}
+	return nil
+}
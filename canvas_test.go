@@ -0,0 +1,52 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath_test
+
+import (
+	"os"
+
+	"github.com/tunabay/go-bitarray"
+	"github.com/tunabay/go-bmppath"
+)
+
+func ExamplePath_WritePath2D() {
+	bmp := bitarray.NewBufferFromBitArray(bitarray.MustParse("01101"))
+	path, err := bmppath.New(bmp, 5)
+	if err != nil {
+		panic(err)
+	}
+
+	if err := path.WritePath2D(os.Stdout, "glyph"); err != nil {
+		panic(err)
+	}
+
+	// Output:
+	// const glyph = new Path2D();
+	// glyph.moveTo(1, 0);
+	// glyph.lineTo(3, 0);
+	// glyph.lineTo(3, 1);
+	// glyph.lineTo(1, 1);
+	// glyph.closePath();
+	// glyph.moveTo(4, 0);
+	// glyph.lineTo(5, 0);
+	// glyph.lineTo(5, 1);
+	// glyph.lineTo(4, 1);
+	// glyph.closePath();
+}
+
+func ExamplePath_WriteCanvasJSON() {
+	bmp := bitarray.NewBufferFromBitArray(bitarray.MustParse("01101"))
+	path, err := bmppath.New(bmp, 5)
+	if err != nil {
+		panic(err)
+	}
+
+	if err := path.WriteCanvasJSON(os.Stdout); err != nil {
+		panic(err)
+	}
+
+	// Output:
+	// [["M",1,0],["L",3,0],["L",3,1],["L",1,1],["Z"],["M",4,0],["L",5,0],["L",5,1],["L",4,1],["Z"]]
+}
@@ -0,0 +1,239 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+)
+
+// ErrNotFound is the error thrown when the requested item does not exist.
+var ErrNotFound = errors.New("not found")
+
+// catalogMagic is the signature at the beginning of the catalog files.
+const catalogMagic = "BMPC\x01"
+
+// Catalog is a collection of named Paths, such as the traced assets of games
+// or icon systems, which can be stored in a single file. The Paths in a
+// Catalog opened with OpenCatalog are decoded lazily when they are requested
+// for the first time. A Catalog is not safe for concurrent use.
+//
+// The catalog file consists of the signature "BMPC\x01", the index, and the
+// data. The index is the number of entries followed by the entries, each of
+// which is the length-prefixed name, the offset from the beginning of the
+// data, and the length of the encoded Path, all as varints. The Paths are
+// encoded in the compact binary representation.
+type Catalog struct {
+	names   []string
+	entries map[string]*catalogEntry
+}
+
+type catalogEntry struct {
+	path   *Path
+	r      io.ReaderAt
+	offset int64
+	length int64
+}
+
+// NewCatalog creates an empty Catalog.
+func NewCatalog() *Catalog {
+	return &Catalog{entries: make(map[string]*catalogEntry)}
+}
+
+// OpenCatalog opens the catalog file read from r. Only the index is read at
+// this point, and each Path is read from r when it is requested. Therefore r
+// must remain readable while the Catalog is used.
+func OpenCatalog(r io.ReaderAt) (*Catalog, error) {
+	cr := &countingReader{r: bufio.NewReader(io.NewSectionReader(r, 0, 1<<62))}
+	magic := make([]byte, len(catalogMagic))
+	if _, err := io.ReadFull(cr, magic); err != nil || string(magic) != catalogMagic {
+		return nil, fmt.Errorf("%w: not a catalog", ErrInvalidEncoding)
+	}
+	readUint := func() (int64, error) {
+		v, err := binary.ReadUvarint(cr)
+		if err != nil || 1<<62 < v {
+			return 0, fmt.Errorf("%w: broken index", ErrInvalidEncoding)
+		}
+		return int64(v), nil
+	}
+	n, err := readUint()
+	if err != nil {
+		return nil, err
+	}
+	c := NewCatalog()
+	var entries []*catalogEntry
+	for i := int64(0); i < n; i++ {
+		l, err := readUint()
+		if err != nil {
+			return nil, err
+		}
+		name, err := readN(cr, l)
+		if err != nil {
+			return nil, fmt.Errorf("%w: broken index", ErrInvalidEncoding)
+		}
+		e := &catalogEntry{r: r}
+		if e.offset, err = readUint(); err != nil {
+			return nil, err
+		}
+		if e.length, err = readUint(); err != nil {
+			return nil, err
+		}
+		if _, dup := c.entries[string(name)]; dup {
+			return nil, fmt.Errorf("%w: duplicate name %q", ErrInvalidEncoding, name)
+		}
+		c.names = append(c.names, string(name))
+		c.entries[string(name)] = e
+		entries = append(entries, e)
+	}
+	for _, e := range entries {
+		e.offset += cr.n
+	}
+	sort.Strings(c.names)
+	return c, nil
+}
+
+// Len returns the number of Paths in the Catalog.
+func (c *Catalog) Len() int { return len(c.names) }
+
+// Names returns the names of the Paths in the Catalog in sorted order.
+func (c *Catalog) Names() []string { return append([]string(nil), c.names...) }
+
+// Add adds the Path p to the Catalog with the name name, replacing the existing
+// one with the same name. p is not copied, so it must not be modified while it
+// is in the Catalog.
+func (c *Catalog) Add(name string, p *Path) {
+	if _, ok := c.entries[name]; !ok {
+		i := sort.SearchStrings(c.names, name)
+		c.names = append(c.names, "")
+		copy(c.names[i+1:], c.names[i:])
+		c.names[i] = name
+	}
+	c.entries[name] = &catalogEntry{path: p}
+}
+
+// Get returns the Path with the name name. If the Path has not been loaded
+// yet, it is read and decoded. It returns ErrNotFound if there is no such
+// Path.
+func (c *Catalog) Get(name string) (*Path, error) {
+	e, ok := c.entries[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrNotFound, name)
+	}
+	if e.path != nil {
+		return e.path, nil
+	}
+	b, err := readN(io.NewSectionReader(e.r, e.offset, e.length), e.length)
+	switch {
+	case errors.Is(err, io.EOF):
+		return nil, fmt.Errorf("%w: %q: truncated data", ErrInvalidEncoding, name)
+	case err != nil:
+		return nil, fmt.Errorf("read failure: %q: %w", name, err)
+	}
+	p, err := decodeBinary(b)
+	if err != nil {
+		return nil, fmt.Errorf("%q: %w", name, err)
+	}
+	e.path, e.r = p, nil
+	return p, nil
+}
+
+// WriteTo writes the Catalog as a catalog file to w. All the Paths not loaded
+// yet are loaded. It implements the io.WriterTo interface.
+func (c *Catalog) WriteTo(w io.Writer) (int64, error) {
+	var index, data []byte
+	index = append(index, catalogMagic...)
+	index = appendUvarint(index, uint64(len(c.names)))
+	for _, name := range c.names {
+		p, err := c.Get(name)
+		if err != nil {
+			return 0, err
+		}
+		offset := len(data)
+		if data, err = p.appendBinary(data); err != nil {
+			return 0, fmt.Errorf("%q: %w", name, err)
+		}
+		index = appendUvarint(index, uint64(len(name)))
+		index = append(index, name...)
+		index = appendUvarint(index, uint64(offset))
+		index = appendUvarint(index, uint64(len(data)-offset))
+	}
+	n, err := w.Write(index)
+	if err != nil {
+		return int64(n), fmt.Errorf("write failure: %w", err)
+	}
+	m, err := w.Write(data)
+	if err != nil {
+		return int64(n + m), fmt.Errorf("write failure: %w", err)
+	}
+	return int64(n + m), nil
+}
+
+// Export writes each Path in the Catalog into its own file in the directory
+// dir, named after the name of the Path. The format is specified by
+// opts.Format, and the other fields of opts are ignored. nil opts is the same
+// as the zero value of ExportOptions. It returns ErrInvalidName without writing
+// any files if a name contains a path separator or "..", which could write the
+// file outside dir.
+func (c *Catalog) Export(dir string, opts *ExportOptions) error {
+	if opts == nil {
+		opts = &ExportOptions{}
+	}
+	for _, name := range c.names {
+		if err := checkFileName(name); err != nil {
+			return err
+		}
+	}
+	write, ext := opts.Format.writer(nil)
+	for _, name := range c.names {
+		p, err := c.Get(name)
+		if err != nil {
+			return err
+		}
+		if err := writeFile(filepath.Join(dir, name+ext), func(w io.Writer) error {
+			return write(p, w)
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readN reads n bytes from r. The buffer grows as the bytes are read, rather
+// than being allocated for n bytes at once, so that broken data cannot cause
+// huge allocations. It returns io.EOF if r ends before n bytes.
+func readN(r io.Reader, n int64) ([]byte, error) {
+	var buf bytes.Buffer
+	m, err := io.CopyN(&buf, r, n)
+	if m < n && err == nil {
+		err = io.EOF
+	}
+	return buf.Bytes(), err
+}
+
+// countingReader counts the bytes read from r.
+type countingReader struct {
+	r *bufio.Reader
+	n int64
+}
+
+func (cr *countingReader) Read(b []byte) (int, error) {
+	n, err := cr.r.Read(b)
+	cr.n += int64(n)
+	return n, err
+}
+
+func (cr *countingReader) ReadByte() (byte, error) {
+	b, err := cr.r.ReadByte()
+	if err == nil {
+		cr.n++
+	}
+	return b, err
+}
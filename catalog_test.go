@@ -0,0 +1,149 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath_test
+
+import (
+	"bytes"
+	"errors"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tunabay/go-bitarray"
+	"github.com/tunabay/go-bmppath"
+)
+
+func TestCatalog(t *testing.T) {
+	invader, err := bmppath.New(bitarray.NewBufferFromByteSlice([]byte{
+		0b_10000001, 0b_01000010, 0b_00111100, 0b_01111110,
+		0b_11011011, 0b_01111110, 0b_00100100, 0b_11000011,
+	}), 8)
+	if err != nil {
+		t.Fatalf("New(): %v", err)
+	}
+	invader.Labels = []string{"body", "", "eye"}
+	dot, err := bmppath.New(bitarray.NewBufferFromBitArray(bitarray.MustParse("0110")), 2)
+	if err != nil {
+		t.Fatalf("New(): %v", err)
+	}
+
+	c := bmppath.NewCatalog()
+	c.Add("invader", invader)
+	c.Add("dot", dot)
+	c.Add("empty", &bmppath.Path{Width: 3, Height: 4})
+	var buf bytes.Buffer
+	if _, err := c.WriteTo(&buf); err != nil {
+		t.Fatalf("WriteTo(): %v", err)
+	}
+
+	opened, err := bmppath.OpenCatalog(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("OpenCatalog(): %v", err)
+	}
+	if got := opened.Names(); len(got) != 3 || got[0] != "dot" || got[1] != "empty" || got[2] != "invader" {
+		t.Errorf("unexpected names: %q", got)
+	}
+	for _, name := range c.Names() {
+		want, _ := c.Get(name)
+		got, err := opened.Get(name)
+		if err != nil {
+			t.Fatalf("Get(%q): %v", name, err)
+		}
+		if !got.Equal(want) {
+			t.Errorf("%s: unexpected path:\n%s", name, bmppath.Diff(want, got))
+		}
+	}
+	if got, _ := opened.Get("invader"); got.PathLabel(2) != "eye" {
+		t.Errorf("unexpected labels: %q", got.Labels)
+	}
+	if _, err := opened.Get("ufo"); !errors.Is(err, bmppath.ErrNotFound) {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	b := buf.Bytes()
+	broken, err := bmppath.OpenCatalog(bytes.NewReader(b[:len(b)-1]))
+	if err != nil {
+		t.Fatalf("OpenCatalog(): %v", err)
+	}
+	if _, err := broken.Get("invader"); err == nil {
+		t.Error("no error for truncated data")
+	}
+	if _, err := bmppath.OpenCatalog(bytes.NewReader([]byte("BMPD"))); !errors.Is(err, bmppath.ErrInvalidEncoding) {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	dir := t.TempDir()
+	if err := opened.Export(dir, &bmppath.ExportOptions{Format: bmppath.ExportDXF}); err != nil {
+		t.Fatalf("Export(): %v", err)
+	}
+	for _, name := range []string{"dot.dxf", "empty.dxf", "invader.dxf"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Errorf("missing file: %v", err)
+		}
+	}
+}
+
+func TestOpenCatalog_corrupt(t *testing.T) {
+	c := bmppath.NewCatalog()
+	c.Add("dot", &bmppath.Path{Width: 1, Height: 1, Vertices: [][]bmppath.Vertex{{{0, 0}, {1, 0}, {1, 1}, {0, 1}}}})
+	var buf bytes.Buffer
+	if _, err := c.WriteTo(&buf); err != nil {
+		t.Fatalf("WriteTo(): %v", err)
+	}
+	valid := buf.Bytes()
+
+	// the huge lengths are rejected rather than allocated
+	for _, b := range []string{
+		"BMPC\x01\x01\xff\xff\xff\xff\xff\xff\xff\xff\x3f",
+		"BMPC\x01\x01\x01a\x00\xff\xff\xff\xff\xff\xff\xff\xff\x3f",
+		"BMPC\x01\xff\xff\xff\xff\xff\xff\xff\xff\x3f",
+	} {
+		opened, err := bmppath.OpenCatalog(strings.NewReader(b))
+		if err != nil {
+			if !errors.Is(err, bmppath.ErrInvalidEncoding) {
+				t.Errorf("%q: unexpected error: %v", b, err)
+			}
+			continue
+		}
+		for _, name := range opened.Names() {
+			if _, err := opened.Get(name); !errors.Is(err, bmppath.ErrInvalidEncoding) {
+				t.Errorf("%q: Get(%q): unexpected error: %v", b, name, err)
+			}
+		}
+	}
+
+	// the randomly corrupted catalogs do not panic
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 10000; i++ {
+		b := append([]byte(nil), valid[:r.Intn(len(valid)+1)]...)
+		for j := r.Intn(4); 0 < j && 0 < len(b); j-- {
+			b[r.Intn(len(b))] = byte(r.Intn(256))
+		}
+		opened, err := bmppath.OpenCatalog(bytes.NewReader(b))
+		if err != nil {
+			continue
+		}
+		for _, name := range opened.Names() {
+			_, _ = opened.Get(name)
+		}
+	}
+}
+
+func TestCatalog_Export_invalidName(t *testing.T) {
+	for _, name := range []string{"../escape", "a/b", `a\b`, ".."} {
+		c := bmppath.NewCatalog()
+		c.Add("ok", &bmppath.Path{Width: 1, Height: 1})
+		c.Add(name, &bmppath.Path{Width: 1, Height: 1})
+		dir := t.TempDir()
+		if err := c.Export(dir, nil); !errors.Is(err, bmppath.ErrInvalidName) {
+			t.Errorf("%q: unexpected error: %v", name, err)
+		}
+		if files, _ := os.ReadDir(dir); len(files) != 0 {
+			t.Errorf("%q: files written: %d", name, len(files))
+		}
+	}
+}
@@ -0,0 +1,56 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// WriteSVGClipPath writes the vectorized bitmap image as an SVG <clipPath>
+// element with the id attribute id, as a fragment to be embedded in other SVG
+// documents. The clipping path can be applied to any element with the
+// attribute clip-path="url(#id)". The coordinates are in the user space of the
+// referencing element, so that it is usually necessary to scale the path with
+// the 'transform' attribute of the element or of the enclosing group.
+func (p *Path) WriteSVGClipPath(w io.Writer, id string) error {
+	return p.writeSVGFragment(w, `<clipPath id="%s"><path d="`, `"/></clipPath>`, id)
+}
+
+// WriteSVGMask is identical to WriteSVGClipPath except that it writes a <mask>
+// element, which is applied with the attribute mask="url(#id)". The inside of
+// the paths is white, and the outside is transparent. The mask region covers
+// the canvas of p.
+func (p *Path) WriteSVGMask(w io.Writer, id string) error {
+	head := fmt.Sprintf(
+		`<mask id="%%s" maskUnits="userSpaceOnUse" x="0" y="0" width="%d" height="%d"><path fill="#fff" d="`,
+		p.Width, p.Height,
+	)
+	return p.writeSVGFragment(w, head, `"/></mask>`, id)
+}
+
+// writeSVGFragment writes the 'd' string of p enclosed by head, with the
+// escaped id substituted for %s, and tail.
+func (p *Path) writeSVGFragment(w io.Writer, head, tail, id string) error {
+	if _, err := fmt.Fprintf(w, head, escapeAttr(id)); err != nil {
+		return fmt.Errorf("write failure: %w", err)
+	}
+	if err := p.WriteSVGD(w); err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintln(w, tail); err != nil {
+		return fmt.Errorf("write failure: %w", err)
+	}
+	return nil
+}
+
+// escapeAttr returns s escaped to be a value of an XML attribute.
+func escapeAttr(s string) string {
+	var sb strings.Builder
+	_ = xml.EscapeText(&sb, []byte(s))
+	return sb.String()
+}
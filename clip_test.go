@@ -0,0 +1,34 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath_test
+
+import (
+	"os"
+	"strings"
+
+	"github.com/tunabay/go-bitarray"
+	"github.com/tunabay/go-bmppath"
+)
+
+func ExamplePath_WriteSVGClipPath() {
+	bmp := bitarray.NewBufferFromBitArray(
+		bitarray.MustParse(strings.Join([]string{
+			"111",
+			"101",
+			"111",
+		}, "")),
+	)
+	path, err := bmppath.New(bmp, 3)
+	if err != nil {
+		panic(err)
+	}
+
+	_ = path.WriteSVGClipPath(os.Stdout, "logo")
+	_ = path.WriteSVGMask(os.Stdout, "logo-mask")
+
+	// Output:
+	// <clipPath id="logo"><path d="m0,0h3v3h-3zm1,1v1h1v-1z"/></clipPath>
+	// <mask id="logo-mask" maskUnits="userSpaceOnUse" x="0" y="0" width="3" height="3"><path fill="#fff" d="m0,0h3v3h-3zm1,1v1h1v-1z"/></mask>
+}
@@ -0,0 +1,102 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ClipPathUnit specifies the unit of the coordinates of the CSS clip-path.
+type ClipPathUnit int
+
+const (
+	// ClipPathPixels writes the coordinates in pixels, such as "3px". The
+	// shape is clipped at the original size regardless of the size of the
+	// element.
+	ClipPathPixels ClipPathUnit = iota
+
+	// ClipPathPercent writes the coordinates in percentages of the canvas
+	// size, such as "37.5%", so that the shape scales with the element.
+	ClipPathPercent
+)
+
+// ClipPathOptions represents the options for ClipPath and WriteClipPath.
+type ClipPathOptions struct {
+	// Unit is the unit of the coordinates.
+	Unit ClipPathUnit
+
+	// Holes also includes the holes, which are otherwise omitted so that
+	// the element is clipped to the outer contours only.
+	Holes bool
+}
+
+// ClipPath returns the CSS basic shape polygon() of the outer contours of p,
+// which can be used as the value of the clip-path property to clip the HTML
+// elements to the traced shape without inline SVG. Since polygon() takes only
+// a single list of the vertices, the closed paths are joined by the bridges
+// going back and forth along the same line to the first vertex, which enclose
+// no area. The default nonzero fill rule of polygon() fills the joined paths in
+// the same way as WriteSVG. If p has no closed path, the degenerate polygon
+// clipping out the whole element is returned. nil opts is the same as the zero
+// value of ClipPathOptions.
+func (p *Path) ClipPath(opts *ClipPathOptions) string {
+	if opts == nil {
+		opts = &ClipPathOptions{}
+	}
+	pt := func(v Vertex) string {
+		return fmt.Sprintf("%dpx %dpx", v[0], v[1])
+	}
+	if opts.Unit == ClipPathPercent {
+		f := FormatCoord(3, "%")
+		pt = func(v Vertex) string {
+			x, y := 0.0, 0.0
+			if p.Width != 0 {
+				x = float64(v[0]) * 100 / float64(p.Width)
+			}
+			if p.Height != 0 {
+				y = float64(v[1]) * 100 / float64(p.Height)
+			}
+			return f(x) + " " + f(y)
+		}
+	}
+	var pts []string
+	var first, prev []Vertex
+	for _, vs := range p.Vertices {
+		if len(vs) == 0 || (!opts.Holes && signedArea(vs) < 0) {
+			continue
+		}
+		if first == nil {
+			first = vs
+		} else {
+			pts = append(pts, pt(prev[0]))
+			if &prev[0] != &first[0] {
+				pts = append(pts, pt(first[0]))
+			}
+		}
+		for _, v := range vs {
+			pts = append(pts, pt(v))
+		}
+		prev = vs
+	}
+	switch {
+	case first == nil:
+		return "polygon(0 0)"
+	case &prev[0] != &first[0]:
+		pts = append(pts, pt(prev[0]))
+	}
+	return "polygon(" + strings.Join(pts, ", ") + ")"
+}
+
+// WriteClipPath writes the CSS declaration of the clip-path property with the
+// value returned by ClipPath. nil opts is the same as the zero value of
+// ClipPathOptions.
+func (p *Path) WriteClipPath(w io.Writer, opts *ClipPathOptions) error {
+	if _, err := fmt.Fprintf(w, "clip-path: %s;\n", p.ClipPath(opts)); err != nil {
+		return fmt.Errorf("write failure: %w", err)
+	}
+	return nil
+}
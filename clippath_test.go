@@ -0,0 +1,47 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath_test
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/tunabay/go-bitarray"
+	"github.com/tunabay/go-bmppath"
+)
+
+func ExamplePath_ClipPath() {
+	bmp := bitarray.NewBufferFromBitArray(
+		bitarray.MustParse(strings.Join([]string{
+			"11100",
+			"10100",
+			"11101",
+		}, "")),
+	)
+	path, err := bmppath.New(bmp, 5)
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Println(path.ClipPath(nil))
+	fmt.Println(path.ClipPath(&bmppath.ClipPathOptions{Unit: bmppath.ClipPathPercent}))
+	if err := path.WriteClipPath(os.Stdout, &bmppath.ClipPathOptions{Holes: true}); err != nil {
+		panic(err)
+	}
+
+	// Output:
+	// polygon(0px 0px, 3px 0px, 3px 3px, 0px 3px, 0px 0px, 4px 2px, 5px 2px, 5px 3px, 4px 3px, 4px 2px)
+	// polygon(0% 0%, 60% 0%, 60% 100%, 0% 100%, 0% 0%, 80% 66.667%, 100% 66.667%, 100% 100%, 80% 100%, 80% 66.667%)
+	// clip-path: polygon(0px 0px, 3px 0px, 3px 3px, 0px 3px, 0px 0px, 1px 1px, 1px 2px, 2px 2px, 2px 1px, 1px 1px, 0px 0px, 4px 2px, 5px 2px, 5px 3px, 4px 3px, 4px 2px);
+}
+
+func ExamplePath_ClipPath_empty() {
+	path := &bmppath.Path{Width: 4, Height: 4}
+	fmt.Println(path.ClipPath(nil))
+
+	// Output:
+	// polygon(0 0)
+}
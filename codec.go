@@ -0,0 +1,184 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath
+
+import (
+	"bytes"
+	"compress/flate"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Codec is a compression backend of the compact binary encoding of the paths,
+// applied by EncodeBinary and DecodeBinary on top of the delta-coded varints.
+// The backends depending on the third-party packages can be injected by the
+// users. For example, the zstd backend with github.com/klauspost/compress/zstd
+// is:
+//
+//	enc, _ := zstd.NewWriter(nil)
+//	dec, _ := zstd.NewReader(nil)
+//	codec := &bmppath.Codec{
+//		Name:       "zstd",
+//		Compress:   func(b []byte) ([]byte, error) { return enc.EncodeAll(b, nil), nil },
+//		Decompress: func(b []byte) ([]byte, error) { return dec.DecodeAll(b, nil) },
+//	}
+type Codec struct {
+	// Name is the name of the backend, used in the reports.
+	Name string
+
+	// Compress and Decompress convert the delta-coded binary data. If nil,
+	// the data is stored as it is.
+	Compress, Decompress func(b []byte) ([]byte, error)
+}
+
+var (
+	// CodecRaw stores the delta-coded varints as they are. It is the
+	// fastest, and often small enough for the small shapes, for which the
+	// general-purpose compressors add more overhead than they save.
+	CodecRaw = &Codec{Name: "raw"}
+
+	// CodecFlate compresses the delta-coded varints with DEFLATE of the
+	// standard library at the best compression level. Its Decompress fails
+	// if the data expands to more than DefaultFlateLimit bytes, so that a
+	// small crafted data cannot exhaust the memory. NewCodecFlate creates
+	// the one with another limit.
+	CodecFlate = NewCodecFlate(DefaultFlateLimit)
+)
+
+// DefaultFlateLimit is the maximum size in bytes of the data decompressed by
+// CodecFlate, which is enough for tens of millions of vertices.
+const DefaultFlateLimit = 64 << 20
+
+// NewCodecFlate returns a backend identical to CodecFlate except that its
+// Decompress fails if the data expands to more than limit bytes.
+func NewCodecFlate(limit int64) *Codec {
+	return &Codec{
+		Name:     "flate",
+		Compress: flateCompress,
+		Decompress: func(b []byte) ([]byte, error) {
+			return flateDecompress(b, limit)
+		},
+	}
+}
+
+func flateCompress(b []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	fw, err := flate.NewWriter(&buf, flate.BestCompression)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := fw.Write(b); err != nil {
+		return nil, err
+	}
+	if err := fw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func flateDecompress(b []byte, limit int64) ([]byte, error) {
+	ret, err := io.ReadAll(io.LimitReader(flate.NewReader(bytes.NewReader(b)), limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if limit < int64(len(ret)) {
+		return nil, fmt.Errorf("decompressed data exceeds %d bytes", limit)
+	}
+	return ret, nil
+}
+
+// EncodeBinary returns the compact binary representation of p, the same as the
+// one stored in a Catalog, compressed by the backend c. nil c is the same as
+// CodecRaw.
+func (p *Path) EncodeBinary(c *Codec) ([]byte, error) {
+	b, err := p.appendBinary(nil)
+	if err != nil || c == nil || c.Compress == nil {
+		return b, err
+	}
+	b, err = c.Compress(b)
+	if err != nil {
+		return nil, fmt.Errorf("%s: compress failure: %w", c.Name, err)
+	}
+	return b, nil
+}
+
+// DecodeBinary decodes the binary representation of a Path written by
+// EncodeBinary with the same backend c. nil c is the same as CodecRaw. It
+// returns ErrInvalidEncoding if b is broken, including the case where the
+// decompression by c fails.
+func DecodeBinary(b []byte, c *Codec) (*Path, error) {
+	if c != nil && c.Decompress != nil {
+		var err error
+		if b, err = c.Decompress(b); err != nil {
+			return nil, fmt.Errorf("%w: %s: %v", ErrInvalidEncoding, c.Name, err)
+		}
+	}
+	return decodeBinary(b)
+}
+
+// CodecComparison is the result of encoding a set of paths with a backend,
+// returned by CompareCodecs.
+type CodecComparison struct {
+	// Name is the name of the backend.
+	Name string
+
+	// Size is the total size of the encoded paths in bytes.
+	Size int
+
+	// Ratio is Size divided by the total size with CodecRaw.
+	Ratio float64
+
+	// EncodeTime and DecodeTime are the total time to encode and decode
+	// the paths.
+	EncodeTime, DecodeTime time.Duration
+}
+
+// CompareCodecs encodes each of the paths separately with each of the backends
+// codecs, and reports the total sizes and times, so that the users storing a
+// large number of traced shapes can choose the trade-off. Each encoded Path is
+// decoded and verified to be equal to the original one. The results are
+// returned in the same order as codecs.
+func CompareCodecs(paths []*Path, codecs []*Codec) ([]CodecComparison, error) {
+	raw := 0
+	for _, p := range paths {
+		b, err := p.appendBinary(nil)
+		if err != nil {
+			return nil, err
+		}
+		raw += len(b)
+	}
+	ret := make([]CodecComparison, 0, len(codecs))
+	for _, c := range codecs {
+		if c == nil {
+			c = CodecRaw
+		}
+		r := CodecComparison{Name: c.Name}
+		for _, p := range paths {
+			start := time.Now()
+			b, err := p.EncodeBinary(c)
+			if err != nil {
+				return nil, err
+			}
+			r.EncodeTime += time.Since(start)
+			r.Size += len(b)
+
+			start = time.Now()
+			q, err := DecodeBinary(b, c)
+			if err != nil {
+				return nil, err
+			}
+			r.DecodeTime += time.Since(start)
+			if !p.Equal(q) {
+				return nil, fmt.Errorf("%w: %s: decoded path differs", ErrInvalidEncoding, c.Name)
+			}
+		}
+		if raw != 0 {
+			r.Ratio = float64(r.Size) / float64(raw)
+		}
+		ret = append(ret, r)
+	}
+	return ret, nil
+}
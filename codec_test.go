@@ -0,0 +1,133 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath_test
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/zlib"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/tunabay/go-bitarray"
+	"github.com/tunabay/go-bmppath"
+)
+
+// zlibCodec is a backend injected by the user.
+var zlibCodec = &bmppath.Codec{
+	Name: "zlib",
+	Compress: func(b []byte) ([]byte, error) {
+		var buf bytes.Buffer
+		zw := zlib.NewWriter(&buf)
+		if _, err := zw.Write(b); err != nil {
+			return nil, err
+		}
+		if err := zw.Close(); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	},
+	Decompress: func(b []byte) ([]byte, error) {
+		zr, err := zlib.NewReader(bytes.NewReader(b))
+		if err != nil {
+			return nil, err
+		}
+		return io.ReadAll(zr)
+	},
+}
+
+func TestCompareCodecs(t *testing.T) {
+	var paths []*bmppath.Path
+	for _, s := range []string{
+		"0110100110010110",
+		"1111100110011111",
+		strings.Repeat("1010", 64),
+	} {
+		p, err := bmppath.New(bitarray.NewBufferFromBitArray(bitarray.MustParse(s)), 4)
+		if err != nil {
+			t.Fatalf("New(): %v", err)
+		}
+		paths = append(paths, p)
+	}
+	codecs := []*bmppath.Codec{nil, bmppath.CodecFlate, zlibCodec}
+	res, err := bmppath.CompareCodecs(paths, codecs)
+	if err != nil {
+		t.Fatalf("CompareCodecs(): %v", err)
+	}
+	if len(res) != len(codecs) {
+		t.Fatalf("unexpected number of results: %d", len(res))
+	}
+	for i, name := range []string{"raw", "flate", "zlib"} {
+		if res[i].Name != name {
+			t.Errorf("#%d: unexpected name: %s", i, res[i].Name)
+		}
+		if res[i].Size <= 0 || res[i].Ratio <= 0 {
+			t.Errorf("#%d: unexpected size: %d, %g", i, res[i].Size, res[i].Ratio)
+		}
+	}
+	if res[0].Ratio != 1 {
+		t.Errorf("unexpected ratio of raw: %g", res[0].Ratio)
+	}
+
+	for _, c := range codecs {
+		b, err := paths[2].EncodeBinary(c)
+		if err != nil {
+			t.Fatalf("EncodeBinary(): %v", err)
+		}
+		p, err := bmppath.DecodeBinary(b, c)
+		if err != nil {
+			t.Fatalf("DecodeBinary(): %v", err)
+		}
+		if !p.Equal(paths[2]) {
+			t.Errorf("%v: round trip failed: %s", c, bmppath.Diff(p, paths[2]))
+		}
+		if _, err := bmppath.DecodeBinary(b[:len(b)-1], c); !errors.Is(err, bmppath.ErrInvalidEncoding) {
+			t.Errorf("%v: unexpected error for truncated data: %v", c, err)
+		}
+	}
+}
+
+func TestDecodeBinary_flateBomb(t *testing.T) {
+	bomb := func(n int) []byte {
+		var buf bytes.Buffer
+		fw, err := flate.NewWriter(&buf, flate.BestSpeed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := fw.Write(make([]byte, n)); err != nil {
+			t.Fatal(err)
+		}
+		if err := fw.Close(); err != nil {
+			t.Fatal(err)
+		}
+		return buf.Bytes()
+	}
+	if _, err := bmppath.DecodeBinary(bomb(bmppath.DefaultFlateLimit+1), bmppath.CodecFlate); !errors.Is(err, bmppath.ErrInvalidEncoding) {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if _, err := bmppath.DecodeBinary(bomb(1001), bmppath.NewCodecFlate(1000)); !errors.Is(err, bmppath.ErrInvalidEncoding) {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	// the limit does not affect the data within it
+	p := &bmppath.Path{Width: 2, Height: 2, Vertices: [][]bmppath.Vertex{{{0, 0}, {2, 0}, {2, 2}, {0, 2}}}}
+	b, err := p.EncodeBinary(bmppath.CodecFlate)
+	if err != nil {
+		t.Fatalf("EncodeBinary(): %v", err)
+	}
+	raw, err := p.EncodeBinary(nil)
+	if err != nil {
+		t.Fatalf("EncodeBinary(): %v", err)
+	}
+	got, err := bmppath.DecodeBinary(b, bmppath.NewCodecFlate(int64(len(raw))))
+	if err != nil {
+		t.Fatalf("DecodeBinary(): %v", err)
+	}
+	if !got.Equal(p) {
+		t.Errorf("round trip failed: %s", bmppath.Diff(p, got))
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/tunabay/go-bitarray"
+)
+
+// ErrInvalidFactor is the error thrown when the specified supersampling factor
+// is invalid.
+var ErrInvalidFactor = errors.New("invalid factor")
+
+// TraceComparison is the result of tracing a bitmap image at a supersampling
+// factor, returned by CompareResolutions.
+type TraceComparison struct {
+	// Factor is the supersampling factor.
+	Factor int
+
+	// Path is the traced result. Its canvas is Factor times larger than the
+	// source bitmap image.
+	Path *Path
+
+	// NumVertices is the total number of vertices of Path.
+	NumVertices int
+
+	// DiffPixels is the number of pixels that differ between Path and the
+	// source bitmap image magnified by Factor.
+	DiffPixels int
+
+	// DiffRatio is DiffPixels divided by the number of pixels of the
+	// magnified source bitmap image.
+	DiffRatio float64
+}
+
+// CompareResolutions traces the same bitmap image bm at each of the
+// supersampling factors, and reports the trade-off between the number of
+// vertices and the fidelity to the source. At each factor, bm is magnified
+// with the nearest neighbor interpolation, then preprocessed as specified by
+// opts and traced. Since the preprocessing such as Smooth works at the pixel
+// level, higher factors result in finer smoothing. nil opts is the same as
+// Options{Smooth: 1}. The results are returned in the same order as factors.
+func CompareResolutions(bm *bitarray.Buffer, width int, factors []int, opts *Options) ([]TraceComparison, error) {
+	height, err := bitmapHeight(bm, width)
+	if err != nil {
+		return nil, err
+	}
+	if opts == nil {
+		opts = &Options{Smooth: 1}
+	}
+	img := newBitmap(bm, width, height)
+	ret := make([]TraceComparison, 0, len(factors))
+	for _, k := range factors {
+		if k < 1 {
+			return nil, fmt.Errorf("%w: factor=%d < 1", ErrInvalidFactor, k)
+		}
+		size, ok := mulInt(bm.Len(), k)
+		if ok {
+			_, ok = mulInt(size, k)
+		}
+		if !ok {
+			return nil, fmt.Errorf("%w: factor=%d", ErrTooLarge, k)
+		}
+		src := img.upscale(k).buffer()
+		path, err := NewWithOptions(src, width*k, opts)
+		if err != nil {
+			return nil, err
+		}
+		diff := path.Rasterize()
+		diff.XorAt(0, src)
+		n := diff.OnesCount()
+		ret = append(ret, TraceComparison{
+			Factor:      k,
+			Path:        path,
+			NumVertices: path.NumVertices(),
+			DiffPixels:  n,
+			DiffRatio:   float64(n) / float64(src.Len()),
+		})
+	}
+	return ret, nil
+}
@@ -0,0 +1,54 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/tunabay/go-bitarray"
+	"github.com/tunabay/go-bmppath"
+)
+
+func ExampleCompareResolutions() {
+	bmp := bitarray.NewBufferFromBitArray(
+		bitarray.MustParse(strings.Join([]string{
+			"00000000",
+			"00111100",
+			"01111110",
+			"01111110",
+			"00111100",
+			"00000000",
+		}, "")),
+	)
+
+	results, err := bmppath.CompareResolutions(bmp, 8, []int{1, 2, 4}, nil)
+	if err != nil {
+		panic(err)
+	}
+	for _, r := range results {
+		fmt.Printf("x%d: vertices=%d, diff=%d (%.3f)\n", r.Factor, r.NumVertices, r.DiffPixels, r.DiffRatio)
+	}
+
+	// Output:
+	// x1: vertices=12, diff=0 (0.000)
+	// x2: vertices=20, diff=12 (0.062)
+	// x4: vertices=36, diff=12 (0.016)
+}
+
+func TestCompareResolutions_tooLarge(t *testing.T) {
+	bmp := bitarray.NewBufferFromBitArray(bitarray.MustParse("01101001"))
+	// the square of the factor exceeds the range of int
+	factor := 1 << 16
+	if ^uint(0)>>32 != 0 {
+		factor <<= 15
+	}
+	_, err := bmppath.CompareResolutions(bmp, 4, []int{factor}, nil)
+	if !errors.Is(err, bmppath.ErrTooLarge) {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
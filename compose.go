@@ -0,0 +1,34 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath
+
+// Append merges the closed paths of other into p, placing the upper left corner
+// of other at (atX, atY) on the canvas of p. The canvas of p is grown as needed
+// to contain the canvas of other. Unlike most other methods, Append modifies p
+// itself, so that many traced images such as glyphs can be composed into one
+// document starting from the zero value of Path. other is not modified.
+func (p *Path) Append(other *Path, atX, atY int) {
+	if w := atX + other.Width; p.Width < w {
+		p.Width = w
+	}
+	if h := atY + other.Height; p.Height < h {
+		p.Height = h
+	}
+	if p.Labels != nil || other.Labels != nil {
+		for len(p.Labels) < len(p.Vertices) {
+			p.Labels = append(p.Labels, "")
+		}
+		for i := range other.Vertices {
+			p.Labels = append(p.Labels, other.PathLabel(i))
+		}
+	}
+	for _, vs := range other.Vertices {
+		nvs := make([]Vertex, len(vs))
+		for i, v := range vs {
+			nvs[i] = Vertex{v[0] + atX, v[1] + atY}
+		}
+		p.Vertices = append(p.Vertices, nvs)
+	}
+}
@@ -0,0 +1,35 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath_test
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/tunabay/go-bitarray"
+	"github.com/tunabay/go-bmppath"
+)
+
+func ExamplePath_Append() {
+	glyph, err := bmppath.New(bitarray.NewBufferFromBitArray(
+		bitarray.MustParse(strings.Join([]string{
+			"111",
+			"101",
+			"111",
+		}, "")),
+	), 3)
+	if err != nil {
+		panic(err)
+	}
+
+	doc := &bmppath.Path{}
+	for i := 0; i < 3; i++ {
+		doc.Append(glyph, i*4, 1)
+	}
+	fmt.Printf("%dx%d: %s\n", doc.Width, doc.Height, doc.SVGDString())
+
+	// Output:
+	// 11x4: m0,1h3v3h-3zm1,1v1h1v-1zm3-1h3v3h-3zm1,1v1h1v-1zm3-1h3v3h-3zm1,1v1h1v-1z
+}
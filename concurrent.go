@@ -0,0 +1,215 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath
+
+import (
+	"runtime"
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+// concurrentMinPixels is the number of pixels of the smallest bitmap image
+// traced concurrently. The smaller ones are traced faster by a single
+// goroutine.
+const concurrentMinPixels = 1 << 16
+
+// bandsPerWorker is the number of the bands per goroutine, which balances the
+// load between the goroutines.
+const bandsPerWorker = 4
+
+// workers returns the number of the goroutines to trace the bitmap image of the
+// size width x height, as specified by opts. opts may be nil.
+func (opts *Options) workers(width, height int) int {
+	if width*height < concurrentMinPixels {
+		return 1
+	}
+	if opts != nil && 0 < opts.Workers {
+		return opts.Workers
+	}
+	return runtime.GOMAXPROCS(0)
+}
+
+// turns holds the directions to try at a grid point for each direction of the
+// incoming edge, in the same order as EdgeGrid.paths tries. The left turn is
+// preferred, so that the closed paths do not cross at the saddle points.
+var turns = [4][3]int{{3, 1, 0}, {0, 2, 1}, {1, 3, 2}, {2, 0, 3}}
+
+// moves holds the offsets of the grid points for each direction.
+var moves = [4]Vertex{{0, -1}, {1, 0}, {0, 1}, {-1, 0}}
+
+// bandPaths is identical to paths except that the closed paths are traced by n
+// goroutines concurrently. The grid points are split into the horizontal
+// bands, and each cycle of the edges is traced by the goroutine of the band
+// where its first rightward edge is. The closed paths are returned in the same
+// order as paths returns, as soon as all the bands above their starting points
+// have been traced. g is not modified. stop must be called when the closed
+// paths are no longer read, and it returns after all the goroutines have
+// stopped reading g, so that g can be reused after that.
+func (g *EdgeGrid) bandPaths(n int) (next func() *path, stop func()) {
+	w, rows := g.Width+1, g.Height+1
+	nb := n * bandsPerWorker
+	if rows < nb {
+		nb = rows
+	}
+	bands := make([]chan []startedPath, nb)
+	for i := range bands {
+		bands[i] = make(chan []startedPath, 1)
+	}
+	quit := make(chan struct{})
+	var taken int32
+	var wg sync.WaitGroup
+	wg.Add(n)
+	for k := 0; k < n; k++ {
+		go func() {
+			defer wg.Done()
+			for {
+				i := int(atomic.AddInt32(&taken, 1)) - 1
+				if nb <= i {
+					return
+				}
+				select {
+				case <-quit:
+					return
+				default:
+				}
+				bands[i] <- g.traceBand(rows*i/nb, rows*(i+1)/nb)
+			}
+		}()
+	}
+
+	// the closed paths of the bands traced so far, sorted by the starting
+	// points, and the number of them ready to be returned
+	var pending []startedPath
+	ready, i := 0, 0
+	next = func() *path {
+		for ready == 0 {
+			if i == nb {
+				return nil
+			}
+			pending = append(pending, <-bands[i]...)
+			sort.Slice(pending, func(a, b int) bool { return pending[a].start < pending[b].start })
+			i++
+			bound := w * rows * i / nb
+			if i < nb {
+				bound = w * (rows * i / nb)
+			}
+			for ready < len(pending) && pending[ready].start < bound {
+				ready++
+			}
+		}
+		p := pending[0].path
+		pending = pending[1:]
+		ready--
+		return p
+	}
+	return next, func() {
+		close(quit)
+		wg.Wait()
+	}
+}
+
+// startedPath is a closed path with the raster index of its starting point.
+type startedPath struct {
+	path  *path
+	start int
+}
+
+// traceBand traces the cycles of the edges whose first rightward edges in the
+// raster order are in the rows of the grid points from y0 to y1-1, and returns
+// the closed paths into which paths splits them. g is not modified, so that the
+// bands can be traced concurrently. Each rightward edge is followed until the
+// cycle returns to it, or abandoned when an earlier rightward edge is found.
+// Since the cycles are followed preferring the left turns, the result does not
+// depend on the order of them.
+func (g *EdgeGrid) traceBand(y0, y1 int) []startedPath {
+	w := g.Width + 1
+	has := func(x, y, dir int) bool {
+		return g.has(w*y+x, dir)
+	}
+	// the rightward edges in the band reached from an earlier one, which
+	// cannot be the first ones of the cycles
+	seen := make([]uint64, (w*(y1-y0)+63)>>6)
+
+	var ret []startedPath
+	var vs []Vertex
+	a := &arena{}
+	for y := y0; y < y1; y++ {
+		for x := 0; x < w; x++ {
+			if i := w*(y-y0) + x; !has(x, y, 1) || seen[i>>6]&(1<<(i&63)) != 0 {
+				continue
+			}
+			vs = append(vs[:0], Vertex{x, y})
+			dir, cx, cy := 1, x+1, y
+			for {
+				nd := -1
+				for _, d := range turns[dir] {
+					if has(cx, cy, d) {
+						nd = d
+						break
+					}
+				}
+				if cx == x && cy == y && nd == 1 {
+					for _, c := range splitCycle(append([]Vertex(nil), vs...)) {
+						p := newPath(a, c[0])
+						for _, v := range c[1:] {
+							p.addVertex(v[0], v[1])
+						}
+						p.close()
+						ret = append(ret, startedPath{path: p, start: w*c[0][1] + c[0][0]})
+					}
+					break
+				}
+				if nd == 1 {
+					if cy < y || (cy == y && cx < x) {
+						break
+					}
+					if cy < y1 {
+						i := w*(cy-y0) + cx
+						seen[i>>6] |= 1 << (i & 63)
+					}
+				}
+				if nd != dir {
+					vs = append(vs, Vertex{cx, cy})
+				}
+				dir, cx, cy = nd, cx+moves[nd][0], cy+moves[nd][1]
+			}
+		}
+	}
+	return ret
+}
+
+// splitCycle splits the cycle of the edges, given as its vertices starting from
+// its first rightward edge, in the same way as paths does. paths closes each
+// closed path when it returns to the starting point, even if the cycle passes
+// through the point again as a saddle point, and traces the rest of the cycle
+// later from its first rightward edge.
+func splitCycle(vs []Vertex) [][]Vertex {
+	var ret [][]Vertex
+	for {
+		k := 1
+		for k < len(vs) && vs[k] != vs[0] {
+			k++
+		}
+		if k == len(vs) {
+			return append(ret, vs)
+		}
+		ret = append(ret, vs[:k:k])
+		vs = vs[k:]
+
+		// the rest starts from its first rightward edge
+		first := -1
+		for i, v := range vs {
+			u := vs[(i+1)%len(vs)]
+			if u[1] != v[1] || u[0] < v[0] {
+				continue
+			}
+			if first == -1 || v[1] < vs[first][1] || (v[1] == vs[first][1] && v[0] < vs[first][0]) {
+				first = i
+			}
+		}
+		vs = append(vs[first:len(vs):len(vs)], vs[:first]...)
+	}
+}
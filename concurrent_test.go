@@ -0,0 +1,54 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath_test
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/tunabay/go-bitarray"
+	"github.com/tunabay/go-bmppath"
+)
+
+func TestNewWithOptions_workers(t *testing.T) {
+	// large enough to be traced concurrently, with the closed paths
+	// crossing the bands and touching themselves at the saddle points
+	const width, height = 320, 256
+	r := rand.New(rand.NewSource(1))
+	bmp := bitarray.NewBuffer(width * height)
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			if (x+y)%2 == 0 && r.Intn(4) != 0 || (x/7+y/5)%3 == 0 {
+				bmp.PutBitAt(width*y+x, 1)
+			}
+		}
+	}
+	trace := func(workers int) (*bmppath.Path, [][]bmppath.Vertex) {
+		var visited [][]bmppath.Vertex
+		opts := &bmppath.Options{
+			Workers: workers,
+			Visit: func(vs []bmppath.Vertex) bool {
+				visited = append(visited, append([]bmppath.Vertex(nil), vs...))
+				return true
+			},
+		}
+		path, err := bmppath.NewWithOptions(bmp, width, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return path, visited
+	}
+	want, wantVisited := trace(1)
+	for _, workers := range []int{0, 2, 3, 8} {
+		got, gotVisited := trace(workers)
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("workers=%d: path differs from the single goroutine", workers)
+		}
+		if !reflect.DeepEqual(gotVisited, wantVisited) {
+			t.Errorf("workers=%d: visited closed paths differ from the single goroutine", workers)
+		}
+	}
+}
@@ -0,0 +1,474 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath_test
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math/rand"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/tunabay/go-bitarray"
+	"github.com/tunabay/go-bmppath"
+)
+
+// encoder is an entry of the conformance suite. write encodes the Path, and
+// decode reads the written document back into a Path on the same canvas, which
+// is compared with the original one by rasterizing both.
+type encoder struct {
+	name   string
+	write  func(p *bmppath.Path, w io.Writer) error
+	decode func(p *bmppath.Path, data []byte) (*bmppath.Path, error)
+}
+
+var encoders = []encoder{
+	{"SVG", (*bmppath.Path).WriteSVG, decodeSVG},
+	{"SVGUse", (*bmppath.Path).WriteSVGUse, decodeSVG},
+	{
+		"SVGClipPath",
+		func(p *bmppath.Path, w io.Writer) error { return p.WriteSVGClipPath(w, "c") },
+		decodeSVG,
+	},
+	{
+		"SVGMask",
+		func(p *bmppath.Path, w io.Writer) error { return p.WriteSVGMask(w, "m") },
+		decodeSVG,
+	},
+	{"SVGZ", (*bmppath.Path).WriteSVGZ, decodeSVGZ},
+	{"DXF", (*bmppath.Path).WriteDXF, decodeDXF},
+	{"EPS", (*bmppath.Path).WriteEPS, decodeEPS},
+	{
+		"HPGL",
+		func(p *bmppath.Path, w io.Writer) error { return p.WriteHPGL(w, nil) },
+		decodeHPGL,
+	},
+	{
+		"GCode",
+		func(p *bmppath.Path, w io.Writer) error { return p.WriteGCode(w, nil) },
+		decodeGCode,
+	},
+	{"CanvasJSON", (*bmppath.Path).WriteCanvasJSON, decodeCanvasJSON},
+	{
+		"FloatSVG",
+		func(p *bmppath.Path, w io.Writer) error { return p.Float().WriteSVG(w) },
+		decodeSVG,
+	},
+	{
+		"SVGOptions",
+		func(p *bmppath.Path, w io.Writer) error { return p.WriteSVGOptions(w, nil) },
+		decodeSVG,
+	},
+	{
+		"SVGSeparate",
+		func(p *bmppath.Path, w io.Writer) error {
+			return p.WriteSVGOptions(w, &bmppath.SVGOptions{Separate: true, IDPrefix: "c"})
+		},
+		decodeSVG,
+	},
+	{
+		"SVGSplit",
+		func(p *bmppath.Path, w io.Writer) error {
+			return p.WriteSVGOptions(w, &bmppath.SVGOptions{MaxDLength: 24})
+		},
+		decodeSVG,
+	},
+	{
+		"SVGEvenOdd",
+		func(p *bmppath.Path, w io.Writer) error {
+			return p.WriteSVGOptions(w, &bmppath.SVGOptions{FillRule: bmppath.EvenOdd, Separate: true})
+		},
+		decodeSVG,
+	},
+	{
+		"CurveSVG",
+		func(p *bmppath.Path, w io.Writer) error { return p.RoundCorners(0).WriteSVG(w) },
+		decodeSVG,
+	},
+	{
+		"FloatDXF",
+		func(p *bmppath.Path, w io.Writer) error { return p.Float().WriteDXF(w) },
+		decodeDXF,
+	},
+}
+
+// conformanceCorpus returns the shared shapes to test the encoders with.
+func conformanceCorpus() map[string]*bitarray.Buffer {
+	corpus := map[string]*bitarray.Buffer{
+		"empty": bitarray.NewBuffer(64),
+		"full":  bitarray.NewBufferFromBitArray(bitarray.MustParse(strings.Repeat("1", 64))),
+		"invader": bitarray.NewBufferFromByteSlice([]byte{
+			0b_10000001, 0b_01000010, 0b_00111100, 0b_01111110,
+			0b_11011011, 0b_01111110, 0b_00100100, 0b_11000011,
+		}),
+		"nested": bitarray.NewBufferFromBitArray(bitarray.MustParse(strings.Join([]string{
+			"11111111", "10000001", "10111101", "10100101",
+			"10100101", "10111101", "10000001", "11111111",
+		}, ""))),
+		"checker": bitarray.NewBufferFromByteSlice([]byte{
+			0xaa, 0x55, 0xaa, 0x55, 0xaa, 0x55, 0xaa, 0x55,
+		}),
+	}
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 4; i++ {
+		b := make([]byte, 8)
+		rnd.Read(b)
+		corpus[fmt.Sprintf("random-%d", i)] = bitarray.NewBufferFromByteSlice(b)
+	}
+	return corpus
+}
+
+func TestConformance(t *testing.T) {
+	for name, bmp := range conformanceCorpus() {
+		path, err := bmppath.New(bmp, 8)
+		if err != nil {
+			t.Fatalf("%s: New(): %v", name, err)
+		}
+		want := path.Rasterize()
+		for _, enc := range encoders {
+			var buf bytes.Buffer
+			if err := enc.write(path, &buf); err != nil {
+				t.Errorf("%s: %s: write: %v", enc.name, name, err)
+				continue
+			}
+			decoded, err := enc.decode(path, buf.Bytes())
+			if err != nil {
+				t.Errorf("%s: %s: decode: %v\n%s", enc.name, name, err, buf.String())
+				continue
+			}
+			if got := decoded.Rasterize(); !got.BitArray().Equal(want.BitArray()) {
+				t.Errorf("%s: %s: image mismatch\n%s", enc.name, name, buf.String())
+			}
+		}
+	}
+}
+
+// decodeSVGZ decompresses the gzipped SVG document and decodes it with
+// decodeSVG.
+func decodeSVGZ(p *bmppath.Path, data []byte) (*bmppath.Path, error) {
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	data, err = io.ReadAll(zr)
+	if err != nil {
+		return nil, err
+	}
+	return decodeSVG(p, data)
+}
+
+// decodeSVG checks that data is a well-formed XML document and reads the
+// <path> elements in it, expanding the <use> elements referring to them. The
+// first white <path> element directly in the root <svg> element is skipped as
+// the background.
+func decodeSVG(p *bmppath.Path, data []byte) (*bmppath.Path, error) {
+	ret := &bmppath.Path{Width: p.Width, Height: p.Height}
+	defs := make(map[string][][]bmppath.Vertex)
+	inDefs, background := false, false
+	var stack []string
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch tok := tok.(type) {
+		case xml.StartElement:
+			parent := ""
+			if len(stack) != 0 {
+				parent = stack[len(stack)-1]
+			}
+			stack = append(stack, tok.Name.Local)
+			attrs := make(map[string]string)
+			for _, a := range tok.Attr {
+				attrs[a.Name.Local] = a.Value
+			}
+			switch tok.Name.Local {
+			case "defs":
+				inDefs = true
+			case "path":
+				if attrs["fill"] == "#fff" && parent == "svg" && !background {
+					background = true
+					continue
+				}
+				vss, err := parseSVGD(attrs["d"])
+				if err != nil {
+					return nil, err
+				}
+				if inDefs {
+					defs[attrs["id"]] = vss
+					continue
+				}
+				ret.Vertices = append(ret.Vertices, vss...)
+			case "use":
+				vss, ok := defs[strings.TrimPrefix(attrs["href"], "#")]
+				if !ok {
+					return nil, fmt.Errorf("undefined reference: %q", attrs["href"])
+				}
+				x, _ := strconv.Atoi(attrs["x"])
+				y, _ := strconv.Atoi(attrs["y"])
+				for _, vs := range vss {
+					moved := make([]bmppath.Vertex, len(vs))
+					for i, v := range vs {
+						moved[i] = bmppath.Vertex{v.X() + x, v.Y() + y}
+					}
+					ret.Vertices = append(ret.Vertices, moved)
+				}
+			}
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+			if tok.Name.Local == "defs" {
+				inDefs = false
+			}
+		}
+	}
+	return ret, nil
+}
+
+// parseSVGD parses the 'd' string consisting of the commands m, h, v, and z,
+// and their absolute versions.
+func parseSVGD(d string) ([][]bmppath.Vertex, error) {
+	var ret [][]bmppath.Vertex
+	var cur, start bmppath.Vertex
+	var vs []bmppath.Vertex
+	for len(d) != 0 {
+		cmd := d[0]
+		d = d[1:]
+		if cmd == 'z' || cmd == 'Z' {
+			if len(vs) != 0 {
+				ret = append(ret, vs)
+			}
+			vs, cur = nil, start
+			continue
+		}
+		n := 1
+		if cmd == 'm' || cmd == 'M' {
+			n = 2
+		}
+		var args [2]int
+		for i := 0; i < n; i++ {
+			d = strings.TrimPrefix(d, ",")
+			j := 0
+			for j < len(d) && (d[j] == '-' && j == 0 || '0' <= d[j] && d[j] <= '9') {
+				j++
+			}
+			v, err := strconv.Atoi(d[:j])
+			if err != nil {
+				return nil, fmt.Errorf("command %c: %w", cmd, err)
+			}
+			args[i], d = v, d[j:]
+		}
+		switch cmd {
+		case 'm':
+			cur = bmppath.Vertex{cur.X() + args[0], cur.Y() + args[1]}
+		case 'M':
+			cur = bmppath.Vertex{args[0], args[1]}
+		case 'h':
+			cur = bmppath.Vertex{cur.X() + args[0], cur.Y()}
+		case 'H':
+			cur = bmppath.Vertex{args[0], cur.Y()}
+		case 'v':
+			cur = bmppath.Vertex{cur.X(), cur.Y() + args[0]}
+		case 'V':
+			cur = bmppath.Vertex{cur.X(), args[0]}
+		default:
+			return nil, fmt.Errorf("unsupported command: %c", cmd)
+		}
+		if cmd == 'm' || cmd == 'M' {
+			start = cur
+		}
+		vs = append(vs, cur)
+	}
+	if len(vs) != 0 {
+		return nil, fmt.Errorf("unclosed path")
+	}
+	return ret, nil
+}
+
+// decodeDXF reads the POLYLINE entities in the DXF document data, flipping the
+// y-axis back.
+func decodeDXF(p *bmppath.Path, data []byte) (*bmppath.Path, error) {
+	ret := &bmppath.Path{Width: p.Width, Height: p.Height}
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	var pairs [][2]string
+	for sc.Scan() {
+		code := sc.Text()
+		if !sc.Scan() {
+			return nil, fmt.Errorf("missing value for group code %s", code)
+		}
+		pairs = append(pairs, [2]string{code, sc.Text()})
+	}
+	if len(pairs) == 0 || pairs[len(pairs)-1] != [2]string{"0", "EOF"} {
+		return nil, fmt.Errorf("missing EOF")
+	}
+	var vs []bmppath.Vertex
+	for _, pair := range pairs {
+		switch pair {
+		case [2]string{"0", "VERTEX"}:
+			vs = append(vs, bmppath.Vertex{})
+		case [2]string{"0", "SEQEND"}:
+			ret.Vertices = append(ret.Vertices, vs)
+			vs = nil
+		}
+		if len(vs) == 0 || (pair[0] != "10" && pair[0] != "20") {
+			continue
+		}
+		v, err := strconv.Atoi(pair[1])
+		if err != nil {
+			return nil, err
+		}
+		if pair[0] == "10" {
+			vs[len(vs)-1][0] = v
+		} else {
+			vs[len(vs)-1][1] = p.Height - v
+		}
+	}
+	return ret, nil
+}
+
+// decodeEPS decodes the moveto, lineto, and closepath operators written by
+// WriteEPS, flipping the y-axis back.
+func decodeEPS(p *bmppath.Path, data []byte) (*bmppath.Path, error) {
+	if !bytes.HasPrefix(data, []byte("%!PS-Adobe-3.0 EPSF-3.0\n")) {
+		return nil, fmt.Errorf("missing header")
+	}
+	if !bytes.HasSuffix(data, []byte("\n%%EOF\n")) {
+		return nil, fmt.Errorf("missing EOF")
+	}
+	ret := &bmppath.Path{Width: p.Width, Height: p.Height}
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	var vs []bmppath.Vertex
+	for sc.Scan() {
+		fields := strings.Fields(sc.Text())
+		switch {
+		case len(fields) == 1 && fields[0] == "closepath":
+			ret.Vertices = append(ret.Vertices, vs)
+			vs = nil
+		case len(fields) == 3 && (fields[2] == "moveto" || fields[2] == "lineto"):
+			x, err := strconv.Atoi(fields[0])
+			if err != nil {
+				return nil, err
+			}
+			y, err := strconv.Atoi(fields[1])
+			if err != nil {
+				return nil, err
+			}
+			vs = append(vs, bmppath.Vertex{x, p.Height - y})
+		}
+	}
+	return ret, nil
+}
+
+// decodeGCode decodes the G0 and G1 moves written by WriteGCode with the
+// default options. Each G0 move starts a closed path, and the last G1 move
+// returning to its start is dropped.
+func decodeGCode(p *bmppath.Path, data []byte) (*bmppath.Path, error) {
+	ret := &bmppath.Path{Width: p.Width, Height: p.Height}
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	for sc.Scan() {
+		fields := strings.Fields(sc.Text())
+		if len(fields) < 3 || (fields[0] != "G0" && fields[0] != "G1") {
+			continue
+		}
+		x, err := strconv.Atoi(strings.TrimPrefix(fields[1], "X"))
+		if err != nil {
+			return nil, err
+		}
+		y, err := strconv.Atoi(strings.TrimPrefix(fields[2], "Y"))
+		if err != nil {
+			return nil, err
+		}
+		v := bmppath.Vertex{x, y}
+		switch {
+		case fields[0] == "G0":
+			ret.Vertices = append(ret.Vertices, []bmppath.Vertex{v})
+		case len(ret.Vertices) == 0:
+			return nil, fmt.Errorf("G1 before G0")
+		default:
+			n := len(ret.Vertices) - 1
+			if vs := ret.Vertices[n]; v != vs[0] {
+				ret.Vertices[n] = append(vs, v)
+			}
+		}
+	}
+	return ret, nil
+}
+
+// decodeHPGL decodes the PU and PD commands written by WriteHPGL with the
+// default options, 40 plotter units per pixel with the y-axis flipped.
+func decodeHPGL(p *bmppath.Path, data []byte) (*bmppath.Path, error) {
+	ret := &bmppath.Path{Width: p.Width, Height: p.Height}
+	for _, cmd := range strings.Split(string(data), ";") {
+		cmd = strings.TrimSpace(cmd)
+		if len(cmd) <= 2 || (cmd[:2] != "PU" && cmd[:2] != "PD") {
+			continue
+		}
+		nums := strings.Split(cmd[2:], ",")
+		if len(nums)%2 != 0 {
+			return nil, fmt.Errorf("odd number of coordinates: %s", cmd)
+		}
+		var vs []bmppath.Vertex
+		for i := 0; i < len(nums); i += 2 {
+			x, err := strconv.Atoi(nums[i])
+			if err != nil {
+				return nil, err
+			}
+			y, err := strconv.Atoi(nums[i+1])
+			if err != nil {
+				return nil, err
+			}
+			vs = append(vs, bmppath.Vertex{x / 40, p.Height - y/40})
+		}
+		if cmd[:2] == "PU" {
+			ret.Vertices = append(ret.Vertices, vs)
+			continue
+		}
+		if len(ret.Vertices) == 0 {
+			return nil, fmt.Errorf("PD before PU")
+		}
+		n := len(ret.Vertices) - 1
+		ret.Vertices[n] = append(ret.Vertices[n], vs[:len(vs)-1]...)
+	}
+	return ret, nil
+}
+
+// decodeCanvasJSON decodes the command list written by WriteCanvasJSON.
+func decodeCanvasJSON(p *bmppath.Path, data []byte) (*bmppath.Path, error) {
+	var cmds [][]interface{}
+	if err := json.Unmarshal(data, &cmds); err != nil {
+		return nil, err
+	}
+	ret := &bmppath.Path{Width: p.Width, Height: p.Height}
+	for _, cmd := range cmds {
+		switch {
+		case len(cmd) == 1 && cmd[0] == "Z":
+		case len(cmd) == 3 && (cmd[0] == "M" || cmd[0] == "L"):
+			x, xok := cmd[1].(float64)
+			y, yok := cmd[2].(float64)
+			if !xok || !yok {
+				return nil, fmt.Errorf("non-numeric coordinates: %v", cmd)
+			}
+			if cmd[0] == "M" {
+				ret.Vertices = append(ret.Vertices, nil)
+			} else if len(ret.Vertices) == 0 {
+				return nil, fmt.Errorf("L before M")
+			}
+			n := len(ret.Vertices) - 1
+			ret.Vertices[n] = append(ret.Vertices[n], bmppath.Vertex{int(x), int(y)})
+		default:
+			return nil, fmt.Errorf("unexpected command: %v", cmd)
+		}
+	}
+	return ret, nil
+}
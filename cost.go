@@ -0,0 +1,76 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath
+
+// InkCoverage returns the percentage of the canvas covered with ink, the area
+// enclosed by the closed paths relative to Width x Height. It returns 0 for the
+// empty canvas.
+func (p *Path) InkCoverage() float64 {
+	if p.Width == 0 || p.Height == 0 {
+		return 0
+	}
+	return float64(p.Area64()) * 100 / (float64(p.Width) * float64(p.Height))
+}
+
+// CostParams represents the parameters for EstimateCost. The costs are in any
+// currency or time unit, and the lengths are in the unit of PixelSize.
+type CostParams struct {
+	// PixelSize is the physical size of a pixel, such as 0.1 for 0.1mm per
+	// pixel. Zero means the default value 1.
+	PixelSize float64
+
+	// AreaCost is the cost per unit area to engrave the ink.
+	AreaCost float64
+
+	// LengthCost is the cost per unit length to cut along the closed paths.
+	LengthCost float64
+
+	// PathCost is the cost per closed path, such as the time to pierce the
+	// material at the start of each cut.
+	PathCost float64
+
+	// BaseCost is the fixed cost per job, such as the setup time.
+	BaseCost float64
+}
+
+// CostEstimate is the estimated cost of a job returned by EstimateCost.
+type CostEstimate struct {
+	// Coverage is the ink coverage in percent, the same as InkCoverage.
+	Coverage float64
+
+	// Area is the physical area of the ink, and Length is the physical
+	// total length of the closed paths.
+	Area, Length float64
+
+	// EngraveCost is the cost to engrave Area, and CutCost is the cost to
+	// cut Length including the cost per closed path.
+	EngraveCost, CutCost float64
+
+	// Total is the sum of EngraveCost, CutCost, and the base cost.
+	Total float64
+}
+
+// EstimateCost estimates the cost of the job engraving and cutting p with a
+// laser cutter or a plotter, as specified by c, for quoting the jobs from the
+// uploaded bitmap images. nil c is the same as the zero value of CostParams,
+// which estimates only the coverage, the area, and the length.
+func (p *Path) EstimateCost(c *CostParams) *CostEstimate {
+	if c == nil {
+		c = &CostParams{}
+	}
+	ps := c.PixelSize
+	if ps == 0 {
+		ps = 1
+	}
+	ret := &CostEstimate{
+		Coverage: p.InkCoverage(),
+		Area:     float64(p.Area64()) * ps * ps,
+		Length:   float64(p.Perimeter()) * ps,
+	}
+	ret.EngraveCost = ret.Area * c.AreaCost
+	ret.CutCost = ret.Length*c.LengthCost + float64(len(p.Vertices))*c.PathCost
+	ret.Total = ret.EngraveCost + ret.CutCost + c.BaseCost
+	return ret
+}
@@ -0,0 +1,46 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath_test
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/tunabay/go-bitarray"
+	"github.com/tunabay/go-bmppath"
+)
+
+func ExamplePath_EstimateCost() {
+	bmp := bitarray.NewBufferFromBitArray(
+		bitarray.MustParse(strings.Join([]string{
+			"0000000000",
+			"0111100000",
+			"0100100110",
+			"0111100110",
+			"0000000000",
+		}, "")),
+	)
+	path, err := bmppath.New(bmp, 10)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Printf("coverage: %.0f%%\n", path.InkCoverage())
+
+	// 0.5mm per pixel, engraving 0.2/mm², cutting 0.1/mm, and 1 per pierce
+	est := path.EstimateCost(&bmppath.CostParams{
+		PixelSize:  0.5,
+		AreaCost:   0.2,
+		LengthCost: 0.1,
+		PathCost:   1,
+		BaseCost:   5,
+	})
+	fmt.Printf("area: %.2fmm², length: %.1fmm\n", est.Area, est.Length)
+	fmt.Printf("engrave: %.2f, cut: %.2f, total: %.2f\n", est.EngraveCost, est.CutCost, est.Total)
+
+	// Output:
+	// coverage: 28%
+	// area: 3.50mm², length: 14.0mm
+	// engrave: 0.70, cut: 4.40, total: 10.10
+}
@@ -0,0 +1,300 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strings"
+)
+
+// CurveKind specifies the kind of a Curve.
+type CurveKind int
+
+const (
+	// CurveLine is a straight line.
+	CurveLine CurveKind = iota
+
+	// CurveQuad is a quadratic Bézier curve with the control point C1.
+	CurveQuad
+
+	// CurveCubic is a cubic Bézier curve with the control points C1 and C2.
+	CurveCubic
+
+	// CurveArc is a circular arc shorter than a semicircle, with the radius
+	// Radius.
+	CurveArc
+)
+
+// Curve is a segment of a closed path of CurvePath, running from the end of the
+// previous segment to End.
+type Curve struct {
+	Kind   CurveKind
+	C1, C2 FloatVertex
+	Radius float64
+
+	// Clockwise specifies the direction of CurveArc on the screen
+	// coordinates, which corresponds to the sweep-flag of SVG.
+	Clockwise bool
+
+	End FloatVertex
+}
+
+// CurvePath is a set of closed paths consisting of curves, created by the
+// operations such as RoundCorners. Each closed path starts from the End of its
+// last Curve.
+type CurvePath struct {
+	Width, Height float64
+	Curves        [][]Curve
+}
+
+// RoundCorners returns a new CurvePath with all the corners of p replaced by
+// the circular arcs of the radius r. The radius is reduced at the corners
+// where the adjacent segments are too short, so that each segment is rounded
+// by at most a half of its length from each end.
+func (p *Path) RoundCorners(r float64) *CurvePath {
+	return p.Float().RoundCorners(r)
+}
+
+// RoundCorners is identical to Path.RoundCorners except that it rounds fp.
+func (fp *FloatPath) RoundCorners(r float64) *CurvePath {
+	ret := &CurvePath{
+		Width:  fp.Width,
+		Height: fp.Height,
+		Curves: make([][]Curve, 0, len(fp.Vertices)),
+	}
+	for _, vs := range fp.Vertices {
+		n := len(vs)
+		if n < 3 {
+			continue
+		}
+		cs := make([]Curve, 0, n*2)
+		for i := range vs {
+			v0, v1, v2 := vs[(i+n-1)%n], vs[i], vs[(i+1)%n]
+			d1 := FloatVertex{v1[0] - v0[0], v1[1] - v0[1]}
+			d2 := FloatVertex{v2[0] - v1[0], v2[1] - v1[1]}
+			l1, l2 := math.Hypot(d1[0], d1[1]), math.Hypot(d2[0], d2[1])
+			cross := d1[0]*d2[1] - d1[1]*d2[0]
+			if l1 == 0 || l2 == 0 || cross == 0 || r <= 0 {
+				cs = append(cs, Curve{Kind: CurveLine, End: v1})
+				continue
+			}
+			// the tangent length for the radius r, limited to the half
+			// of each adjacent segment
+			phi := math.Acos(math.Max(-1, math.Min(1, (d1[0]*d2[0]+d1[1]*d2[1])/(l1*l2))))
+			tan := math.Tan(phi / 2)
+			t := math.Min(r*tan, math.Min(l1, l2)/2)
+			cs = append(cs,
+				Curve{Kind: CurveLine, End: FloatVertex{v1[0] - d1[0]/l1*t, v1[1] - d1[1]/l1*t}},
+				Curve{
+					Kind:      CurveArc,
+					Radius:    t / tan,
+					Clockwise: 0 < cross,
+					End:       FloatVertex{v1[0] + d2[0]/l2*t, v1[1] + d2[1]/l2*t},
+				},
+			)
+		}
+		// start from the end of the first arc, or the first vertex
+		cs = append(cs[1:], cs[0])
+		ret.Curves = append(ret.Curves, cs)
+	}
+	return ret
+}
+
+// NumPath returns the number of closed paths in this set of paths.
+func (cp *CurvePath) NumPath() int { return len(cp.Curves) }
+
+// SVGDString is identical to WriteSVGD except that it returns a string instead
+// of writing to io.Writer.
+func (cp *CurvePath) SVGDString() string {
+	var sb strings.Builder
+	_ = cp.WriteSVGD(&sb)
+	return sb.String()
+}
+
+// WriteSVGD writes the 'd' string of the closed paths to w, in the same manner
+// as Path.WriteSVGD. The curves are written natively with the q, c, and a
+// commands. It is the same as WriteSVGDFormat with nil formatter.
+func (cp *CurvePath) WriteSVGD(w io.Writer) error {
+	return cp.WriteSVGDFormat(w, nil)
+}
+
+// WriteSVGDFormat is identical to WriteSVGD except that the coordinates are
+// formatted with f. nil f means the shortest representation of each value.
+func (cp *CurvePath) WriteSVGDFormat(w io.Writer, f CoordFormatter) error {
+	if f == nil {
+		f = defaultCoordFormatter
+	}
+	var sb strings.Builder
+	var pen FloatVertex
+	// rel writes the points relative to the current point, and returns the
+	// last one as the readers see it.
+	rel := func(pts ...FloatVertex) FloatVertex {
+		vs := make([]float64, 0, len(pts)*2)
+		for _, pt := range pts {
+			vs = append(vs, pt[0]-pen[0], pt[1]-pen[1])
+		}
+		d := writeSVGNumbers(&sb, f, vs...)
+		return FloatVertex{pen[0] + d[len(d)-2], pen[1] + d[len(d)-1]}
+	}
+	for _, cs := range cp.Curves {
+		if len(cs) == 0 {
+			continue
+		}
+		sb.WriteString("m")
+		pen = rel(cs[len(cs)-1].End)
+		start, pv := pen, cs[len(cs)-1].End
+		for _, c := range cs {
+			switch c.Kind {
+			case CurveQuad:
+				sb.WriteString("q")
+				pen = rel(c.C1, c.End)
+			case CurveCubic:
+				sb.WriteString("c")
+				pen = rel(c.C1, c.C2, c.End)
+			case CurveArc:
+				sb.WriteString("a")
+				writeSVGNumbers(&sb, f, c.Radius, c.Radius)
+				sweep := 0
+				if c.Clockwise {
+					sweep = 1
+				}
+				fmt.Fprintf(&sb, " 0 0 %d ", sweep)
+				pen = rel(c.End)
+			default:
+				switch {
+				case c.End == pv:
+				case c.End[0] == pv[0]:
+					sb.WriteString("v")
+					pen[1] += writeSVGNumbers(&sb, f, c.End[1]-pen[1])[0]
+				case c.End[1] == pv[1]:
+					sb.WriteString("h")
+					pen[0] += writeSVGNumbers(&sb, f, c.End[0]-pen[0])[0]
+				default:
+					sb.WriteString("l")
+					pen = rel(c.End)
+				}
+			}
+			pv = c.End
+		}
+		sb.WriteString("z")
+		pen = start
+		if _, err := io.WriteString(w, sb.String()); err != nil {
+			return fmt.Errorf("write failure: %w", err)
+		}
+		sb.Reset()
+	}
+	return nil
+}
+
+// WriteSVG writes the closed paths as an SVG document, in the same manner as
+// Path.WriteSVG.
+func (cp *CurvePath) WriteSVG(w io.Writer) error {
+	var sb strings.Builder
+	fmt.Fprintln(&sb, `<?xml version="1.0" encoding="utf-8"?>`)
+	fmt.Fprint(&sb, `<svg version="1.1" xmlns="http://www.w3.org/2000/svg"`)
+	fmt.Fprintf(&sb, ` viewBox="0 0 %g %g">`, cp.Width, cp.Height)
+	fmt.Fprintln(&sb)
+	fmt.Fprintf(&sb, `<path fill="#fff" d="m0,0h%gv%gh-%gz"/>`, cp.Width, cp.Height, cp.Width)
+	fmt.Fprintf(&sb, `<path d="%s"/>`, cp.SVGDString())
+	fmt.Fprintln(&sb)
+	fmt.Fprintln(&sb, `</svg>`)
+	if _, err := fmt.Fprint(w, sb.String()); err != nil {
+		return fmt.Errorf("write failure: %w", err)
+	}
+	return nil
+}
+
+// Flatten approximates the curves with the straight lines, so that the maximum
+// distance between them is tol or less, and returns the result as a FloatPath.
+// tol must be positive otherwise it panics.
+func (cp *CurvePath) Flatten(tol float64) *FloatPath {
+	if !(0 < tol) {
+		panic("bmppath: non-positive tolerance")
+	}
+	ret := &FloatPath{
+		Width:    cp.Width,
+		Height:   cp.Height,
+		Vertices: make([][]FloatVertex, 0, len(cp.Curves)),
+	}
+	for _, cs := range cp.Curves {
+		if len(cs) == 0 {
+			continue
+		}
+		var vs []FloatVertex
+		p0 := cs[len(cs)-1].End
+		for _, c := range cs {
+			switch c.Kind {
+			case CurveQuad:
+				l := math.Hypot(p0[0]-2*c.C1[0]+c.End[0], p0[1]-2*c.C1[1]+c.End[1])
+				n := int(math.Ceil(math.Sqrt(l / (4 * tol))))
+				for i := 1; i < n; i++ {
+					t := float64(i) / float64(n)
+					a, b, d := (1-t)*(1-t), 2*(1-t)*t, t*t
+					vs = append(vs, FloatVertex{
+						a*p0[0] + b*c.C1[0] + d*c.End[0],
+						a*p0[1] + b*c.C1[1] + d*c.End[1],
+					})
+				}
+			case CurveCubic:
+				l := math.Max(
+					math.Hypot(p0[0]-2*c.C1[0]+c.C2[0], p0[1]-2*c.C1[1]+c.C2[1]),
+					math.Hypot(c.C1[0]-2*c.C2[0]+c.End[0], c.C1[1]-2*c.C2[1]+c.End[1]),
+				)
+				n := int(math.Ceil(math.Sqrt(3 * l / (4 * tol))))
+				for i := 1; i < n; i++ {
+					t := float64(i) / float64(n)
+					a, b, d, e := (1-t)*(1-t)*(1-t), 3*(1-t)*(1-t)*t, 3*(1-t)*t*t, t*t*t
+					vs = append(vs, FloatVertex{
+						a*p0[0] + b*c.C1[0] + d*c.C2[0] + e*c.End[0],
+						a*p0[1] + b*c.C1[1] + d*c.C2[1] + e*c.End[1],
+					})
+				}
+			case CurveArc:
+				vs = append(vs, arcPoints(p0, c, tol)...)
+			}
+			vs = append(vs, c.End)
+			p0 = c.End
+		}
+		ret.Vertices = append(ret.Vertices, vs)
+	}
+	return ret
+}
+
+// arcPoints returns the points on the arc c from p0, excluding both ends, so
+// that the maximum distance between the arc and the lines is tol or less.
+func arcPoints(p0 FloatVertex, c Curve, tol float64) []FloatVertex {
+	mx, my := (p0[0]+c.End[0])/2, (p0[1]+c.End[1])/2
+	dx, dy := c.End[0]-p0[0], c.End[1]-p0[1]
+	half := math.Hypot(dx, dy) / 2
+	if half == 0 || c.Radius <= 0 {
+		return nil
+	}
+	r := math.Max(c.Radius, half)
+	// the center is on the right of the chord for the clockwise arcs
+	h := math.Sqrt(r*r - half*half)
+	nx, ny := -dy/(2*half), dx/(2*half)
+	if !c.Clockwise {
+		nx, ny = -nx, -ny
+	}
+	cx, cy := mx+nx*h, my+ny*h
+	a0 := math.Atan2(p0[1]-cy, p0[0]-cx)
+	sweep := 2 * math.Asin(math.Min(1, half/r))
+	if !c.Clockwise {
+		sweep = -sweep
+	}
+	step := math.Pi
+	if tol < r {
+		step = 2 * math.Acos(1-tol/r)
+	}
+	n := int(math.Ceil(math.Abs(sweep) / step))
+	ret := make([]FloatVertex, 0, n)
+	for i := 1; i < n; i++ {
+		a := a0 + sweep*float64(i)/float64(n)
+		ret = append(ret, FloatVertex{cx + r*math.Cos(a), cy + r*math.Sin(a)})
+	}
+	return ret
+}
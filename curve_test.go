@@ -0,0 +1,71 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath_test
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/tunabay/go-bitarray"
+	"github.com/tunabay/go-bmppath"
+)
+
+func ExamplePath_RoundCorners() {
+	path, err := bmppath.New(bitarray.NewBufferFromBitArray(bitarray.MustParse("111111")), 3)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(path.RoundCorners(0.5).SVGDString())
+	fmt.Println(path.RoundCorners(5).SVGDString())
+
+	// Output:
+	// m0,0.5a0.5,0.5 0 0 1 0.5-0.5h2a0.5,0.5 0 0 1 0.5,0.5v1a0.5,0.5 0 0 1 -0.5,0.5h-2a0.5,0.5 0 0 1 -0.5-0.5v-1z
+	// m0,1a1,1 0 0 1 1-1h1a1,1 0 0 1 1,1a1,1 0 0 1 -1,1h-1a1,1 0 0 1 -1-1z
+}
+
+func TestCurvePath_Flatten(t *testing.T) {
+	path, err := bmppath.New(bitarray.NewBufferFromBitArray(bitarray.MustParse("1111")), 2)
+	if err != nil {
+		t.Fatalf("New(): %v", err)
+	}
+	for _, tc := range []struct {
+		r, want float64
+	}{
+		{0, 4},
+		{0.5, 4 - (4-math.Pi)/4},
+		{1, math.Pi},
+		{9, math.Pi},
+	} {
+		fp := path.RoundCorners(tc.r).Flatten(1e-4)
+		area := 0.0
+		for _, vs := range fp.Vertices {
+			for i, v0 := range vs {
+				v1 := vs[(i+1)%len(vs)]
+				area += (v0.X()*v1.Y() - v1.X()*v0.Y()) / 2
+			}
+		}
+		if math.Abs(area-tc.want) > 1e-3 {
+			t.Errorf("r=%g: unexpected area: got %g, want %g", tc.r, area, tc.want)
+		}
+	}
+
+	cp := &bmppath.CurvePath{
+		Width:  2,
+		Height: 2,
+		Curves: [][]bmppath.Curve{{
+			{Kind: bmppath.CurveQuad, C1: bmppath.FloatVertex{2, 0}, End: bmppath.FloatVertex{2, 2}},
+			{Kind: bmppath.CurveCubic, C1: bmppath.FloatVertex{2, 2}, C2: bmppath.FloatVertex{0, 2}, End: bmppath.FloatVertex{0, 0}},
+		}},
+	}
+	if got, want := cp.SVGDString(), "m0,0q2,0,2,2c0,0-2,0-2-2z"; got != want {
+		t.Errorf("unexpected d: got %q, want %q", got, want)
+	}
+	for _, v := range cp.Flatten(0.01).Vertices[0] {
+		if v.X() < 0 || 2 < v.X() || v.Y() < 0 || 2 < v.Y() {
+			t.Errorf("vertex out of the hull: %s", v)
+		}
+	}
+}
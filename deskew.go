@@ -0,0 +1,96 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath
+
+import (
+	"math"
+)
+
+// deskew returns a new bitmap rotated back by the dominant skew angle of img,
+// searched within ±maxAngle degrees. The size of the image is not changed.
+func (img *bitmap) deskew(maxAngle float64) *bitmap {
+	angle := img.skewAngle(maxAngle)
+	if angle == 0 {
+		return img
+	}
+	return img.rotate(-angle)
+}
+
+// skewAngle estimates the dominant skew angle of img in degrees, clockwise on
+// the screen coordinates, with the projection profile method. For each
+// candidate angle, the ink pixels are projected onto the axis perpendicular to
+// that angle, and the angle giving the sharpest profile, i.e. the largest sum
+// of the squared bin counts, is chosen. The search is done coarsely first,
+// then refined around the best coarse candidate.
+func (img *bitmap) skewAngle(maxAngle float64) float64 {
+	var ink [][2]float64
+	for y := 0; y < img.height; y++ {
+		for x := 0; x < img.width; x++ {
+			if img.pix[img.width*y+x] {
+				ink = append(ink, [2]float64{float64(x) + 0.5, float64(y) + 0.5})
+			}
+		}
+	}
+	if len(ink) == 0 {
+		return 0
+	}
+	diag := math.Hypot(float64(img.width), float64(img.height))
+	bins := make([]int, int(diag*2)+2)
+	score := func(deg float64) int {
+		for i := range bins {
+			bins[i] = 0
+		}
+		sin, cos := math.Sincos(deg * math.Pi / 180)
+		for _, pt := range ink {
+			bins[int(pt[1]*cos-pt[0]*sin+diag)]++
+		}
+		s := 0
+		for _, n := range bins {
+			s += n * n
+		}
+		return s
+	}
+
+	best, bestScore := 0.0, score(0)
+	search := func(from, to, step float64) {
+		for deg := from; deg <= to+step/2; deg += step {
+			if s := score(deg); bestScore < s {
+				best, bestScore = deg, s
+			}
+		}
+	}
+	search(-maxAngle, maxAngle, 0.5)
+	search(best-0.5, best+0.5, 0.02)
+
+	return best
+}
+
+// rotate returns a new bitmap rotated clockwise by deg degrees around the
+// center of img. Each output pixel is resampled from the source pixels with
+// bilinear interpolation, and then re-thresholded at 0.5.
+func (img *bitmap) rotate(deg float64) *bitmap {
+	ret := &bitmap{width: img.width, height: img.height, pix: make([]bool, len(img.pix))}
+	sin, cos := math.Sincos(deg * math.Pi / 180)
+	cx, cy := float64(img.width)/2, float64(img.height)/2
+	val := func(x, y int) float64 {
+		if img.at(x, y) {
+			return 1
+		}
+		return 0
+	}
+	for y := 0; y < img.height; y++ {
+		for x := 0; x < img.width; x++ {
+			dx, dy := float64(x)+0.5-cx, float64(y)+0.5-cy
+			sx := cx + dx*cos + dy*sin - 0.5
+			sy := cy - dx*sin + dy*cos - 0.5
+			x0, y0 := int(math.Floor(sx)), int(math.Floor(sy))
+			fx, fy := sx-float64(x0), sy-float64(y0)
+			v := val(x0, y0)*(1-fx)*(1-fy) + val(x0+1, y0)*fx*(1-fy) +
+				val(x0, y0+1)*(1-fx)*fy + val(x0+1, y0+1)*fx*fy
+			ret.pix[img.width*y+x] = 0.5 <= v
+		}
+	}
+	return ret
+}
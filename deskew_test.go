@@ -0,0 +1,50 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/tunabay/go-bitarray"
+	"github.com/tunabay/go-bmppath"
+)
+
+func TestNewWithOptions_deskew(t *testing.T) {
+	const width, height = 200, 50
+	sin, cos := math.Sincos(4 * math.Pi / 180)
+	bmp := bitarray.NewBuffer(width * height)
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			dx, dy := float64(x)+0.5-width/2, float64(y)+0.5-height/2
+			u, v := dx*cos+dy*sin, dy*cos-dx*sin
+			if -80 < u && u < 80 && ((-14 < v && v < -8) || (-3 < v && v < 3) || (8 < v && v < 14)) {
+				bmp.PutBitAt(width*y+x, 1)
+			}
+		}
+	}
+	count := func(p *bmppath.Path) int {
+		n := 0
+		for i := 0; i < p.NumPath(); i++ {
+			n += p.PathLen(i)
+		}
+		return n
+	}
+
+	skewed, err := bmppath.New(bmp, width)
+	if err != nil {
+		t.Fatalf("New(): %v", err)
+	}
+	straight, err := bmppath.NewWithOptions(bmp, width, &bmppath.Options{Deskew: true})
+	if err != nil {
+		t.Fatalf("NewWithOptions(): %v", err)
+	}
+	if straight.Width != width || straight.Height != height {
+		t.Errorf("unexpected size: %dx%d", straight.Width, straight.Height)
+	}
+	if ns, nd := count(skewed), count(straight); nd*3 > ns*2 {
+		t.Errorf("not deskewed: %d vertices, %d before deskew", nd, ns)
+	}
+}
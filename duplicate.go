@@ -0,0 +1,134 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Duplicates returns the groups of the closed paths that are identical to each
+// other except for their positions, such as the modules of QR codes or the dots
+// of halftones. Each group contains the indexes of two or more closed paths in
+// ascending order, and the groups are in the order of their first indexes. The
+// starting vertices of the closed paths are not significant, but the directions
+// are.
+func (p *Path) Duplicates() [][]int {
+	units := make([][]int, len(p.Vertices))
+	for i := range units {
+		units[i] = []int{i}
+	}
+	var ret [][]int
+	for _, g := range p.duplicates(units) {
+		if 1 < len(g) {
+			ret = append(ret, g)
+		}
+	}
+	return ret
+}
+
+// WriteSVGUse is identical to WriteSVG except that the regions, each of which
+// consists of an outline and the holes directly inside it, appearing more than
+// once are written only once in the <defs> element, and are referenced by the
+// <use> elements at each position. This dramatically reduces the size of the
+// documents of images with many identical shapes. The regions appearing only
+// once are written in a single <path> element as WriteSVG does.
+func (p *Path) WriteSVGUse(w io.Writer) error {
+	groups := p.groups()
+	var defs, uses strings.Builder
+	var single []int
+	for _, dup := range p.duplicates(groups) {
+		if len(dup) == 1 {
+			single = append(single, groups[dup[0]]...)
+			continue
+		}
+		id := fmt.Sprintf("s%d", dup[0])
+		anchor := canonicalVertices(p.Vertices[groups[dup[0]][0]])[0]
+		fmt.Fprintf(&defs, `<path id="%s" d="`, id)
+		if err := p.writeGroupSVGD(&defs, groups[dup[0]], anchor); err != nil {
+			return err
+		}
+		defs.WriteString(`"/>`)
+		for _, n := range dup {
+			a := canonicalVertices(p.Vertices[groups[n][0]])[0]
+			fmt.Fprintf(&uses, `<use xlink:href="#%s" x="%d" y="%d"/>`, id, a[0], a[1])
+		}
+	}
+	sort.Ints(single)
+
+	var sb strings.Builder
+	fmt.Fprintln(&sb, `<?xml version="1.0" encoding="utf-8"?>`)
+	fmt.Fprint(&sb, `<svg version="1.1" xmlns="http://www.w3.org/2000/svg"`)
+	fmt.Fprint(&sb, ` xmlns:xlink="http://www.w3.org/1999/xlink"`)
+	fmt.Fprintf(&sb, ` viewBox="0 0 %d %d">`, p.Width, p.Height)
+	fmt.Fprintln(&sb)
+	fmt.Fprintf(&sb, `<path fill="#fff" d="m0,0h%dv%dh-%dz"/>`, p.Width, p.Height, p.Width)
+	if defs.Len() != 0 {
+		fmt.Fprintf(&sb, "<defs>%s</defs>%s", defs.String(), uses.String())
+	}
+	if len(single) != 0 {
+		sb.WriteString(`<path d="`)
+		if err := p.writeGroupSVGD(&sb, single, Vertex{}); err != nil {
+			return err
+		}
+		sb.WriteString(`"/>`)
+	}
+	fmt.Fprintln(&sb)
+	fmt.Fprintln(&sb, `</svg>`)
+	if _, err := fmt.Fprint(w, sb.String()); err != nil {
+		return fmt.Errorf("write failure: %w", err)
+	}
+	return nil
+}
+
+// writeGroupSVGD writes the 'd' string of the closed paths specified by the
+// indexes g, relative to the point z.
+func (p *Path) writeGroupSVGD(w io.Writer, g []int, z Vertex) error {
+	for _, n := range g {
+		if err := pathSVGD(w, p.Vertices[n], z); err != nil {
+			return err
+		}
+		z = p.Vertices[n][0]
+	}
+	return nil
+}
+
+// duplicates classifies the units, each of which is a list of the indexes of
+// the closed paths, into the groups of the units identical to each other except
+// for their positions. Each group contains the indexes of the units in
+// ascending order, and the groups are in the order of their first indexes.
+func (p *Path) duplicates(units [][]int) [][]int {
+	var ret [][]int
+	gidx := make(map[string]int)
+	for i, u := range units {
+		key := p.shapeKey(u)
+		if g, ok := gidx[key]; ok {
+			ret[g] = append(ret[g], i)
+			continue
+		}
+		gidx[key] = len(ret)
+		ret = append(ret, []int{i})
+	}
+	return ret
+}
+
+// shapeKey returns the string that identifies the shape of the closed paths
+// specified by the indexes u regardless of its position. The first closed path
+// is significant, and the order of the rest is not.
+func (p *Path) shapeKey(u []int) string {
+	anchor := canonicalVertices(p.Vertices[u[0]])[0]
+	keys := make([]string, len(u))
+	for i, n := range u {
+		vs := canonicalVertices(p.Vertices[n])
+		for j, v := range vs {
+			vs[j] = Vertex{v[0] - anchor[0], v[1] - anchor[1]}
+		}
+		keys[i] = vertsString(vs)
+	}
+	sort.Strings(keys[1:])
+	return strings.Join(keys, "; ")
+}
@@ -0,0 +1,61 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath_test
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/tunabay/go-bitarray"
+	"github.com/tunabay/go-bmppath"
+)
+
+func ExamplePath_Duplicates() {
+	bmp := bitarray.NewBufferFromBitArray(
+		bitarray.MustParse(strings.Join([]string{
+			"11011",
+			"11011",
+			"00000",
+			"10010",
+		}, "")),
+	)
+	path, err := bmppath.New(bmp, 5)
+	if err != nil {
+		panic(err)
+	}
+
+	for _, g := range path.Duplicates() {
+		fmt.Println(g)
+	}
+
+	// Output:
+	// [0 1]
+	// [2 3]
+}
+
+func ExamplePath_WriteSVGUse() {
+	bmp := bitarray.NewBufferFromBitArray(
+		bitarray.MustParse(strings.Join([]string{
+			"1110111",
+			"1010101",
+			"1110111",
+			"0000000",
+			"1100000",
+		}, "")),
+	)
+	path, err := bmppath.New(bmp, 7)
+	if err != nil {
+		panic(err)
+	}
+
+	_ = path.WriteSVGUse(os.Stdout)
+
+	// Output:
+	// <?xml version="1.0" encoding="utf-8"?>
+	// <svg version="1.1" xmlns="http://www.w3.org/2000/svg" xmlns:xlink="http://www.w3.org/1999/xlink" viewBox="0 0 7 5">
+	// <path fill="#fff" d="m0,0h7v5h-7z"/><defs><path id="s0" d="m0,0h3v3h-3zm1,1v1h1v-1z"/></defs><use xlink:href="#s0" x="0" y="0"/><use xlink:href="#s0" x="4" y="0"/><path d="m0,4h2v1h-2z"/>
+	// </svg>
+}
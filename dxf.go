@@ -0,0 +1,37 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// WriteDXF writes the set of paths as a minimal AutoCAD R12 DXF document, with
+// each closed path as a closed POLYLINE entity on the layer "0". Since the
+// y-axis of DXF points upward, the paths are flipped vertically so that the
+// image is not upside down, with the lower left corner of the canvas at the
+// origin.
+func (p *Path) WriteDXF(w io.Writer) error {
+	var sb strings.Builder
+	sb.WriteString("0\nSECTION\n2\nENTITIES\n")
+	for _, vs := range p.Vertices {
+		sb.WriteString("0\nPOLYLINE\n8\n0\n66\n1\n70\n1\n")
+		for _, v := range vs {
+			fmt.Fprintf(&sb, "0\nVERTEX\n8\n0\n10\n%d\n20\n%d\n", v[0], p.Height-v[1])
+		}
+		sb.WriteString("0\nSEQEND\n")
+		if _, err := io.WriteString(w, sb.String()); err != nil {
+			return fmt.Errorf("write failure: %w", err)
+		}
+		sb.Reset()
+	}
+	sb.WriteString("0\nENDSEC\n0\nEOF\n")
+	if _, err := io.WriteString(w, sb.String()); err != nil {
+		return fmt.Errorf("write failure: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,28 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath_test
+
+import (
+	"os"
+	"strings"
+
+	"github.com/tunabay/go-bitarray"
+	"github.com/tunabay/go-bmppath"
+)
+
+func ExamplePath_WriteDXF() {
+	bmp := bitarray.NewBufferFromBitArray(bitarray.MustParse("10"))
+	path, err := bmppath.New(bmp, 2)
+	if err != nil {
+		panic(err)
+	}
+
+	var sb strings.Builder
+	_ = path.WriteDXF(&sb)
+	_, _ = os.Stdout.WriteString(strings.ReplaceAll(sb.String(), "\n", " "))
+
+	// Output:
+	// 0 SECTION 2 ENTITIES 0 POLYLINE 8 0 66 1 70 1 0 VERTEX 8 0 10 0 20 1 0 VERTEX 8 0 10 1 20 1 0 VERTEX 8 0 10 1 20 0 0 VERTEX 8 0 10 0 20 0 0 SEQEND 0 ENDSEC 0 EOF
+}
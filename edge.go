@@ -0,0 +1,267 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/bits"
+
+	"github.com/tunabay/go-bitarray"
+)
+
+// Direction is the direction of an Edge on the screen coordinates.
+type Direction int
+
+const (
+	// DirUp is the direction toward the negative y.
+	DirUp Direction = iota
+
+	// DirRight is the direction toward the positive x.
+	DirRight
+
+	// DirDown is the direction toward the positive y.
+	DirDown
+
+	// DirLeft is the direction toward the negative x.
+	DirLeft
+)
+
+// String returns the name of the Direction.
+func (d Direction) String() string {
+	switch d {
+	case DirUp:
+		return "up"
+	case DirRight:
+		return "right"
+	case DirDown:
+		return "down"
+	case DirLeft:
+		return "left"
+	}
+	return fmt.Sprintf("Direction(%d)", int(d))
+}
+
+// Edge is a crack edge between an ink pixel and a background pixel, the line
+// segment of length one from the grid point From in the direction Dir. The ink
+// pixel is always on the right side of the edge on the screen, so the edges run
+// clockwise around the ink and counterclockwise around the holes.
+type Edge struct {
+	From Vertex
+	Dir  Direction
+}
+
+// String returns the string representation of an Edge in "(x, y) dir" format.
+func (e Edge) String() string { return fmt.Sprintf("%s %s", e.From, e.Dir) }
+
+// To returns the grid point where the Edge ends.
+func (e Edge) To() Vertex {
+	switch e.Dir {
+	case DirUp:
+		return Vertex{e.From[0], e.From[1] - 1}
+	case DirRight:
+		return Vertex{e.From[0] + 1, e.From[1]}
+	case DirDown:
+		return Vertex{e.From[0], e.From[1] + 1}
+	}
+	return Vertex{e.From[0] - 1, e.From[1]}
+}
+
+// EdgeGrid is the set of the directed crack edges of a bitmap image, the
+// intermediate result of New before the edges are linked into the closed paths.
+// It is exposed so that the alternative strategies to link the edges can reuse
+// the edge extraction. The grid points range from (0, 0) to (Width, Height).
+type EdgeGrid struct {
+	Width, Height int
+
+	// planes holds a bitmap for each direction, in which the bit of the
+	// grid point (x, y) is the bit (Width+1)*y+x, the LSB first in each
+	// word. It is set if the edge starts from the point in the direction.
+	planes [4][]uint64
+
+	// origin is added to the coordinates of the traced paths, when the
+	// grid is a part of a larger canvas.
+	origin Vertex
+}
+
+// has reports whether the edge from the grid point with the index i in the
+// direction d exists.
+func (g *EdgeGrid) has(i, d int) bool {
+	return g.planes[d][i>>6]&(1<<(i&63)) != 0
+}
+
+// set adds the edge from the grid point with the index i in the direction d.
+func (g *EdgeGrid) set(i, d int) {
+	g.planes[d][i>>6] |= 1 << (i & 63)
+}
+
+// take removes the edge from the grid point with the index i in the direction
+// d, and reports whether it existed.
+func (g *EdgeGrid) take(i, d int) bool {
+	w := &g.planes[d][i>>6]
+	m := uint64(1) << (i & 63)
+	if *w&m == 0 {
+		return false
+	}
+	*w &^= m
+	return true
+}
+
+// NewEdgeGrid extracts the crack edges from a binary bitmap image in the same
+// manner as New. The bitmap image is preprocessed as specified by opts, and nil
+// opts is the same as the zero value of Options.
+func NewEdgeGrid(bm *bitarray.Buffer, width int, opts *Options) (*EdgeGrid, error) {
+	height, err := bitmapHeight(bm, width)
+	if err != nil {
+		return nil, err
+	}
+	if opts != nil {
+		if bm, width, height, err = opts.prepare(bm, width, height); err != nil {
+			return nil, err
+		}
+	}
+	return newEdgeGrid(bm, width, height)
+}
+
+// newEdgeGrid extracts the crack edges from the bitmap image bm of the size
+// width x height. It returns ErrTooLarge if the number of the grid points
+// overflows int.
+func newEdgeGrid(bm *bitarray.Buffer, width, height int) (*EdgeGrid, error) {
+	return newEdgeGridBuffer(bm, width, height, nil)
+}
+
+// newEdgeGridBuffer is identical to newEdgeGrid except that the edges are
+// stored in buf if it is long enough, instead of a newly allocated buffer. The
+// first plane of the result starts at the beginning of the buffer, and its
+// capacity is that of the whole buffer, so that the buffer can be reused again.
+func newEdgeGridBuffer(bm *bitarray.Buffer, width, height int, buf []uint64) (*EdgeGrid, error) {
+	n, ok := mulInt(width+1, height+1)
+	if ok {
+		n, ok = addInt(n, 63)
+	}
+	if !ok {
+		return nil, fmt.Errorf("%w: %dx%d grid points", ErrTooLarge, width+1, height+1)
+	}
+	pw := n >> 6
+	if cap(buf) < pw<<2 {
+		buf = make([]uint64, pw<<2)
+	} else {
+		buf = buf[:pw<<2]
+		for k := range buf {
+			buf[k] = 0
+		}
+	}
+	g := &EdgeGrid{Width: width, Height: height}
+	for d := range g.planes {
+		g.planes[d] = buf[d*pw : (d+1)*pw]
+	}
+	set := func(x, y int, dir Direction) {
+		g.set((width+1)*y+x, int(dir))
+	}
+
+	// The rows are read as the 64-bit words, the MSB first as in bm, and
+	// the edges of 64 pixels are found at a time by the bitwise operations
+	// on the words. The words hold width+1 bits so that the right border of
+	// the image is included, and the rows outside the image are zero.
+	nw := (width + 64) >> 6
+	rowBytes := make([]byte, nw<<3)
+	words := make([]uint64, 4*nw)
+	prev, cur, e0, e1 := words[:nw], words[nw:2*nw], words[2*nw:3*nw], words[3*nw:]
+	// found calls set for each of the set bits in w, offset by x0.
+	found := func(w []uint64, x0, y int, dir Direction) {
+		for k, v := range w {
+			for v != 0 {
+				lz := bits.LeadingZeros64(v)
+				set(k<<6+lz+x0, y, dir)
+				v &^= 1 << (63 - lz)
+			}
+		}
+	}
+	for y := 0; y < height+1; y++ {
+		prev, cur = cur, prev
+		if y < height {
+			bm.CopyBitsToBytes(width*y, rowBytes, 0, width)
+			for k := range cur {
+				cur[k] = binary.BigEndian.Uint64(rowBytes[k<<3:])
+			}
+		} else {
+			for k := range cur {
+				cur[k] = 0
+			}
+		}
+
+		// the horizontal edges between the pixel rows y-1 and y
+		for k := range cur {
+			e0[k] = cur[k] &^ prev[k]
+			e1[k] = prev[k] &^ cur[k]
+		}
+		found(e0, 0, y, DirRight)
+		found(e1, 1, y, DirLeft)
+		if y == height {
+			break
+		}
+
+		// the vertical edges between the pixel columns x-1 and x in the
+		// row y, comparing the row with itself shifted by a pixel
+		var carry uint64
+		for k, v := range cur {
+			l := v>>1 | carry<<63
+			carry = v
+			e0[k] = v &^ l
+			e1[k] = l &^ v
+		}
+		found(e0, 0, y+1, DirUp)
+		found(e1, 0, y, DirDown)
+	}
+	return g, nil
+}
+
+// Has reports whether the edge from the grid point v in the direction d exists.
+// It returns false for the points outside the grid.
+func (g *EdgeGrid) Has(v Vertex, d Direction) bool {
+	if v[0] < 0 || v[1] < 0 || g.Width < v[0] || g.Height < v[1] || d < DirUp || DirLeft < d {
+		return false
+	}
+	return g.has((g.Width+1)*v[1]+v[0], int(d))
+}
+
+// NumEdges returns the number of the edges.
+func (g *EdgeGrid) NumEdges() int {
+	n := 0
+	for _, plane := range g.planes {
+		for _, w := range plane {
+			n += bits.OnesCount64(w)
+		}
+	}
+	return n
+}
+
+// Edges returns all the edges, ordered by the y-coordinate, the x-coordinate,
+// and the direction of them.
+func (g *EdgeGrid) Edges() []Edge {
+	ret := make([]Edge, 0, g.NumEdges())
+	for y := 0; y <= g.Height; y++ {
+		for x := 0; x <= g.Width; x++ {
+			for d := DirUp; d <= DirLeft; d++ {
+				if g.Has(Vertex{x, y}, d) {
+					ret = append(ret, Edge{From: Vertex{x, y}, Dir: d})
+				}
+			}
+		}
+	}
+	return ret
+}
+
+// Path links the edges into the closed paths with the strategy of New, and
+// returns the result, which is the same as New returns for the bitmap image.
+// g itself is not modified.
+func (g *EdgeGrid) Path() *Path {
+	c := &EdgeGrid{Width: g.Width, Height: g.Height}
+	for d, plane := range g.planes {
+		c.planes[d] = append([]uint64(nil), plane...)
+	}
+	ret, _ := c.trace(nil, &arena{})
+	return ret
+}
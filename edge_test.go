@@ -0,0 +1,133 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath_test
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/tunabay/go-bitarray"
+	"github.com/tunabay/go-bmppath"
+)
+
+func ExampleEdgeGrid() {
+	bmp := bitarray.NewBufferFromBitArray(bitarray.MustParse(strings.Join([]string{
+		"01",
+		"11",
+	}, "")))
+	g, err := bmppath.NewEdgeGrid(bmp, 2, nil)
+	if err != nil {
+		panic(err)
+	}
+
+	for _, e := range g.Edges() {
+		fmt.Println(e, "->", e.To())
+	}
+	fmt.Println(g.Path().SVGDString())
+
+	// Output:
+	// (1, 0) right -> (2, 0)
+	// (2, 0) down -> (2, 1)
+	// (0, 1) right -> (1, 1)
+	// (1, 1) up -> (1, 0)
+	// (2, 1) down -> (2, 2)
+	// (0, 2) up -> (0, 1)
+	// (1, 2) left -> (0, 2)
+	// (2, 2) left -> (1, 2)
+	// m1,0h1v2h-2v-1h1z
+}
+
+func TestEdgeGrid_Path(t *testing.T) {
+	for name, bmp := range conformanceCorpus() {
+		g, err := bmppath.NewEdgeGrid(bmp, 8, nil)
+		if err != nil {
+			t.Fatalf("%s: NewEdgeGrid(): %v", name, err)
+		}
+		want, err := bmppath.New(bmp, 8)
+		if err != nil {
+			t.Fatalf("%s: New(): %v", name, err)
+		}
+		n := g.NumEdges()
+		if got := g.Path(); !got.Equal(want) {
+			t.Errorf("%s: unexpected path: got %s, want %s", name, got.SVGDString(), want.SVGDString())
+		}
+		if g.NumEdges() != n {
+			t.Errorf("%s: edges consumed: %d -> %d", name, n, g.NumEdges())
+		}
+		if want := want.Perimeter(); n != want {
+			t.Errorf("%s: unexpected number of edges: got %d, want %d", name, n, want)
+		}
+
+		// each grid point has as many outgoing edges as incoming ones
+		degree := map[bmppath.Vertex]int{}
+		for _, e := range g.Edges() {
+			degree[e.From]++
+			degree[e.To()]--
+		}
+		for v, d := range degree {
+			if d != 0 {
+				t.Errorf("%s: unbalanced grid point %s: %d", name, v, d)
+			}
+		}
+	}
+}
+
+func TestNewEdgeGrid_wide(t *testing.T) {
+	// the widths around the multiples of 64, and a bitmap image not
+	// starting at a byte boundary
+	r := rand.New(rand.NewSource(1))
+	for _, width := range []int{1, 7, 63, 64, 65, 127, 128, 129, 200} {
+		const height, offset = 5, 3
+		buf := bitarray.NewBuffer(width*height + offset)
+		for i := 0; i < buf.Len(); i++ {
+			if r.Intn(2) == 0 {
+				buf.PutBitAt(i, 1)
+			}
+		}
+		bmp := buf.Slice(offset, buf.Len())
+		g, err := bmppath.NewEdgeGrid(bmp, width, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pix := func(x, y int) bool {
+			return 0 <= x && x < width && 0 <= y && y < height && bmp.BitAt(width*y+x) != 0
+		}
+		// the ink is on the right side of each edge
+		for y := 0; y <= height; y++ {
+			for x := 0; x <= width; x++ {
+				want := [4]bool{
+					bmppath.DirUp:    pix(x, y-1) && !pix(x-1, y-1),
+					bmppath.DirRight: pix(x, y) && !pix(x, y-1),
+					bmppath.DirDown:  pix(x-1, y) && !pix(x, y),
+					bmppath.DirLeft:  pix(x-1, y-1) && !pix(x-1, y),
+				}
+				for d, w := range want {
+					if got := g.Has(bmppath.Vertex{x, y}, bmppath.Direction(d)); got != w {
+						t.Errorf("width=%d: edge from (%d, %d) in direction %d: got %t, want %t", width, x, y, d, got, w)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestNewEdgeGrid_tooLarge(t *testing.T) {
+	// the pixels fit in int32 but the grid points do not, which can be
+	// tested only on the 32-bit platforms
+	if intSize != 32 || testing.Short() {
+		t.Skip("32-bit platforms only")
+	}
+	const width = 46340
+	bmp := bitarray.NewBuffer(width * width)
+	if _, err := bmppath.NewEdgeGrid(bmp, width, nil); !errors.Is(err, bmppath.ErrTooLarge) {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if _, err := bmppath.New(bmp, width); !errors.Is(err, bmppath.ErrTooLarge) {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,83 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strings"
+)
+
+// EPSOptions represents the options for WriteEPSOptions.
+type EPSOptions struct {
+	// Marks, if not nil, adds the bleed and the printer's marks around the
+	// canvas. The bounding box is extended to contain them, rounded up to
+	// the whole points.
+	Marks *PrintMarks
+}
+
+// WriteEPS writes the set of paths as an Encapsulated PostScript document, with
+// each closed path constructed by the moveto, lineto, and closepath operators
+// and all of them filled at once with the nonzero winding rule. The bounding
+// box is the canvas, one point per pixel. Since the y-axis of PostScript points
+// upward, the paths are flipped vertically in the same manner as WriteDXF. It
+// is the same as WriteEPSOptions with nil opts.
+func (p *Path) WriteEPS(w io.Writer) error {
+	return p.WriteEPSOptions(w, nil)
+}
+
+// WriteEPSOptions is identical to WriteEPS except that the document is
+// customized with opts. nil opts is the same as the zero value of EPSOptions.
+func (p *Path) WriteEPSOptions(w io.Writer, opts *EPSOptions) error {
+	if opts == nil {
+		opts = &EPSOptions{}
+	}
+	e := math.Ceil(opts.Marks.extent())
+	ie := int(e)
+
+	var sb strings.Builder
+	sb.WriteString("%!PS-Adobe-3.0 EPSF-3.0\n")
+	fmt.Fprintf(&sb, "%%%%BoundingBox: 0 0 %d %d\n", p.Width+ie*2, p.Height+ie*2)
+	sb.WriteString("%%Pages: 0\n%%EndComments\n")
+	if lines, circles := opts.Marks.shapes(float64(p.Width), float64(p.Height)); len(lines) != 0 || len(circles) != 0 {
+		f := FormatCoord(-1, "")
+		_, _, lw := opts.Marks.params()
+		pt := func(x, y float64) string { return f(e+x) + " " + f(e+float64(p.Height)-y) }
+		fmt.Fprintf(&sb, "gsave\n%s setlinewidth\nnewpath\n", f(lw))
+		for _, l := range lines {
+			fmt.Fprintf(&sb, "%s moveto\n%s lineto\n", pt(l[0][0], l[0][1]), pt(l[1][0], l[1][1]))
+		}
+		for _, c := range circles {
+			fmt.Fprintf(&sb, "%s moveto\n", pt(c.Center[0]+c.Radius, c.Center[1]))
+			fmt.Fprintf(&sb, "%s %s 0 360 arc\nclosepath\n", pt(c.Center[0], c.Center[1]), f(c.Radius))
+		}
+		sb.WriteString("stroke\ngrestore\n")
+	}
+	sb.WriteString("gsave\n")
+	if ie != 0 {
+		fmt.Fprintf(&sb, "%d %d translate\n", ie, ie)
+	}
+	sb.WriteString("newpath\n")
+	for _, vs := range p.Vertices {
+		for i, v := range vs {
+			op := "lineto"
+			if i == 0 {
+				op = "moveto"
+			}
+			fmt.Fprintf(&sb, "%d %d %s\n", v[0], p.Height-v[1], op)
+		}
+		sb.WriteString("closepath\n")
+		if _, err := io.WriteString(w, sb.String()); err != nil {
+			return fmt.Errorf("write failure: %w", err)
+		}
+		sb.Reset()
+	}
+	sb.WriteString("fill\ngrestore\n%%EOF\n")
+	if _, err := io.WriteString(w, sb.String()); err != nil {
+		return fmt.Errorf("write failure: %w", err)
+	}
+	return nil
+}
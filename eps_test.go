@@ -0,0 +1,40 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath_test
+
+import (
+	"os"
+
+	"github.com/tunabay/go-bitarray"
+	"github.com/tunabay/go-bmppath"
+)
+
+func ExamplePath_WriteEPS() {
+	bmp := bitarray.NewBufferFromBitArray(bitarray.MustParse("0110"))
+	path, err := bmppath.New(bmp, 4)
+	if err != nil {
+		panic(err)
+	}
+
+	if err := path.WriteEPS(os.Stdout); err != nil {
+		panic(err)
+	}
+
+	// Output:
+	// %!PS-Adobe-3.0 EPSF-3.0
+	// %%BoundingBox: 0 0 4 1
+	// %%Pages: 0
+	// %%EndComments
+	// gsave
+	// newpath
+	// 1 1 moveto
+	// 3 1 lineto
+	// 3 0 lineto
+	// 1 0 lineto
+	// closepath
+	// fill
+	// grestore
+	// %%EOF
+}
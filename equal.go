@@ -0,0 +1,118 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Equal reports whether p and q represent the same set of closed paths on the
+// same size of canvas. The order of the closed paths and the starting vertex of
+// each closed path are not significant, but the direction is. The labels are
+// not compared.
+func (p *Path) Equal(q *Path) bool {
+	if p.Width != q.Width || p.Height != q.Height || len(p.Vertices) != len(q.Vertices) {
+		return false
+	}
+	pc, qc := p.canonical(), q.canonical()
+	for i := range pc {
+		if compareVertices(pc[i], qc[i]) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Diff returns the human readable differences between p and q in the same
+// sense as Equal, or an empty string if they are equal. Each closed path found
+// only in p is reported in a line starting with "-", and each one found only in
+// q is reported in a line starting with "+". The closed paths are shown in the
+// canonical form starting from the upper left vertex. This is useful for the
+// golden-file testing of generated paths.
+func Diff(p, q *Path) string {
+	var sb strings.Builder
+	if p.Width != q.Width || p.Height != q.Height {
+		fmt.Fprintf(&sb, "size: %dx%d != %dx%d\n", p.Width, p.Height, q.Width, q.Height)
+	}
+	pc, qc := p.canonical(), q.canonical()
+	i, j := 0, 0
+	for i < len(pc) || j < len(qc) {
+		c := 0
+		switch {
+		case i == len(pc):
+			c = 1
+		case j == len(qc):
+			c = -1
+		default:
+			c = compareVertices(pc[i], qc[j])
+		}
+		switch {
+		case c < 0:
+			fmt.Fprintf(&sb, "- %s\n", vertsString(pc[i]))
+			i++
+		case 0 < c:
+			fmt.Fprintf(&sb, "+ %s\n", vertsString(qc[j]))
+			j++
+		default:
+			i++
+			j++
+		}
+	}
+	return sb.String()
+}
+
+// canonical returns the closed paths of p, each rotated to start from its
+// upper left vertex, sorted in a fixed order.
+func (p *Path) canonical() [][]Vertex {
+	ret := make([][]Vertex, len(p.Vertices))
+	for i, vs := range p.Vertices {
+		ret[i] = canonicalVertices(vs)
+	}
+	sort.Slice(ret, func(i, j int) bool { return compareVertices(ret[i], ret[j]) < 0 })
+	return ret
+}
+
+// canonicalVertices returns a copy of the closed path vs rotated to start from
+// its upper left vertex.
+func canonicalVertices(vs []Vertex) []Vertex {
+	mini := 0
+	for j, v := range vs {
+		if lessVertex(v, vs[mini]) {
+			mini = j
+		}
+	}
+	return append(append(make([]Vertex, 0, len(vs)), vs[mini:]...), vs[:mini]...)
+}
+
+// lessVertex reports whether a precedes b in the order of y, then x.
+func lessVertex(a, b Vertex) bool {
+	if a[1] != b[1] {
+		return a[1] < b[1]
+	}
+	return a[0] < b[0]
+}
+
+// compareVertices compares two closed paths lexicographically.
+func compareVertices(a, b []Vertex) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		switch {
+		case lessVertex(a[i], b[i]):
+			return -1
+		case lessVertex(b[i], a[i]):
+			return 1
+		}
+	}
+	return len(a) - len(b)
+}
+
+func vertsString(vs []Vertex) string {
+	f := make([]string, len(vs))
+	for i, v := range vs {
+		f[i] = v.String()
+	}
+	return strings.Join(f, ", ")
+}
@@ -0,0 +1,69 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/tunabay/go-bmppath"
+)
+
+func TestPath_Equal(t *testing.T) {
+	p := &bmppath.Path{
+		Width:  4,
+		Height: 2,
+		Vertices: [][]bmppath.Vertex{
+			{{0, 0}, {1, 0}, {1, 1}, {0, 1}},
+			{{2, 0}, {3, 0}, {3, 2}, {2, 2}},
+		},
+	}
+	q := &bmppath.Path{
+		Width:  4,
+		Height: 2,
+		Vertices: [][]bmppath.Vertex{
+			{{3, 2}, {2, 2}, {2, 0}, {3, 0}},
+			{{1, 1}, {0, 1}, {0, 0}, {1, 0}},
+		},
+	}
+	if !p.Equal(q) || !q.Equal(p) {
+		t.Errorf("unexpectedly not equal: %s", bmppath.Diff(p, q))
+	}
+	if d := bmppath.Diff(p, q); d != "" {
+		t.Errorf("unexpected diff: %q", d)
+	}
+	if r := p.Reverse(); p.Equal(r) {
+		t.Errorf("reversed path unexpectedly equal")
+	}
+	if r := p.Translate(1, 0); p.Equal(r) {
+		t.Errorf("translated path unexpectedly equal")
+	}
+}
+
+func ExampleDiff() {
+	p := &bmppath.Path{
+		Width:  4,
+		Height: 2,
+		Vertices: [][]bmppath.Vertex{
+			{{0, 0}, {1, 0}, {1, 1}, {0, 1}},
+			{{2, 0}, {3, 0}, {3, 2}, {2, 2}},
+		},
+	}
+	q := &bmppath.Path{
+		Width:  4,
+		Height: 3,
+		Vertices: [][]bmppath.Vertex{
+			{{3, 0}, {3, 2}, {2, 2}, {2, 0}},
+			{{0, 1}, {0, 2}, {1, 2}, {1, 1}},
+		},
+	}
+
+	fmt.Print(bmppath.Diff(p, q))
+
+	// Output:
+	// size: 4x2 != 4x3
+	// - (0, 0), (1, 0), (1, 1), (0, 1)
+	// + (0, 1), (0, 2), (1, 2), (1, 1)
+}
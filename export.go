@@ -0,0 +1,160 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ExportFormat specifies the file format written by ExportContours.
+type ExportFormat int
+
+const (
+	// ExportSVG writes SVG documents with WriteSVG.
+	ExportSVG ExportFormat = iota
+
+	// ExportDXF writes DXF documents with WriteDXF.
+	ExportDXF
+
+	// ExportPDF writes PDF documents with WritePDF.
+	ExportPDF
+
+	// ExportEPS writes EPS documents with WriteEPS.
+	ExportEPS
+
+	// ExportVectorDrawable writes Android VectorDrawable XML resources with
+	// WriteVectorDrawable.
+	ExportVectorDrawable
+)
+
+// writer returns the function to write a Path in the format f with the
+// printer's marks, and the file name extension. The marks are not written in
+// DXF and VectorDrawable.
+func (f ExportFormat) writer(marks *PrintMarks) (func(p *Path, w io.Writer) error, string) {
+	switch f {
+	case ExportDXF:
+		return (*Path).WriteDXF, ".dxf"
+	case ExportPDF:
+		return func(p *Path, w io.Writer) error {
+			return p.WritePDF(w, &PDFOptions{Marks: marks})
+		}, ".pdf"
+	case ExportEPS:
+		return func(p *Path, w io.Writer) error {
+			return p.WriteEPSOptions(w, &EPSOptions{Marks: marks})
+		}, ".eps"
+	case ExportVectorDrawable:
+		return func(p *Path, w io.Writer) error {
+			return p.WriteVectorDrawable(w, nil)
+		}, ".xml"
+	}
+	if marks == nil {
+		return (*Path).WriteSVG, ".svg"
+	}
+	return func(p *Path, w io.Writer) error {
+		return p.WriteSVGOptions(w, &SVGOptions{Marks: marks})
+	}, ".svg"
+}
+
+// ExportOptions represents the options for ExportContours.
+type ExportOptions struct {
+	// Format is the file format to write.
+	Format ExportFormat
+
+	// Group writes each outline together with the holes inside it into one
+	// file, instead of writing every closed path into its own file.
+	Group bool
+
+	// Name returns the file name without the extension for the n-th file. If
+	// nil, the label of the closed path, or of the outline when Group is
+	// set, is used. The unlabeled ones are named "contour-0", "contour-1",
+	// ... by the index of the file.
+	Name func(n int) string
+}
+
+// ExportContours writes each closed path, or each group of an outline and its
+// holes, of p into its own file in the directory dir. This is useful for the
+// workflows that feed individual parts into other tools. Each file has the
+// same canvas as p, so that the parts keep their positions. nil opts is the
+// same as the zero value of ExportOptions. It returns ErrInvalidName without
+// writing any files if a name contains a path separator or "..", or if the
+// same name is used for more than one file.
+func (p *Path) ExportContours(dir string, opts *ExportOptions) error {
+	if opts == nil {
+		opts = &ExportOptions{}
+	}
+	var groups [][]int
+	if opts.Group {
+		groups = p.groups()
+	} else {
+		groups = make([][]int, len(p.Vertices))
+		for i := range p.Vertices {
+			groups[i] = []int{i}
+		}
+	}
+	names := make([]string, len(groups))
+	used := make(map[string]bool, len(groups))
+	for n, g := range groups {
+		name := p.PathLabel(g[0])
+		switch {
+		case opts.Name != nil:
+			name = opts.Name(n)
+		case name == "":
+			name = fmt.Sprintf("contour-%d", n)
+		}
+		if err := checkFileName(name); err != nil {
+			return err
+		}
+		if used[name] {
+			return fmt.Errorf("%w: %q used more than once", ErrInvalidName, name)
+		}
+		used[name] = true
+		names[n] = name
+	}
+	write, ext := opts.Format.writer(nil)
+	for n, g := range groups {
+		part := &Path{Width: p.Width, Height: p.Height, Vertices: make([][]Vertex, len(g))}
+		for i, idx := range g {
+			part.Vertices[i] = p.Vertices[idx]
+			if p.Labels != nil {
+				part.Labels = append(part.Labels, p.PathLabel(idx))
+			}
+		}
+		if err := writeFile(filepath.Join(dir, names[n]+ext), func(w io.Writer) error {
+			return write(part, w)
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkFileName returns ErrInvalidName if name contains a path separator or
+// "..", which could write the file outside the directory.
+func checkFileName(name string) error {
+	if strings.ContainsAny(name, `/\`) || strings.Contains(name, "..") {
+		return fmt.Errorf("%w: %q", ErrInvalidName, name)
+	}
+	return nil
+}
+
+// writeFile creates the file at the path name and writes its content with f.
+func writeFile(name string, f func(w io.Writer) error) error {
+	file, err := os.Create(name)
+	if err != nil {
+		return fmt.Errorf("create failure: %w", err)
+	}
+	if err := f(file); err != nil {
+		_ = file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("close failure: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath_test
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tunabay/go-bitarray"
+	"github.com/tunabay/go-bmppath"
+)
+
+func TestPath_ExportContours(t *testing.T) {
+	bmp := bitarray.NewBufferFromBitArray(
+		bitarray.MustParse(strings.Join([]string{
+			"11101",
+			"10100",
+			"11101",
+		}, "")),
+	)
+	path, err := bmppath.New(bmp, 5)
+	if err != nil {
+		t.Fatalf("New(): %v", err)
+	}
+
+	tcs := []struct {
+		opts  *bmppath.ExportOptions
+		files []string
+	}{
+		{nil, []string{"contour-0.svg", "contour-1.svg", "contour-2.svg", "contour-3.svg"}},
+		{
+			&bmppath.ExportOptions{Format: bmppath.ExportDXF, Group: true},
+			[]string{"contour-0.dxf", "contour-1.dxf", "contour-2.dxf"},
+		},
+		{
+			&bmppath.ExportOptions{
+				Group: true,
+				Name:  func(n int) string { return fmt.Sprintf("part%02d", n) },
+			},
+			[]string{"part00.svg", "part01.svg", "part02.svg"},
+		},
+	}
+	for i, tc := range tcs {
+		dir := t.TempDir()
+		if err := path.ExportContours(dir, tc.opts); err != nil {
+			t.Errorf("#%d: ExportContours(): %v", i, err)
+			continue
+		}
+		ents, err := os.ReadDir(dir)
+		if err != nil {
+			t.Fatalf("ReadDir(): %v", err)
+		}
+		var files []string
+		for _, ent := range ents {
+			files = append(files, ent.Name())
+		}
+		if got, want := strings.Join(files, " "), strings.Join(tc.files, " "); got != want {
+			t.Errorf("#%d: unexpected files: got %q, want %q", i, got, want)
+		}
+	}
+
+	if err := path.ExportContours(filepath.Join(t.TempDir(), "nonexistent"), nil); err == nil {
+		t.Error("no error for nonexistent directory")
+	}
+}
+
+func TestPath_ExportContours_invalidName(t *testing.T) {
+	square := func(x int) []bmppath.Vertex {
+		return []bmppath.Vertex{{x, 0}, {x + 1, 0}, {x + 1, 1}, {x, 1}}
+	}
+	path := &bmppath.Path{
+		Width:    6,
+		Height:   1,
+		Vertices: [][]bmppath.Vertex{square(0), square(2), square(4)},
+	}
+	for i, labels := range [][]string{
+		{"a", "../../x", "c"},
+		{"a", "b/c", "d"},
+		{"a", `b\c`, "d"},
+		{"a", "b", "a"},
+		{"contour-2", "", ""},
+	} {
+		path.Labels = labels
+		dir := t.TempDir()
+		if err := path.ExportContours(dir, nil); !errors.Is(err, bmppath.ErrInvalidName) {
+			t.Errorf("#%d: unexpected error: %v", i, err)
+		}
+		ents, err := os.ReadDir(dir)
+		if err != nil {
+			t.Fatalf("ReadDir(): %v", err)
+		}
+		if len(ents) != 0 {
+			t.Errorf("#%d: %d files written", i, len(ents))
+		}
+	}
+
+	path.Labels = nil
+	if err := path.ExportContours(t.TempDir(), &bmppath.ExportOptions{
+		Name: func(int) string { return "same" },
+	}); !errors.Is(err, bmppath.ErrInvalidName) {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
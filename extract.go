@@ -0,0 +1,92 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath
+
+import (
+	"image"
+)
+
+// PathBounds returns the bounding box of the closed path specified by the
+// index n. n must be less than p.NumPath() otherwise it panics.
+func (p *Path) PathBounds(n int) image.Rectangle {
+	r, _ := vertexBounds(p.Vertices[n])
+	return r
+}
+
+// vertexBounds returns the bounding box of all the vertices of the closed paths
+// vss. It returns false if there are no vertices.
+func vertexBounds(vss ...[]Vertex) (image.Rectangle, bool) {
+	var r image.Rectangle
+	found := false
+	for _, vs := range vss {
+		for _, v := range vs {
+			if !found {
+				r.Min, r.Max, found = image.Pt(v[0], v[1]), image.Pt(v[0], v[1]), true
+				continue
+			}
+			switch {
+			case v[0] < r.Min.X:
+				r.Min.X = v[0]
+			case r.Max.X < v[0]:
+				r.Max.X = v[0]
+			}
+			switch {
+			case v[1] < r.Min.Y:
+				r.Min.Y = v[1]
+			case r.Max.Y < v[1]:
+				r.Max.Y = v[1]
+			}
+		}
+	}
+	return r, found
+}
+
+// Extract returns a new Path consisting only of the closed path specified by
+// the index n, on the canvas fitted to its bounding box. The closed path is
+// translated so that the upper left corner of the bounding box, which can be
+// obtained with PathBounds, is at the origin. n must be less than p.NumPath()
+// otherwise it panics. p itself is not modified.
+func (p *Path) Extract(n int) *Path {
+	return p.extract([]int{n})
+}
+
+// ExtractRegion is identical to Extract except that the holes directly inside
+// the closed path specified by the index n are also included. This is useful
+// for pulling out the individual connected components, such as glyphs.
+func (p *Path) ExtractRegion(n int) *Path {
+	_ = p.Vertices[n]
+	g := []int{n}
+	for i, parent := range p.parents() {
+		if parent == n && signedArea(p.Vertices[i]) < 0 {
+			g = append(g, i)
+		}
+	}
+	return p.extract(g)
+}
+
+// extract returns a new Path consisting of the closed paths specified by the
+// indexes g, on the canvas fitted to the bounding box of all of them.
+func (p *Path) extract(g []int) *Path {
+	r := p.PathBounds(g[0])
+	for _, n := range g[1:] {
+		r = r.Union(p.PathBounds(n))
+	}
+	ret := &Path{
+		Width:    r.Dx(),
+		Height:   r.Dy(),
+		Vertices: make([][]Vertex, len(g)),
+	}
+	for i, n := range g {
+		vs := make([]Vertex, len(p.Vertices[n]))
+		for j, v := range p.Vertices[n] {
+			vs[j] = Vertex{v[0] - r.Min.X, v[1] - r.Min.Y}
+		}
+		ret.Vertices[i] = vs
+		if p.Labels != nil {
+			ret.Labels = append(ret.Labels, p.PathLabel(n))
+		}
+	}
+	return ret
+}
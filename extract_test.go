@@ -0,0 +1,43 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath_test
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/tunabay/go-bitarray"
+	"github.com/tunabay/go-bmppath"
+)
+
+func ExamplePath_ExtractRegion() {
+	bmp := bitarray.NewBufferFromBitArray(
+		bitarray.MustParse(strings.Join([]string{
+			"000000",
+			"011101",
+			"010101",
+			"011100",
+		}, "")),
+	)
+	path, err := bmppath.New(bmp, 6)
+	if err != nil {
+		panic(err)
+	}
+
+	for i := 0; i < path.NumPath(); i++ {
+		fmt.Println(i, path.PathBounds(i), path.PathString(i))
+	}
+	glyph := path.ExtractRegion(0)
+	fmt.Printf("%dx%d %s\n", glyph.Width, glyph.Height, glyph.SVGDString())
+	outline := path.Extract(0)
+	fmt.Printf("%dx%d %s\n", outline.Width, outline.Height, outline.SVGDString())
+
+	// Output:
+	// 0 (1,1)-(4,4) (1, 1), (4, 1), (4, 4), (1, 4)
+	// 1 (2,2)-(3,3) (2, 2), (2, 3), (3, 3), (3, 2)
+	// 2 (5,1)-(6,3) (5, 1), (6, 1), (6, 3), (5, 3)
+	// 3x3 m0,0h3v3h-3zm1,1v1h1v-1z
+	// 3x3 m0,0h3v3h-3z
+}
@@ -0,0 +1,41 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath
+
+// FillHoles returns a new Path in which the holes enclosing an area of maxArea
+// pixels or less are removed. Holes are the closed paths that run in the
+// opposite direction to the outlines, i.e. counterclockwise on the screen
+// coordinates. This can be used to clean up pinholes in a traced image without
+// re-rasterizing it. p itself is not modified.
+func (p *Path) FillHoles(maxArea int) *Path {
+	ret := &Path{
+		Width:    p.Width,
+		Height:   p.Height,
+		Vertices: make([][]Vertex, 0, len(p.Vertices)),
+	}
+	for i, vs := range p.Vertices {
+		if a := signedArea(vs); a < 0 && -a <= int64(maxArea) {
+			continue
+		}
+		ret.Vertices = append(ret.Vertices, append([]Vertex(nil), vs...))
+		if p.Labels != nil {
+			ret.Labels = append(ret.Labels, p.PathLabel(i))
+		}
+	}
+	return ret
+}
+
+// signedArea returns the area enclosed by the closed path vs. The result is
+// positive for outlines running clockwise on the screen coordinates, and
+// negative for holes. It is computed in int64 so that the areas of the large
+// images do not overflow on the 32-bit platforms.
+func signedArea(vs []Vertex) int64 {
+	var a int64
+	for i, v0 := range vs {
+		v1 := vs[(i+1)%len(vs)]
+		a += int64(v0[0])*int64(v1[1]) - int64(v1[0])*int64(v0[1])
+	}
+	return a / 2
+}
@@ -0,0 +1,53 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/tunabay/go-bitarray"
+	"github.com/tunabay/go-bmppath"
+)
+
+func TestPath_FillHoles(t *testing.T) {
+	bmp := bitarray.NewBufferFromBitArray(
+		bitarray.MustParse(strings.Join([]string{
+			"11111111",
+			"10111001",
+			"11111001",
+			"11111111",
+		}, "")),
+	)
+	path, err := bmppath.New(bmp, 8)
+	if err != nil {
+		t.Fatalf("New(): %v", err)
+	}
+	if n := path.NumPath(); n != 3 {
+		t.Fatalf("unexpected number of paths: got %d, want 3", n)
+	}
+
+	tcs := []struct {
+		maxArea, want int
+	}{
+		{0, 3},
+		{1, 2},
+		{3, 2},
+		{4, 1},
+		{100, 1},
+	}
+	for _, tc := range tcs {
+		filled := path.FillHoles(tc.maxArea)
+		if n := filled.NumPath(); n != tc.want {
+			t.Errorf("maxArea=%d: unexpected number of paths: got %d, want %d", tc.maxArea, n, tc.want)
+		}
+		if filled.Width != path.Width || filled.Height != path.Height {
+			t.Errorf("maxArea=%d: unexpected size: %dx%d", tc.maxArea, filled.Width, filled.Height)
+		}
+	}
+	if n := path.NumPath(); n != 3 {
+		t.Errorf("original path modified: got %d paths, want 3", n)
+	}
+}
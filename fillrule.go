@@ -0,0 +1,118 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath
+
+import (
+	"sort"
+
+	"github.com/tunabay/go-bitarray"
+)
+
+// FillRule specifies the rule to determine the inside of the closed paths.
+type FillRule int
+
+const (
+	// NonZero regards a point as inside if the closed paths wind around it
+	// a non-zero number of times, counting the clockwise ones as +1 and the
+	// counterclockwise ones as -1.
+	NonZero FillRule = iota
+
+	// EvenOdd regards a point as inside if it is enclosed by an odd number
+	// of closed paths, regardless of their directions.
+	EvenOdd
+)
+
+// String returns the name of the fill rule as used by the 'fill-rule'
+// property of SVG, "nonzero" or "evenodd".
+func (r FillRule) String() string {
+	if r == EvenOdd {
+		return "evenodd"
+	}
+	return "nonzero"
+}
+
+// ConvertFillRule returns a new Path which represents the same region as p
+// interpreted with the fill rule from, and yields that region with both of the
+// fill rules. This is needed before exporting to the formats or the rendering
+// engines that support only one of them. The Paths returned by New already
+// satisfy this, but the ones composed or edited afterward may not.
+//
+// For EvenOdd, the directions of the closed paths are corrected according to
+// their nesting depths: the outlines run clockwise and the holes run
+// counterclockwise, on the screen coordinates. The closed paths must not
+// intersect each other. For NonZero, the region is rasterized with the nonzero
+// rule and traced again, so the overlapping closed paths are merged, and the
+// labels are dropped. p itself is not modified.
+func (p *Path) ConvertFillRule(from FillRule) *Path {
+	if from == NonZero {
+		r := p.bounds()
+		w, h := r.Dx(), r.Dy()
+		return fromBitmap(p.rasterizeNonZero(r.Min.X, r.Min.Y, w, h), r.Min.X, r.Min.Y, w, h, p.Width, p.Height)
+	}
+	parents := p.parents()
+	depth := make([]int, len(p.Vertices))
+	for i := range p.Vertices {
+		for j := parents[i]; j != -1; j = parents[j] {
+			depth[i]++
+		}
+	}
+	ret := p.mapVertices(p.Width, p.Height, func(v Vertex) Vertex { return v })
+	for i, vs := range ret.Vertices {
+		if hole := signedArea(vs) < 0; hole != (depth[i]%2 == 1) {
+			reverseVertices(vs)
+		}
+	}
+	return ret
+}
+
+// rasterizeNonZero is identical to rasterize except that it uses the nonzero
+// rule.
+func (p *Path) rasterizeNonZero(x0, y0, w, h int) *bitarray.Buffer {
+	type crossing struct{ x, dir int }
+	buf := bitarray.NewBuffer(w * h)
+	xs := make([][]crossing, h)
+	for _, vs := range p.Vertices {
+		for i, v0 := range vs {
+			v1 := vs[(i+1)%len(vs)]
+			if v0[0] != v1[0] || v0[1] == v1[1] {
+				continue
+			}
+			ya, yb, dir := v0[1]-y0, v1[1]-y0, 1
+			if yb < ya {
+				ya, yb, dir = yb, ya, -1
+			}
+			if ya < 0 {
+				ya = 0
+			}
+			if h < yb {
+				yb = h
+			}
+			for y := ya; y < yb; y++ {
+				xs[y] = append(xs[y], crossing{v0[0] - x0, dir})
+			}
+		}
+	}
+	for y, row := range xs {
+		sort.Slice(row, func(i, j int) bool { return row[i].x < row[j].x })
+		wn := 0
+		for i, c := range row {
+			wn += c.dir
+			if wn == 0 || i+1 == len(row) {
+				continue
+			}
+			xa, xb := c.x, row[i+1].x
+			if xa < 0 {
+				xa = 0
+			}
+			if w < xb {
+				xb = w
+			}
+			if xa < xb {
+				buf.FillBitsAt(w*y+xa, xb-xa, 1)
+			}
+		}
+	}
+	return buf
+}
@@ -0,0 +1,68 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/tunabay/go-bitarray"
+	"github.com/tunabay/go-bmppath"
+)
+
+func ExamplePath_ConvertFillRule() {
+	bmp := bitarray.NewBufferFromBitArray(
+		bitarray.MustParse(strings.Join([]string{
+			"11111",
+			"10001",
+			"10101",
+			"10001",
+			"11111",
+		}, "")),
+	)
+	path, err := bmppath.New(bmp, 5)
+	if err != nil {
+		panic(err)
+	}
+
+	// all the closed paths running clockwise are valid only with EvenOdd
+	broken := path.ReversePath(1)
+	fmt.Println(bmppath.Diff(path, broken))
+	fixed := broken.ConvertFillRule(bmppath.EvenOdd)
+	fmt.Println(fixed.Equal(path))
+
+	// Output:
+	// + (1, 1), (4, 1), (4, 4), (1, 4)
+	// - (1, 1), (1, 4), (4, 4), (4, 1)
+	//
+	// true
+}
+
+func TestPath_ConvertFillRule(t *testing.T) {
+	// two overlapping squares running clockwise
+	p := &bmppath.Path{
+		Width:  4,
+		Height: 3,
+		Vertices: [][]bmppath.Vertex{
+			{{0, 0}, {3, 0}, {3, 2}, {0, 2}},
+			{{1, 1}, {4, 1}, {4, 3}, {1, 3}},
+		},
+	}
+	got := p.ConvertFillRule(bmppath.NonZero)
+	want := &bmppath.Path{
+		Width:  4,
+		Height: 3,
+		Vertices: [][]bmppath.Vertex{
+			{{0, 0}, {3, 0}, {3, 1}, {4, 1}, {4, 3}, {1, 3}, {1, 2}, {0, 2}},
+		},
+	}
+	if !got.Equal(want) {
+		t.Errorf("unexpected result:\n%s", bmppath.Diff(want, got))
+	}
+	if s := bmppath.EvenOdd.String(); s != "evenodd" {
+		t.Errorf("unexpected name: %q", s)
+	}
+}
@@ -0,0 +1,225 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/tunabay/go-bitarray"
+)
+
+// FloatPath is a set of closed paths with floating point coordinates. It holds
+// the results of the operations that move the vertices off the integer grid,
+// such as a rotation by an arbitrary angle.
+type FloatPath struct {
+	Width, Height float64
+	Vertices      [][]FloatVertex
+}
+
+// Float converts p to a FloatPath.
+func (p *Path) Float() *FloatPath {
+	ret := &FloatPath{
+		Width:    float64(p.Width),
+		Height:   float64(p.Height),
+		Vertices: make([][]FloatVertex, len(p.Vertices)),
+	}
+	for i, vs := range p.Vertices {
+		ret.Vertices[i] = floatVertices(vs)
+	}
+	return ret
+}
+
+// Path converts fp back to a Path by rasterizing it with Rasterize and tracing
+// the result. Unlike rounding the coordinates, this always results in valid
+// closed paths running along the pixel boundaries.
+func (fp *FloatPath) Path() *Path {
+	w, h := int(math.Ceil(fp.Width)), int(math.Ceil(fp.Height))
+	return fromBitmap(fp.Rasterize(), 0, 0, w, h, w, h)
+}
+
+// Rasterize fills the closed paths into a binary bitmap image of the size
+// ceil(fp.Width) x ceil(fp.Height), using the even-odd rule. Each pixel is set
+// to 1 if its center is inside the paths. The parts of the paths outside the
+// canvas are clipped.
+func (fp *FloatPath) Rasterize() *bitarray.Buffer {
+	w, h := int(math.Ceil(fp.Width)), int(math.Ceil(fp.Height))
+	buf := bitarray.NewBuffer(w * h)
+	for y := 0; y < h; y++ {
+		yc := float64(y) + 0.5
+		var xs []float64
+		for _, vs := range fp.Vertices {
+			for i, v0 := range vs {
+				v1 := vs[(i+1)%len(vs)]
+				if (v0[1] <= yc) == (v1[1] <= yc) {
+					continue
+				}
+				xs = append(xs, v0[0]+(yc-v0[1])*(v1[0]-v0[0])/(v1[1]-v0[1]))
+			}
+		}
+		sort.Float64s(xs)
+		for i := 0; i+1 < len(xs); i += 2 {
+			xa := int(math.Max(math.Ceil(xs[i]-0.5), 0))
+			xb := int(math.Min(math.Ceil(xs[i+1]-0.5), float64(w)))
+			if xa < xb {
+				buf.FillBitsAt(w*y+xa, xb-xa, 1)
+			}
+		}
+	}
+	return buf
+}
+
+// NumPath returns the number of closed paths in this set of paths.
+func (fp *FloatPath) NumPath() int { return len(fp.Vertices) }
+
+// PathLen returns the number of vertices of the closed path specified by the
+// index n. n == 0 points to the first path, and n must be less than
+// fp.NumPath() otherwise it panics.
+func (fp *FloatPath) PathLen(n int) int { return len(fp.Vertices[n]) }
+
+// PathString returns the string representation of the closed path specified by
+// the index n.
+func (fp *FloatPath) PathString(n int) string {
+	f := make([]string, len(fp.Vertices[n]))
+	for i, v := range fp.Vertices[n] {
+		f[i] = v.String()
+	}
+	return strings.Join(f, ", ")
+}
+
+// SVGDString is identical to WriteSVGD except that it returns a string instead
+// of writing to io.Writer.
+func (fp *FloatPath) SVGDString() string {
+	var sb strings.Builder
+	_ = fp.WriteSVGD(&sb)
+	return sb.String()
+}
+
+// WriteSVGD is identical to Path.WriteSVGD except that the coordinates may
+// have fractional parts, and the diagonal segments are written with the l
+// command. It is the same as WriteSVGDFormat with nil formatter.
+func (fp *FloatPath) WriteSVGD(w io.Writer) error {
+	return fp.WriteSVGDFormat(w, nil)
+}
+
+// WriteSVG is identical to Path.WriteSVG except that it writes fp. It is the
+// same as WriteSVGOptions with nil options.
+func (fp *FloatPath) WriteSVG(w io.Writer) error {
+	return fp.WriteSVGOptions(w, nil)
+}
+
+// WriteDXF is identical to Path.WriteDXF except that it writes fp.
+func (fp *FloatPath) WriteDXF(w io.Writer) error {
+	var sb strings.Builder
+	sb.WriteString("0\nSECTION\n2\nENTITIES\n")
+	for _, vs := range fp.Vertices {
+		sb.WriteString("0\nPOLYLINE\n8\n0\n66\n1\n70\n1\n")
+		for _, v := range vs {
+			fmt.Fprintf(&sb, "0\nVERTEX\n8\n0\n10\n%g\n20\n%g\n", v[0], fp.Height-v[1])
+		}
+		sb.WriteString("0\nSEQEND\n")
+		if _, err := io.WriteString(w, sb.String()); err != nil {
+			return fmt.Errorf("write failure: %w", err)
+		}
+		sb.Reset()
+	}
+	sb.WriteString("0\nENDSEC\n0\nEOF\n")
+	if _, err := io.WriteString(w, sb.String()); err != nil {
+		return fmt.Errorf("write failure: %w", err)
+	}
+	return nil
+}
+
+// Translate returns a new FloatPath with all the vertices moved by (dx, dy).
+// The Width and Height are increased by dx and dy, the same as Path.Translate.
+// fp itself is not modified.
+func (fp *FloatPath) Translate(dx, dy float64) *FloatPath {
+	return fp.mapVertices(fp.Width+dx, fp.Height+dy, func(v FloatVertex) FloatVertex {
+		return FloatVertex{v[0] + dx, v[1] + dy}
+	})
+}
+
+// Scale returns a new FloatPath magnified by the factor s. All the
+// coordinates, the Width and the Height are multiplied by s. s must be a
+// positive number otherwise it panics. fp itself is not modified.
+func (fp *FloatPath) Scale(s float64) *FloatPath {
+	if !(0 < s) {
+		panic("bmppath: non-positive scale factor")
+	}
+	return fp.mapVertices(fp.Width*s, fp.Height*s, func(v FloatVertex) FloatVertex {
+		return FloatVertex{v[0] * s, v[1] * s}
+	})
+}
+
+// Rotate returns a new FloatPath rotated clockwise by theta radians on the
+// screen coordinates around the point c. The Width and Height are not changed.
+// fp itself is not modified.
+func (fp *FloatPath) Rotate(theta float64, c FloatVertex) *FloatPath {
+	sin, cos := math.Sincos(theta)
+	return fp.mapVertices(fp.Width, fp.Height, func(v FloatVertex) FloatVertex {
+		dx, dy := v[0]-c[0], v[1]-c[1]
+		return FloatVertex{c[0] + dx*cos - dy*sin, c[1] + dx*sin + dy*cos}
+	})
+}
+
+// Straighten returns a new FloatPath rotated so that the minimum-area
+// rectangle enclosing all the closed paths is aligned to the axes, along with
+// the applied rotation angle. The angle is in radians, clockwise on the screen
+// coordinates, and in the range (-π/4, π/4], so that the shape is rotated as
+// little as possible. The result is translated so that the rectangle is placed
+// at the origin, and its Width and Height are set to the size of the
+// rectangle. This can be used to square up the traced image of a stamp or logo
+// scanned at a slight angle. fp itself is not modified.
+func (fp *FloatPath) Straighten() (*FloatPath, float64) {
+	var pts []FloatVertex
+	for _, vs := range fp.Vertices {
+		pts = append(pts, vs...)
+	}
+	hull := convexHull(pts)
+	if len(hull) == 0 {
+		return fp.mapVertices(fp.Width, fp.Height, func(v FloatVertex) FloatVertex { return v }), 0
+	}
+	box := minAreaBox(hull)
+	theta := -box.Angle
+	if theta <= -math.Pi/4 {
+		theta += math.Pi / 2
+	}
+	rotated := fp.Rotate(theta, box.Center)
+
+	lo := FloatVertex{math.Inf(1), math.Inf(1)}
+	hi := FloatVertex{math.Inf(-1), math.Inf(-1)}
+	for _, vs := range rotated.Vertices {
+		for _, v := range vs {
+			for i := 0; i < 2; i++ {
+				lo[i], hi[i] = math.Min(lo[i], v[i]), math.Max(hi[i], v[i])
+			}
+		}
+	}
+	ret := rotated.mapVertices(hi[0]-lo[0], hi[1]-lo[1], func(v FloatVertex) FloatVertex {
+		return FloatVertex{v[0] - lo[0], v[1] - lo[1]}
+	})
+	return ret, theta
+}
+
+// mapVertices returns a new FloatPath of the size width x height, with each
+// vertex of fp converted by f.
+func (fp *FloatPath) mapVertices(width, height float64, f func(FloatVertex) FloatVertex) *FloatPath {
+	ret := &FloatPath{
+		Width:    width,
+		Height:   height,
+		Vertices: make([][]FloatVertex, len(fp.Vertices)),
+	}
+	for i, vs := range fp.Vertices {
+		nvs := make([]FloatVertex, len(vs))
+		for j, v := range vs {
+			nvs[j] = f(v)
+		}
+		ret.Vertices[i] = nvs
+	}
+	return ret
+}
@@ -0,0 +1,79 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath_test
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/tunabay/go-bitarray"
+	"github.com/tunabay/go-bmppath"
+)
+
+func TestFloatPath_Straighten(t *testing.T) {
+	path, err := bmppath.New(bitarray.NewBufferFromBitArray(bitarray.MustParse("111111")), 3)
+	if err != nil {
+		t.Fatalf("New(): %v", err)
+	}
+	const tilt = 0.1
+	fp := path.Float().Rotate(tilt, bmppath.FloatVertex{1.5, 1})
+
+	straight, theta := fp.Straighten()
+	if math.Abs(theta+tilt) > 1e-9 {
+		t.Errorf("unexpected angle: got %g, want %g", theta, -tilt)
+	}
+	if math.Abs(straight.Width-3) > 1e-9 || math.Abs(straight.Height-2) > 1e-9 {
+		t.Errorf("unexpected size: %gx%g", straight.Width, straight.Height)
+	}
+	want := []bmppath.FloatVertex{{0, 0}, {3, 0}, {3, 2}, {0, 2}}
+	for i, v := range straight.Vertices[0] {
+		if math.Abs(v.X()-want[i].X()) > 1e-9 || math.Abs(v.Y()-want[i].Y()) > 1e-9 {
+			t.Errorf("vertex #%d: got %s, want %s", i, v, want[i])
+		}
+	}
+}
+
+func TestFloatPath_Path(t *testing.T) {
+	bmp := bitarray.NewBufferFromByteSlice([]byte{
+		0b_10000001,
+		0b_01000010,
+		0b_00111100,
+		0b_01111110,
+		0b_11011011,
+		0b_01111110,
+		0b_00100100,
+		0b_11000011,
+	})
+	path, err := bmppath.New(bmp, 8)
+	if err != nil {
+		t.Fatalf("New(): %v", err)
+	}
+	if got := path.Float().Path(); !got.Equal(path) {
+		t.Errorf("round trip failed:\n%s", bmppath.Diff(path, got))
+	}
+	if got := path.Float().Scale(0.5).Scale(2).Path(); !got.Equal(path) {
+		t.Errorf("scaled round trip failed:\n%s", bmppath.Diff(path, got))
+	}
+}
+
+func ExampleFloatPath_SVGDString() {
+	fp := &bmppath.FloatPath{
+		Width:  4,
+		Height: 4,
+		Vertices: [][]bmppath.FloatVertex{
+			{{2, 0.5}, {3.5, 2}, {2, 3.5}, {0.5, 2}},
+			{{1.5, 1.5}, {1.5, 2.5}, {2.5, 2.5}, {2.5, 1.5}},
+		},
+	}
+	fmt.Println(fp.SVGDString())
+	fmt.Println(fp.Translate(-0.5, 0).SVGDString())
+	fmt.Println(fp.Path().SVGDString())
+
+	// Output:
+	// m2,0.5l1.5,1.5l-1.5,1.5l-1.5-1.5zm-0.5,1v1h1v-1z
+	// m1.5,0.5l1.5,1.5l-1.5,1.5l-1.5-1.5zm-0.5,1v1h1v-1z
+	// m2,1h1v2h-2v-1h1z
+}
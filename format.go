@@ -0,0 +1,481 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// CoordFormatter converts a coordinate value into its text representation for
+// the text encoders. The result must not depend on the locale.
+type CoordFormatter func(v float64) string
+
+// FormatCoord returns a CoordFormatter that writes the value in the decimal
+// notation with at most prec digits after the decimal point, omitting the
+// trailing zeros, followed by the unit suffix unit such as "cm". A negative prec
+// means the smallest number of digits necessary to represent the value
+// exactly. Note that the 'd' strings of SVG do not accept the unit suffixes.
+func FormatCoord(prec int, unit string) CoordFormatter {
+	return func(v float64) string {
+		s := strconv.FormatFloat(v, 'f', prec, 64)
+		if strings.IndexByte(s, '.') != -1 {
+			s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+		}
+		if s == "-0" {
+			s = "0"
+		}
+		return s + unit
+	}
+}
+
+// defaultCoordFormatter is the CoordFormatter used when nil is specified.
+func defaultCoordFormatter(v float64) string { return strconv.FormatFloat(v, 'g', -1, 64) }
+
+// SVGOptions represents the options for WriteSVGOptions.
+type SVGOptions struct {
+	// Format formats the coordinates in the 'd' strings. If nil, the
+	// shortest representation of each value is used.
+	Format CoordFormatter
+
+	// Margin is the space added around the canvas, which extends the
+	// viewBox in all directions so that the artwork is not flush against
+	// the edges of the document.
+	Margin float64
+
+	// Origin is the point of the canvas placed at the upper left corner of
+	// the viewBox, before the Margin is added. Setting it offsets the
+	// viewBox, which crops or pads the canvas without modifying the paths.
+	Origin FloatVertex
+
+	// FillRule is the fill rule of the <path> elements. For EvenOdd, the
+	// fill-rule="evenodd" attribute is added, and the directions of the
+	// closed paths are corrected according to their nesting depths before
+	// writing, so that the holes are handled correctly even if the
+	// directions are broken, such as in the Paths composed manually. For
+	// NonZero, which is the default of SVG, the attribute is omitted and
+	// the closed paths are written as they are.
+	FillRule FillRule
+
+	// Separate writes each closed path as its own <path> element, instead
+	// of writing all of them in a single <path> element, so that they can
+	// be styled or animated individually. Since the holes written alone
+	// would be filled, they are filled with white, and the elements are
+	// written in descending order of the enclosed areas, so that each hole
+	// is painted over the outline enclosing it.
+	Separate bool
+
+	// MaxDLength, if positive, limits the length of the 'd' strings in
+	// bytes, since some parsers and editors cannot handle huge attributes.
+	// The closed paths are split into multiple <path> elements at the
+	// boundaries of the regions, each of which consists of an outline and
+	// the holes directly inside it, so that the holes are not filled. A
+	// region longer than MaxDLength by itself is written in its own <path>
+	// element. It is ignored when Separate is set.
+	MaxDLength int
+
+	// IDPrefix, if not empty, adds the id attribute to each <path> element
+	// written with Separate. The id is IDPrefix followed by the index of
+	// the closed path, or the label of the closed path if it is labeled.
+	IDPrefix string
+
+	// Marks, if not nil, adds the bleed and the printer's marks around the
+	// canvas, extending the viewBox to contain them.
+	Marks *PrintMarks
+
+	// Absolute writes the 'd' strings with the absolute commands, as
+	// WriteSVGDAbsolute does, instead of the relative ones.
+	Absolute bool
+
+	// Grid, if not nil, records the pixel grid of the source raster as the
+	// data attributes of the <svg> element, such as data-pixel-width.
+	Grid *PixelGrid
+}
+
+// WriteSVGOptions is identical to WriteSVG except that the document is
+// customized with opts. nil opts is the same as the zero value of SVGOptions.
+func (p *Path) WriteSVGOptions(w io.Writer, opts *SVGOptions) error {
+	return p.Float().writeSVGOptions(w, p.Labels, opts)
+}
+
+// WriteSVGOptions is identical to WriteSVG except that the document is
+// customized with opts. nil opts is the same as the zero value of SVGOptions.
+func (fp *FloatPath) WriteSVGOptions(w io.Writer, opts *SVGOptions) error {
+	return fp.writeSVGOptions(w, nil, opts)
+}
+
+// writeSVGOptions writes fp as an SVG document customized with opts, with the
+// labels of the closed paths labels.
+func (fp *FloatPath) writeSVGOptions(w io.Writer, labels []string, opts *SVGOptions) error {
+	if opts == nil {
+		opts = &SVGOptions{}
+	}
+	f := opts.Format
+	if f == nil {
+		f = defaultCoordFormatter
+	}
+	vertices, rule := fp.Vertices, ""
+	if opts.FillRule == EvenOdd {
+		vertices, rule = orientEvenOdd(vertices), ` fill-rule="evenodd"`
+	}
+	var sb strings.Builder
+	fmt.Fprintln(&sb, `<?xml version="1.0" encoding="utf-8"?>`)
+	fmt.Fprint(&sb, `<svg version="1.1" xmlns="http://www.w3.org/2000/svg"`)
+	// the marks are in points, and one user unit is 1/96 inch
+	const k = 96.0 / 72
+	ext := opts.Margin + opts.Marks.extent()*k
+	x, y := opts.Origin[0]-ext, opts.Origin[1]-ext
+	width, height := fp.Width+2*ext, fp.Height+2*ext
+	fmt.Fprintf(&sb, ` viewBox="%s %s %s %s"`, f(x), f(y), f(width), f(height))
+	for _, gf := range opts.Grid.fields(fp.Width, fp.Height) {
+		fmt.Fprintf(&sb, ` data-%s="`, kebabCase(gf.name))
+		switch v := gf.value.(type) {
+		case float64:
+			sb.WriteString(defaultCoordFormatter(v))
+		case string:
+			_ = xml.EscapeText(&sb, []byte(v))
+		}
+		sb.WriteString(`"`)
+	}
+	fmt.Fprintln(&sb, `>`)
+	if opts.Absolute {
+		sb.WriteString(`<path fill="#fff" d="M`)
+		writeSVGNumbers(&sb, f, x, y)
+		fmt.Fprintf(&sb, `H%sV%sH%sZ"/>`, f(x+width), f(y+height), f(x))
+	} else {
+		sb.WriteString(`<path fill="#fff" d="m`)
+		writeSVGNumbers(&sb, f, x, y)
+		fmt.Fprintf(&sb, `h%sv%sh%sz"/>`, f(width), f(height), f(-width))
+	}
+	switch {
+	case opts.Separate:
+		areas := make([]float64, len(vertices))
+		order := make([]int, len(vertices))
+		for i, vs := range vertices {
+			areas[i], order[i] = floatSignedArea(vs), i
+		}
+		sort.SliceStable(order, func(i, j int) bool {
+			return math.Abs(areas[order[j]]) < math.Abs(areas[order[i]])
+		})
+		for _, n := range order {
+			sb.WriteString(`<path`)
+			if opts.IDPrefix != "" {
+				id := opts.IDPrefix + strconv.Itoa(n)
+				if n < len(labels) && labels[n] != "" {
+					id = opts.IDPrefix + labels[n]
+				}
+				sb.WriteString(` id="`)
+				_ = xml.EscapeText(&sb, []byte(id))
+				sb.WriteString(`"`)
+			}
+			if areas[n] < 0 {
+				sb.WriteString(` fill="#fff"`)
+			}
+			sb.WriteString(rule)
+			sb.WriteString(` d="`)
+			_ = writeFloatSVGD(&sb, vertices[n:n+1], f, opts.Absolute)
+			sb.WriteString(`"/>`)
+			fmt.Fprintln(&sb)
+		}
+	case 0 < opts.MaxDLength:
+		var d, gd strings.Builder
+		var pen FloatVertex
+		for _, g := range floatGroups(vertices) {
+			vss := make([][]FloatVertex, len(g))
+			for i, n := range g {
+				vss[i] = vertices[n]
+			}
+			gd.Reset()
+			next, _ := writeFloatSVGDFrom(&gd, vss, f, pen, opts.Absolute)
+			if d.Len() != 0 && opts.MaxDLength < d.Len()+gd.Len() {
+				fmt.Fprintf(&sb, `<path%s d="%s"/>`, rule, d.String())
+				fmt.Fprintln(&sb)
+				d.Reset()
+				gd.Reset()
+				next, _ = writeFloatSVGDFrom(&gd, vss, f, FloatVertex{}, opts.Absolute)
+			}
+			d.WriteString(gd.String())
+			pen = next
+		}
+		fmt.Fprintf(&sb, `<path%s d="%s"/>`, rule, d.String())
+		fmt.Fprintln(&sb)
+	default:
+		fmt.Fprintf(&sb, `<path%s d="`, rule)
+		_ = writeFloatSVGD(&sb, vertices, f, opts.Absolute)
+		sb.WriteString(`"/>`)
+		fmt.Fprintln(&sb)
+	}
+	if lines, circles := opts.Marks.shapes(fp.Width/k, fp.Height/k); len(lines) != 0 || len(circles) != 0 {
+		_, _, lw := opts.Marks.params()
+		fmt.Fprintf(&sb, `<g fill="none" stroke="#000" stroke-width="%s">`, f(lw*k))
+		sb.WriteString(`<path d="`)
+		for _, l := range lines {
+			sb.WriteString("M")
+			writeSVGNumbers(&sb, f, l[0][0]*k, l[0][1]*k)
+			sb.WriteString("L")
+			writeSVGNumbers(&sb, f, l[1][0]*k, l[1][1]*k)
+		}
+		sb.WriteString(`"/>`)
+		for _, c := range circles {
+			fmt.Fprintf(&sb, `<circle cx="%s" cy="%s" r="%s"/>`, f(c.Center[0]*k), f(c.Center[1]*k), f(c.Radius*k))
+		}
+		fmt.Fprintln(&sb, `</g>`)
+	}
+	fmt.Fprintln(&sb, `</svg>`)
+	if _, err := fmt.Fprint(w, sb.String()); err != nil {
+		return fmt.Errorf("write failure: %w", err)
+	}
+	return nil
+}
+
+// floatGroups is identical to Path.groups except that it takes the closed
+// paths with floating point coordinates. Each hole is assigned to the smallest
+// outline containing the midpoint of its first segment.
+func floatGroups(vss [][]FloatVertex) [][]int {
+	areas := make([]float64, len(vss))
+	for i, vs := range vss {
+		areas[i] = floatSignedArea(vs)
+	}
+	var ret [][]int
+	gidx := make(map[int]int)
+	for i, a := range areas {
+		if 0 <= a {
+			gidx[i] = len(ret)
+			ret = append(ret, []int{i})
+		}
+	}
+	for i, vs := range vss {
+		if 0 <= areas[i] {
+			continue
+		}
+		pt := FloatVertex{(vs[0][0] + vs[1%len(vs)][0]) / 2, (vs[0][1] + vs[1%len(vs)][1]) / 2}
+		parent := -1
+		for j, cvs := range vss {
+			if areas[j] <= -areas[i] || !containsFloatPoint(cvs, pt) {
+				continue
+			}
+			if parent == -1 || areas[j] < areas[parent] {
+				parent = j
+			}
+		}
+		if g, ok := gidx[parent]; ok {
+			ret[g] = append(ret[g], i)
+			continue
+		}
+		ret = append(ret, []int{i})
+	}
+	return ret
+}
+
+// containsFloatPoint reports whether the point pt is inside the closed path vs,
+// using the even-odd rule.
+func containsFloatPoint(vs []FloatVertex, pt FloatVertex) bool {
+	in := false
+	for i, v0 := range vs {
+		v1 := vs[(i+1)%len(vs)]
+		if (v0[1] <= pt[1]) == (v1[1] <= pt[1]) {
+			continue
+		}
+		if x := v0[0] + (pt[1]-v0[1])*(v1[0]-v0[0])/(v1[1]-v0[1]); pt[0] < x {
+			in = !in
+		}
+	}
+	return in
+}
+
+// orientEvenOdd returns a copy of the closed paths vss whose directions are
+// corrected according to their nesting depths, as ConvertFillRule does for
+// EvenOdd.
+func orientEvenOdd(vss [][]FloatVertex) [][]FloatVertex {
+	areas := make([]float64, len(vss))
+	for i, vs := range vss {
+		areas[i] = math.Abs(floatSignedArea(vs))
+	}
+	ret := make([][]FloatVertex, len(vss))
+	for i, vs := range vss {
+		if len(vs) == 0 {
+			ret[i] = vs
+			continue
+		}
+		pt := FloatVertex{(vs[0][0] + vs[1%len(vs)][0]) / 2, (vs[0][1] + vs[1%len(vs)][1]) / 2}
+		depth := 0
+		for j, cvs := range vss {
+			if areas[i] < areas[j] && containsFloatPoint(cvs, pt) {
+				depth++
+			}
+		}
+		ret[i] = append([]FloatVertex(nil), vs...)
+		if hole := floatSignedArea(vs) < 0; hole != (depth%2 == 1) {
+			for a, b := 1, len(vs)-1; a < b; a, b = a+1, b-1 {
+				ret[i][a], ret[i][b] = ret[i][b], ret[i][a]
+			}
+		}
+	}
+	return ret
+}
+
+// floatSignedArea is identical to signedArea except that it takes the vertices
+// with floating point coordinates.
+func floatSignedArea(vs []FloatVertex) float64 {
+	a := 0.0
+	for i, v0 := range vs {
+		v1 := vs[(i+1)%len(vs)]
+		a += v0[0]*v1[1] - v1[0]*v0[1]
+	}
+	return a / 2
+}
+
+// WriteSVGDFormat is identical to WriteSVGD except that the coordinates are
+// formatted with f. nil f means the shortest representation of each value.
+func (fp *FloatPath) WriteSVGDFormat(w io.Writer, f CoordFormatter) error {
+	if f == nil {
+		f = defaultCoordFormatter
+	}
+	return writeFloatSVGD(w, fp.Vertices, f, false)
+}
+
+// WriteSVGDAbsolute is identical to WriteSVGDFormat except that the 'd' string
+// is written with the absolute commands M, H, V, L, and Z, which some parsers
+// and the diff-based workflows handle better, since each coordinate does not
+// depend on the preceding ones. nil f means the shortest representation of
+// each value.
+func (fp *FloatPath) WriteSVGDAbsolute(w io.Writer, f CoordFormatter) error {
+	if f == nil {
+		f = defaultCoordFormatter
+	}
+	return writeFloatSVGD(w, fp.Vertices, f, true)
+}
+
+// SVGDOptions represents the options for WriteSVGDOptions.
+type SVGDOptions struct {
+	// Scale is the factor multiplied to all the coordinates before they are
+	// written, such as the conversion from pixels to millimeters. It must
+	// not be negative. The zero value means 1.
+	Scale float64
+
+	// Precision is the maximum number of digits after the decimal point.
+	// The coordinates are rounded, and the trailing zeros are omitted, so
+	// that a value such as 1.5000000001 is written as "1.5". Zero means the
+	// shortest representation of each value, and a negative value rounds
+	// the coordinates to integers.
+	Precision int
+
+	// Absolute writes the 'd' string with the absolute commands, as
+	// WriteSVGDAbsolute does, instead of the relative ones.
+	Absolute bool
+}
+
+// formatter returns the CoordFormatter for the Precision of opts.
+func (opts *SVGDOptions) formatter() CoordFormatter {
+	switch {
+	case opts.Precision < 0:
+		return FormatCoord(0, "")
+	case 0 < opts.Precision:
+		return FormatCoord(opts.Precision, "")
+	}
+	return defaultCoordFormatter
+}
+
+// WriteSVGDOptions is identical to WriteSVGD except that the coordinates are
+// scaled and rounded as specified by opts. The relative coordinates are
+// computed from the rounded ones written before, so that the rounding errors
+// do not accumulate, and the output is deterministic for the same input. It
+// panics if opts.Scale is negative. nil opts is the same as the zero value of
+// SVGDOptions.
+func (fp *FloatPath) WriteSVGDOptions(w io.Writer, opts *SVGDOptions) error {
+	if opts == nil {
+		opts = &SVGDOptions{}
+	}
+	if opts.Scale != 0 && opts.Scale != 1 {
+		fp = fp.Scale(opts.Scale)
+	}
+	return writeFloatSVGD(w, fp.Vertices, opts.formatter(), opts.Absolute)
+}
+
+// writeFloatSVGD writes the 'd' string of the closed paths vss to w, with the
+// coordinates formatted with f. If abs is set, the absolute commands are used.
+func writeFloatSVGD(w io.Writer, vss [][]FloatVertex, f CoordFormatter, abs bool) error {
+	_, err := writeFloatSVGDFrom(w, vss, f, FloatVertex{}, abs)
+	return err
+}
+
+// writeFloatSVGDFrom is identical to writeFloatSVGD except that the 'd' string
+// continues from the current point pen. It returns the current point at the
+// end. The current point is the one as the readers see it, which may differ
+// from the exact one due to the rounding by f. The relative coordinates are
+// computed from it, so that the errors do not accumulate.
+func writeFloatSVGDFrom(w io.Writer, vss [][]FloatVertex, f CoordFormatter, pen FloatVertex, abs bool) (FloatVertex, error) {
+	m, v, h, l, z := "m", "v", "h", "l", "z"
+	if abs {
+		m, v, h, l, z = "M", "V", "H", "L", "Z"
+	}
+	// ref returns the point the coordinates are written relative to
+	ref := func() FloatVertex {
+		if abs {
+			return FloatVertex{}
+		}
+		return pen
+	}
+	var sb strings.Builder
+	for _, vs := range vss {
+		if len(vs) == 0 {
+			continue
+		}
+		sb.WriteString(m)
+		o := ref()
+		d := writeSVGNumbers(&sb, f, vs[0][0]-o[0], vs[0][1]-o[1])
+		pen = FloatVertex{o[0] + d[0], o[1] + d[1]}
+		start, pv := pen, vs[0]
+		for _, vt := range vs[1:] {
+			o := ref()
+			switch {
+			case vt[0] == pv[0] && vt[1] == pv[1]:
+				continue
+			case vt[0] == pv[0]:
+				sb.WriteString(v)
+				pen[1] = o[1] + writeSVGNumbers(&sb, f, vt[1]-o[1])[0]
+			case vt[1] == pv[1]:
+				sb.WriteString(h)
+				pen[0] = o[0] + writeSVGNumbers(&sb, f, vt[0]-o[0])[0]
+			default:
+				sb.WriteString(l)
+				d := writeSVGNumbers(&sb, f, vt[0]-o[0], vt[1]-o[1])
+				pen = FloatVertex{o[0] + d[0], o[1] + d[1]}
+			}
+			pv = vt
+		}
+		sb.WriteString(z)
+		pen = start
+		if _, err := io.WriteString(w, sb.String()); err != nil {
+			return pen, fmt.Errorf("write failure: %w", err)
+		}
+		sb.Reset()
+	}
+	return pen, nil
+}
+
+// writeSVGNumbers writes the numbers vs formatted with f, separated by commas,
+// and returns the values as written. The commas before the negative numbers
+// are omitted. The values that cannot be parsed back are returned as they are.
+func writeSVGNumbers(sb *strings.Builder, f CoordFormatter, vs ...float64) []float64 {
+	ret := make([]float64, len(vs))
+	for i, v := range vs {
+		s := f(v)
+		if 0 < i && !strings.HasPrefix(s, "-") {
+			sb.WriteString(",")
+		}
+		sb.WriteString(s)
+		ret[i] = v
+		if pv, err := strconv.ParseFloat(s, 64); err == nil {
+			ret[i] = pv
+		}
+	}
+	return ret
+}
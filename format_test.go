@@ -0,0 +1,184 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath_test
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"strings"
+
+	"github.com/tunabay/go-bitarray"
+	"github.com/tunabay/go-bmppath"
+)
+
+func ExampleFormatCoord() {
+	f := bmppath.FormatCoord(2, "cm")
+	fmt.Println(f(1.5), f(2), f(-0.001), f(1.0/3))
+
+	// Output:
+	// 1.5cm 2cm 0cm 0.33cm
+}
+
+func ExampleFloatPath_WriteSVGOptions() {
+	fp := &bmppath.FloatPath{
+		Width:  3,
+		Height: 3,
+		Vertices: [][]bmppath.FloatVertex{
+			{{1, 0}, {1 + math.Sqrt2, 1}, {1, 2}},
+			{{0.333, 0.333}, {0.666, 0.333}, {0.666, 0.666}, {0.333, 0.666}},
+		},
+	}
+	_ = fp.WriteSVGOptions(os.Stdout, &bmppath.SVGOptions{
+		Format: bmppath.FormatCoord(1, ""),
+	})
+
+	// Output:
+	// <?xml version="1.0" encoding="utf-8"?>
+	// <svg version="1.1" xmlns="http://www.w3.org/2000/svg" viewBox="0 0 3 3">
+	// <path fill="#fff" d="m0,0h3v3h-3z"/><path d="m1,0l1.4,1l-1.4,1zm-0.7,0.3h0.4v0.4h-0.4z"/>
+	// </svg>
+}
+
+func ExampleFloatPath_WriteSVGDOptions() {
+	fp := &bmppath.FloatPath{
+		Width:  4,
+		Height: 4,
+		Vertices: [][]bmppath.FloatVertex{
+			{{0.3, 0}, {1.5000000001, 0}, {1.5000000001, 1.0 / 3}, {0.3, 1.0 / 3}},
+		},
+	}
+	_ = fp.WriteSVGD(os.Stdout)
+	fmt.Println()
+	_ = fp.WriteSVGDOptions(os.Stdout, &bmppath.SVGDOptions{Precision: 2})
+	fmt.Println()
+	_ = fp.WriteSVGDOptions(os.Stdout, &bmppath.SVGDOptions{Scale: 0.5, Precision: 3, Absolute: true})
+	fmt.Println()
+
+	// Output:
+	// m0.3,0h1.2000000001v0.3333333333333333h-1.2000000001z
+	// m0.3,0h1.2v0.33h-1.2z
+	// M0.15,0H0.75V0.167H0.15Z
+}
+
+func ExamplePath_WriteSVGOptions() {
+	bmp := bitarray.NewBufferFromBitArray(
+		bitarray.MustParse(strings.Join([]string{
+			"11100",
+			"10101",
+			"11100",
+		}, "")),
+	)
+	path, err := bmppath.New(bmp, 5)
+	if err != nil {
+		panic(err)
+	}
+	path.Labels = []string{"ring"}
+
+	_ = path.WriteSVGOptions(os.Stdout, &bmppath.SVGOptions{
+		Separate: true,
+		IDPrefix: "c-",
+	})
+
+	// Output:
+	// <?xml version="1.0" encoding="utf-8"?>
+	// <svg version="1.1" xmlns="http://www.w3.org/2000/svg" viewBox="0 0 5 3">
+	// <path fill="#fff" d="m0,0h5v3h-5z"/><path id="c-ring" d="m0,0h3v3h-3z"/>
+	// <path id="c-1" fill="#fff" d="m1,1v1h1v-1z"/>
+	// <path id="c-2" d="m4,1h1v1h-1z"/>
+	// </svg>
+}
+
+func ExampleSVGOptions_maxDLength() {
+	bmp := bitarray.NewBufferFromBitArray(
+		bitarray.MustParse(strings.Join([]string{
+			"11101",
+			"10100",
+			"11101",
+		}, "")),
+	)
+	path, err := bmppath.New(bmp, 5)
+	if err != nil {
+		panic(err)
+	}
+
+	_ = path.WriteSVGOptions(os.Stdout, &bmppath.SVGOptions{MaxDLength: 30})
+
+	// Output:
+	// <?xml version="1.0" encoding="utf-8"?>
+	// <svg version="1.1" xmlns="http://www.w3.org/2000/svg" viewBox="0 0 5 3">
+	// <path fill="#fff" d="m0,0h5v3h-5z"/><path d="m0,0h3v3h-3zm1,1v1h1v-1z"/>
+	// <path d="m4,0h1v1h-1zm0,2h1v1h-1z"/>
+	// </svg>
+}
+
+func ExampleSVGOptions_absolute() {
+	bmp := bitarray.NewBufferFromBitArray(
+		bitarray.MustParse(strings.Join([]string{
+			"11101",
+			"10100",
+			"11101",
+		}, "")),
+	)
+	path, err := bmppath.New(bmp, 5)
+	if err != nil {
+		panic(err)
+	}
+
+	_ = path.WriteSVGOptions(os.Stdout, &bmppath.SVGOptions{Absolute: true, Separate: true})
+
+	// Output:
+	// <?xml version="1.0" encoding="utf-8"?>
+	// <svg version="1.1" xmlns="http://www.w3.org/2000/svg" viewBox="0 0 5 3">
+	// <path fill="#fff" d="M0,0H5V3H0Z"/><path d="M0,0H3V3H0Z"/>
+	// <path fill="#fff" d="M1,1V2H2V1Z"/>
+	// <path d="M4,0H5V1H4Z"/>
+	// <path d="M4,2H5V3H4Z"/>
+	// </svg>
+}
+
+func ExampleSVGOptions_margin() {
+	path, err := bmppath.New(bitarray.NewBufferFromBitArray(bitarray.MustParse("0110")), 2)
+	if err != nil {
+		panic(err)
+	}
+
+	_ = path.WriteSVGOptions(os.Stdout, &bmppath.SVGOptions{Margin: 1.5})
+	_ = path.WriteSVGOptions(os.Stdout, &bmppath.SVGOptions{Origin: bmppath.FloatVertex{1, 0}})
+
+	// Output:
+	// <?xml version="1.0" encoding="utf-8"?>
+	// <svg version="1.1" xmlns="http://www.w3.org/2000/svg" viewBox="-1.5 -1.5 5 5">
+	// <path fill="#fff" d="m-1.5-1.5h5v5h-5z"/><path d="m1,0h1v1h-1v1h-1v-1h1z"/>
+	// </svg>
+	// <?xml version="1.0" encoding="utf-8"?>
+	// <svg version="1.1" xmlns="http://www.w3.org/2000/svg" viewBox="1 0 2 2">
+	// <path fill="#fff" d="m1,0h2v2h-2z"/><path d="m1,0h1v1h-1v1h-1v-1h1z"/>
+	// </svg>
+}
+
+func ExampleSVGOptions_fillRule() {
+	bmp := bitarray.NewBufferFromBitArray(
+		bitarray.MustParse(strings.Join([]string{
+			"111",
+			"101",
+			"111",
+		}, "")),
+	)
+	path, err := bmppath.New(bmp, 3)
+	if err != nil {
+		panic(err)
+	}
+
+	// the hole running clockwise is filled with the nonzero rule
+	broken := path.ReversePath(1)
+	_ = broken.WriteSVGOptions(os.Stdout, &bmppath.SVGOptions{FillRule: bmppath.EvenOdd})
+
+	// Output:
+	// <?xml version="1.0" encoding="utf-8"?>
+	// <svg version="1.1" xmlns="http://www.w3.org/2000/svg" viewBox="0 0 3 3">
+	// <path fill="#fff" d="m0,0h3v3h-3z"/><path fill-rule="evenodd" d="m0,0h3v3h-3zm1,1v1h1v-1z"/>
+	// </svg>
+}
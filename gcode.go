@@ -0,0 +1,110 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// GCodeOptions represents the options for WriteGCode.
+type GCodeOptions struct {
+	// Format formats the coordinates and the other values. If nil, the
+	// values are written with at most 4 digits after the decimal point.
+	Format CoordFormatter
+
+	// Scale is the length in the machine units per pixel. Zero means the
+	// default value 1.
+	Scale float64
+
+	// Inch selects the inches as the machine units with G20, instead of the
+	// millimeters with G21.
+	Inch bool
+
+	// InvertY flips the paths vertically, so that the image is not upside
+	// down on the machines whose y-axis points away from the operator, with
+	// the lower left corner of the canvas at the origin.
+	InvertY bool
+
+	// FeedRate is the feed rate of the G1 moves, in the machine units per
+	// minute. Zero omits the F word to use the current feed rate.
+	FeedRate float64
+
+	// Laser turns the laser on with M3 at the start of each closed path and
+	// off with M5 at the end of it, so that the laser is off during the G0
+	// rapid moves between them.
+	Laser bool
+
+	// Power is the laser power written as the S word of M3. Zero omits it.
+	Power float64
+}
+
+// WriteGCode writes the closed paths as a G-code program for the CNC machines
+// and the laser cutters. The tool moves to the start of each closed path with
+// a G0 rapid move, and then follows its vertices with G1 moves back to the
+// start. The closed paths are cut in the order of p.Vertices, so use
+// Options.Ordering or Reorder beforehand to minimize the rapid moves. nil opts
+// is the same as the zero value of GCodeOptions.
+func (p *Path) WriteGCode(w io.Writer, opts *GCodeOptions) error {
+	if opts == nil {
+		opts = &GCodeOptions{}
+	}
+	f := opts.Format
+	if f == nil {
+		f = FormatCoord(4, "")
+	}
+	scale := opts.Scale
+	if scale == 0 {
+		scale = 1
+	}
+	xy := func(v Vertex) string {
+		y := v[1]
+		if opts.InvertY {
+			y = p.Height - y
+		}
+		return fmt.Sprintf("X%s Y%s", f(float64(v[0])*scale), f(float64(y)*scale))
+	}
+
+	var sb strings.Builder
+	sb.WriteString("G90\n")
+	if opts.Inch {
+		sb.WriteString("G20\n")
+	} else {
+		sb.WriteString("G21\n")
+	}
+	for _, vs := range p.Vertices {
+		if len(vs) == 0 {
+			continue
+		}
+		fmt.Fprintf(&sb, "G0 %s\n", xy(vs[0]))
+		if opts.Laser {
+			sb.WriteString("M3")
+			if opts.Power != 0 {
+				fmt.Fprintf(&sb, " S%s", f(opts.Power))
+			}
+			sb.WriteString("\n")
+		}
+		for i := range vs {
+			fmt.Fprintf(&sb, "G1 %s", xy(vs[(i+1)%len(vs)]))
+			if i == 0 && opts.FeedRate != 0 {
+				fmt.Fprintf(&sb, " F%s", f(opts.FeedRate))
+			}
+			sb.WriteString("\n")
+		}
+		if opts.Laser {
+			sb.WriteString("M5\n")
+		}
+		if _, err := io.WriteString(w, sb.String()); err != nil {
+			return fmt.Errorf("write failure: %w", err)
+		}
+		sb.Reset()
+	}
+	sb.WriteString("M2\n")
+	if _, err := io.WriteString(w, sb.String()); err != nil {
+		return fmt.Errorf("write failure: %w", err)
+	}
+	return nil
+}
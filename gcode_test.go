@@ -0,0 +1,43 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath_test
+
+import (
+	"os"
+
+	"github.com/tunabay/go-bitarray"
+	"github.com/tunabay/go-bmppath"
+)
+
+func ExamplePath_WriteGCode() {
+	bmp := bitarray.NewBufferFromBitArray(bitarray.MustParse("0110"))
+	path, err := bmppath.New(bmp, 4)
+	if err != nil {
+		panic(err)
+	}
+
+	opts := &bmppath.GCodeOptions{
+		Scale:    0.1,
+		InvertY:  true,
+		FeedRate: 600,
+		Laser:    true,
+		Power:    1000,
+	}
+	if err := path.WriteGCode(os.Stdout, opts); err != nil {
+		panic(err)
+	}
+
+	// Output:
+	// G90
+	// G21
+	// G0 X0.1 Y0.1
+	// M3 S1000
+	// G1 X0.3 Y0.1 F600
+	// G1 X0.3 Y0
+	// G1 X0.1 Y0
+	// G1 X0.1 Y0.1
+	// M5
+	// M2
+}
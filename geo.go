@@ -0,0 +1,232 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// GeoOptions represents the options for WriteGeoJSON and WKT.
+type GeoOptions struct {
+	// Transform is the affine transformation from the pixel coordinates
+	// (px, py) to the world coordinates (x, y), in the same form as the
+	// GeoTransform of GDAL:
+	//
+	//	x = Transform[0] + px*Transform[1] + py*Transform[2]
+	//	y = Transform[3] + px*Transform[4] + py*Transform[5]
+	//
+	// The zero value means the identity, which writes the pixel coordinates
+	// as they are.
+	Transform [6]float64
+
+	// Format formats the coordinates. If nil, the shortest representation
+	// of each value is used.
+	Format CoordFormatter
+
+	// Grid, if not nil, records the pixel grid of the source raster as the
+	// properties of a Feature object wrapping the geometry, such as
+	// pixelWidth, written by WriteGeoJSON. It is not used by WKT.
+	Grid *PixelGrid
+}
+
+// WriteGeoJSON writes the closed paths as a GeoJSON geometry object, a Polygon
+// if there is exactly one polygon, or a MultiPolygon otherwise. Each outline
+// becomes the exterior ring of a polygon, with the holes directly inside it as
+// the interior rings, and the islands inside the holes become the separate
+// polygons. The outlines and the holes are determined by the nesting depths,
+// as Hierarchy does. The rings are closed, and oriented as RFC 7946 requires
+// after the transformation, that is, the exterior rings are counterclockwise
+// and the interior rings are clockwise with the y-axis pointing upward. If
+// opts.Grid is set, the geometry is wrapped in a Feature object with the
+// metadata of the pixel grid as its properties. If p has the Labels, it writes
+// a FeatureCollection object instead, with a Feature object of a Polygon for
+// each outline, having the label of the outline as the "label" property in
+// addition to the metadata of the pixel grid. nil opts is the same as the
+// zero value of GeoOptions.
+func (p *Path) WriteGeoJSON(w io.Writer, opts *GeoOptions) error {
+	polys, outlines, f := p.geoPolygons(opts)
+	var sb strings.Builder
+	writePolygon := func(poly [][]FloatVertex) {
+		sb.WriteString("[")
+		for i, r := range poly {
+			if i != 0 {
+				sb.WriteString(",")
+			}
+			sb.WriteString("[")
+			for j, v := range r {
+				if j != 0 {
+					sb.WriteString(",")
+				}
+				fmt.Fprintf(&sb, "[%s,%s]", f(v[0]), f(v[1]))
+			}
+			sb.WriteString("]")
+		}
+		sb.WriteString("]")
+	}
+	grid := opts != nil && opts.Grid != nil
+	writeProperties := func() {
+		if !grid {
+			return
+		}
+		for i, gf := range opts.Grid.fields(float64(p.Width), float64(p.Height)) {
+			if i != 0 {
+				sb.WriteString(",")
+			}
+			fmt.Fprintf(&sb, "%q:", gf.name)
+			switch v := gf.value.(type) {
+			case float64:
+				sb.WriteString(defaultCoordFormatter(v))
+			case string:
+				b, _ := json.Marshal(v)
+				sb.Write(b)
+			}
+		}
+	}
+	if p.Labels != nil {
+		sb.WriteString(`{"type":"FeatureCollection","features":[`)
+		for i, poly := range polys {
+			if i != 0 {
+				sb.WriteString(",")
+			}
+			sb.WriteString(`{"type":"Feature","properties":{`)
+			writeProperties()
+			if grid {
+				sb.WriteString(",")
+			}
+			b, _ := json.Marshal(p.PathLabel(outlines[i]))
+			sb.WriteString(`"label":`)
+			sb.Write(b)
+			sb.WriteString(`},"geometry":{"type":"Polygon","coordinates":`)
+			writePolygon(poly)
+			sb.WriteString("}}")
+		}
+		sb.WriteString("]}")
+	} else {
+		if grid {
+			sb.WriteString(`{"type":"Feature","properties":{`)
+			writeProperties()
+			sb.WriteString(`},"geometry":`)
+		}
+		if len(polys) == 1 {
+			sb.WriteString(`{"type":"Polygon","coordinates":`)
+			writePolygon(polys[0])
+		} else {
+			sb.WriteString(`{"type":"MultiPolygon","coordinates":[`)
+			for i, poly := range polys {
+				if i != 0 {
+					sb.WriteString(",")
+				}
+				writePolygon(poly)
+			}
+			sb.WriteString("]")
+		}
+		sb.WriteString("}")
+		if grid {
+			sb.WriteString("}")
+		}
+	}
+	sb.WriteString("\n")
+	if _, err := io.WriteString(w, sb.String()); err != nil {
+		return fmt.Errorf("write failure: %w", err)
+	}
+	return nil
+}
+
+// WKT returns the Well-Known Text representation of the closed paths, a
+// POLYGON if there is exactly one polygon, or a MULTIPOLYGON otherwise. The
+// polygons and the rings are the same as WriteGeoJSON writes. nil opts is the
+// same as the zero value of GeoOptions.
+func (p *Path) WKT(opts *GeoOptions) string {
+	polys, _, f := p.geoPolygons(opts)
+	var sb strings.Builder
+	writePolygon := func(poly [][]FloatVertex) {
+		sb.WriteString("(")
+		for i, r := range poly {
+			if i != 0 {
+				sb.WriteString(", ")
+			}
+			sb.WriteString("(")
+			for j, v := range r {
+				if j != 0 {
+					sb.WriteString(", ")
+				}
+				fmt.Fprintf(&sb, "%s %s", f(v[0]), f(v[1]))
+			}
+			sb.WriteString(")")
+		}
+		sb.WriteString(")")
+	}
+	switch len(polys) {
+	case 0:
+		return "MULTIPOLYGON EMPTY"
+	case 1:
+		sb.WriteString("POLYGON ")
+		writePolygon(polys[0])
+	default:
+		sb.WriteString("MULTIPOLYGON (")
+		for i, poly := range polys {
+			if i != 0 {
+				sb.WriteString(", ")
+			}
+			writePolygon(poly)
+		}
+		sb.WriteString(")")
+	}
+	return sb.String()
+}
+
+// geoPolygons returns the polygons of p transformed and oriented as specified
+// by opts, the indexes of the closed paths of their exterior rings, and the
+// formatter of the coordinates.
+func (p *Path) geoPolygons(opts *GeoOptions) ([][][]FloatVertex, []int, CoordFormatter) {
+	if opts == nil {
+		opts = &GeoOptions{}
+	}
+	f := opts.Format
+	if f == nil {
+		f = defaultCoordFormatter
+	}
+	t := opts.Transform
+	if t == ([6]float64{}) {
+		t = [6]float64{0, 1, 0, 0, 0, 1}
+	}
+	ring := func(vs []Vertex, exterior bool) []FloatVertex {
+		r := make([]FloatVertex, 0, len(vs)+1)
+		for _, v := range vs {
+			px, py := float64(v[0]), float64(v[1])
+			r = append(r, FloatVertex{t[0] + px*t[1] + py*t[2], t[3] + px*t[4] + py*t[5]})
+		}
+		// floatSignedArea is positive for the counterclockwise rings with
+		// the y-axis pointing upward
+		if (0 < floatSignedArea(r)) != exterior {
+			for i, j := 0, len(r)-1; i < j; i, j = i+1, j-1 {
+				r[i], r[j] = r[j], r[i]
+			}
+		}
+		return append(r, r[0])
+	}
+
+	var ret [][][]FloatVertex
+	var exteriors []int
+	var walk func(outlines []*Node)
+	walk = func(outlines []*Node) {
+		for _, o := range outlines {
+			poly := [][]FloatVertex{ring(p.Vertices[o.Index], true)}
+			for _, h := range o.Children {
+				poly = append(poly, ring(p.Vertices[h.Index], false))
+			}
+			ret = append(ret, poly)
+			exteriors = append(exteriors, o.Index)
+			for _, h := range o.Children {
+				walk(h.Children)
+			}
+		}
+	}
+	walk(p.Hierarchy().Children)
+	return ret, exteriors, f
+}
@@ -0,0 +1,141 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/tunabay/go-bitarray"
+	"github.com/tunabay/go-bmppath"
+)
+
+func ExamplePath_WKT() {
+	bmp := bitarray.NewBufferFromBitArray(bitarray.MustParse(strings.Join([]string{
+		"11101",
+		"10100",
+		"11100",
+	}, "")))
+	path, err := bmppath.New(bmp, 5)
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Println(path.WKT(nil))
+	if err := path.WriteGeoJSON(os.Stdout, nil); err != nil {
+		panic(err)
+	}
+
+	// Output:
+	// MULTIPOLYGON (((0 0, 3 0, 3 3, 0 3, 0 0), (1 1, 1 2, 2 2, 2 1, 1 1)), ((4 0, 5 0, 5 1, 4 1, 4 0)))
+	// {"type":"MultiPolygon","coordinates":[[[[0,0],[3,0],[3,3],[0,3],[0,0]],[[1,1],[1,2],[2,2],[2,1],[1,1]]],[[[4,0],[5,0],[5,1],[4,1],[4,0]]]]}
+}
+
+func TestPath_WriteGeoJSON_transform(t *testing.T) {
+	bmp := bitarray.NewBufferFromBitArray(bitarray.MustParse(strings.Join([]string{
+		"111",
+		"101",
+		"111",
+	}, "")))
+	path, err := bmppath.New(bmp, 3)
+	if err != nil {
+		t.Fatalf("New(): %v", err)
+	}
+	// north-up raster with 10m pixels, which flips the y-axis
+	opts := &bmppath.GeoOptions{Transform: [6]float64{500000, 10, 0, 4000000, 0, -10}}
+	var sb strings.Builder
+	if err := path.WriteGeoJSON(&sb, opts); err != nil {
+		t.Fatalf("WriteGeoJSON(): %v", err)
+	}
+	var geom struct {
+		Type        string
+		Coordinates [][][2]float64
+	}
+	if err := json.Unmarshal([]byte(sb.String()), &geom); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, sb.String())
+	}
+	if geom.Type != "Polygon" || len(geom.Coordinates) != 2 {
+		t.Fatalf("unexpected geometry: %s", sb.String())
+	}
+	area := func(r [][2]float64) float64 {
+		a := 0.0
+		for i := 0; i < len(r)-1; i++ {
+			a += r[i][0]*r[i+1][1] - r[i+1][0]*r[i][1]
+		}
+		return a / 2
+	}
+	for i, r := range geom.Coordinates {
+		if r[0] != r[len(r)-1] {
+			t.Errorf("ring #%d is not closed: %v", i, r)
+		}
+		// RFC 7946: the exterior ring is counterclockwise
+		if a := area(r); (i == 0) != (0 < a) {
+			t.Errorf("ring #%d: unexpected orientation: area %g", i, a)
+		}
+	}
+	if want := [2]float64{500000, 4000000}; geom.Coordinates[0][0] != want && geom.Coordinates[0][len(geom.Coordinates[0])-2] != want {
+		t.Errorf("unexpected coordinates: %v", geom.Coordinates[0])
+	}
+	if got, want := path.WKT(nil), "POLYGON ((0 0, 3 0, 3 3, 0 3, 0 0), (1 1, 1 2, 2 2, 2 1, 1 1))"; got != want {
+		t.Errorf("unexpected WKT: got %q, want %q", got, want)
+	}
+	if got := (&bmppath.Path{Width: 1, Height: 1}).WKT(nil); got != "MULTIPOLYGON EMPTY" {
+		t.Errorf("unexpected WKT: %q", got)
+	}
+}
+
+func TestPath_WriteGeoJSON_labels(t *testing.T) {
+	path := &bmppath.Path{
+		Width:  5,
+		Height: 5,
+		Vertices: [][]bmppath.Vertex{
+			{{0, 0}, {5, 0}, {5, 5}, {0, 5}},
+			{{1, 1}, {1, 4}, {4, 4}, {4, 1}},
+			{{2, 2}, {3, 2}, {3, 3}, {2, 3}},
+		},
+		Labels: []string{"frame", "hole", `"dot"`},
+	}
+	for _, opts := range []*bmppath.GeoOptions{nil, {Grid: &bmppath.PixelGrid{Unit: "m"}}} {
+		var sb strings.Builder
+		if err := path.WriteGeoJSON(&sb, opts); err != nil {
+			t.Fatalf("WriteGeoJSON(): %v", err)
+		}
+		var fc struct {
+			Type     string
+			Features []struct {
+				Type       string
+				Properties map[string]interface{}
+				Geometry   struct {
+					Type        string
+					Coordinates [][][2]float64
+				}
+			}
+		}
+		if err := json.Unmarshal([]byte(sb.String()), &fc); err != nil {
+			t.Fatalf("invalid JSON: %v\n%s", err, sb.String())
+		}
+		if fc.Type != "FeatureCollection" || len(fc.Features) != 2 {
+			t.Fatalf("unexpected object: %s", sb.String())
+		}
+		for i, want := range []struct {
+			label  string
+			nRings int
+		}{{"frame", 2}, {`"dot"`, 1}} {
+			f := fc.Features[i]
+			if f.Type != "Feature" || f.Geometry.Type != "Polygon" || len(f.Geometry.Coordinates) != want.nRings {
+				t.Errorf("#%d: unexpected feature: %s", i, sb.String())
+			}
+			if got := f.Properties["label"]; got != want.label {
+				t.Errorf("#%d: unexpected label: got %v, want %q", i, got, want.label)
+			}
+			if got, want := f.Properties["unit"] != nil, opts != nil; got != want {
+				t.Errorf("#%d: unexpected grid properties: %v", i, f.Properties)
+			}
+		}
+	}
+}
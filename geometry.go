@@ -0,0 +1,246 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// FloatVertex represents a point on the plane with floating point coordinates.
+type FloatVertex [2]float64
+
+// String returns the string representation of a FloatVertex in "(x, y)"
+// format.
+func (v FloatVertex) String() string { return fmt.Sprintf("(%g, %g)", v[0], v[1]) }
+
+// X returns the x-coordinate of FloatVertex.
+func (v FloatVertex) X() float64 { return v[0] }
+
+// Y returns the y-coordinate of FloatVertex.
+func (v FloatVertex) Y() float64 { return v[1] }
+
+// Circle represents a circle by its center and radius.
+type Circle struct {
+	Center FloatVertex
+	Radius float64
+}
+
+// OrientedBox represents a rectangle that is not necessarily aligned to the
+// axes. Angle is the rotation of the side of the length Width from the x-axis,
+// in radians, clockwise on the screen coordinates.
+type OrientedBox struct {
+	Center        FloatVertex
+	Width, Height float64
+	Angle         float64
+}
+
+// Area returns the area of the OrientedBox.
+func (b OrientedBox) Area() float64 { return b.Width * b.Height }
+
+// Corners returns the four corners of the OrientedBox. The first corner is the
+// one that is the upper left corner when the box is rotated back by Angle.
+func (b OrientedBox) Corners() [4]FloatVertex {
+	sin, cos := math.Sincos(b.Angle)
+	hw, hh := b.Width/2, b.Height/2
+	var ret [4]FloatVertex
+	for i, d := range [4][2]float64{{-hw, -hh}, {hw, -hh}, {hw, hh}, {-hw, hh}} {
+		ret[i] = FloatVertex{
+			b.Center[0] + d[0]*cos - d[1]*sin,
+			b.Center[1] + d[0]*sin + d[1]*cos,
+		}
+	}
+	return ret
+}
+
+// EnclosingCircle returns the smallest circle that encloses the closed path
+// specified by the index n.
+func (p *Path) EnclosingCircle(n int) Circle {
+	return enclosingCircle(convexHull(floatVertices(p.Vertices[n])))
+}
+
+// OrientedBoundingBox returns the minimum-area rectangle that encloses the
+// closed path specified by the index n. The Angle of the result is in the range
+// [0, π/2). When multiple rectangles have the same area, the one with the
+// smallest Angle is returned, so the axis-aligned bounding box is preferred.
+func (p *Path) OrientedBoundingBox(n int) OrientedBox {
+	return minAreaBox(convexHull(floatVertices(p.Vertices[n])))
+}
+
+// ConvexHull returns the convex hull of all the vertices of all the closed
+// paths, as a polygon running clockwise on the screen coordinates and starting
+// from the vertex closest to the origin. It is useful as a fast collision bound,
+// or for placing traced images on a sheet.
+func (p *Path) ConvexHull() []Vertex {
+	var pts []FloatVertex
+	for _, vs := range p.Vertices {
+		pts = append(pts, floatVertices(vs)...)
+	}
+	return intVertices(convexHull(pts))
+}
+
+// PathConvexHull is identical to ConvexHull except that it returns the convex
+// hull of the closed path specified by the index n.
+func (p *Path) PathConvexHull(n int) []Vertex {
+	return intVertices(convexHull(floatVertices(p.Vertices[n])))
+}
+
+// intVertices converts the hull vertices pts on the integer grid back to
+// Vertex, normalizing the starting vertex.
+func intVertices(pts []FloatVertex) []Vertex {
+	ret := make([]Vertex, len(pts))
+	for i, pt := range pts {
+		ret[i] = Vertex{int(pt[0]), int(pt[1])}
+	}
+	normalizeVertices(ret)
+	return ret
+}
+
+// enclosingCircle returns the smallest circle that encloses the points pts.
+func enclosingCircle(pts []FloatVertex) Circle {
+	if len(pts) == 0 {
+		return Circle{}
+	}
+	c := Circle{Center: pts[0]}
+	for i := 1; i < len(pts); i++ {
+		if c.encloses(pts[i]) {
+			continue
+		}
+		c = Circle{Center: pts[i]}
+		for j := 0; j < i; j++ {
+			if c.encloses(pts[j]) {
+				continue
+			}
+			c = circle2(pts[i], pts[j])
+			for k := 0; k < j; k++ {
+				if !c.encloses(pts[k]) {
+					c = circle3(pts[i], pts[j], pts[k])
+				}
+			}
+		}
+	}
+	return c
+}
+
+// minAreaBox returns the minimum-area rectangle that encloses the convex hull
+// hull, by examining the rectangles aligned to each edge of the hull.
+func minAreaBox(hull []FloatVertex) OrientedBox {
+	var best OrientedBox
+	found := false
+	for i, v0 := range hull {
+		v1 := hull[(i+1)%len(hull)]
+		theta := math.Atan2(v1[1]-v0[1], v1[0]-v0[0])
+		theta = math.Mod(theta+2*math.Pi, math.Pi/2)
+		if math.Pi/2-theta < 1e-12 {
+			theta = 0
+		}
+		b := boxAt(hull, theta)
+		switch a, ba := b.Area(), best.Area(); {
+		case !found, a < ba-ba*1e-12:
+		case a <= ba+ba*1e-12 && theta < best.Angle:
+		default:
+			continue
+		}
+		best, found = b, true
+	}
+	return best
+}
+
+// boxAt returns the bounding box of the points pts aligned to the axes rotated
+// by theta.
+func boxAt(pts []FloatVertex, theta float64) OrientedBox {
+	sin, cos := math.Sincos(theta)
+	minu, maxu := math.Inf(1), math.Inf(-1)
+	minv, maxv := math.Inf(1), math.Inf(-1)
+	for _, pt := range pts {
+		u, v := pt[0]*cos+pt[1]*sin, -pt[0]*sin+pt[1]*cos
+		minu, maxu = math.Min(minu, u), math.Max(maxu, u)
+		minv, maxv = math.Min(minv, v), math.Max(maxv, v)
+	}
+	cu, cv := (minu+maxu)/2, (minv+maxv)/2
+	return OrientedBox{
+		Center: FloatVertex{cu*cos - cv*sin, cu*sin + cv*cos},
+		Width:  maxu - minu,
+		Height: maxv - minv,
+		Angle:  theta,
+	}
+}
+
+func (c Circle) encloses(v FloatVertex) bool {
+	return math.Hypot(v[0]-c.Center[0], v[1]-c.Center[1]) <= c.Radius*(1+1e-12)+1e-12
+}
+
+func circle2(a, b FloatVertex) Circle {
+	return Circle{
+		Center: FloatVertex{(a[0] + b[0]) / 2, (a[1] + b[1]) / 2},
+		Radius: math.Hypot(a[0]-b[0], a[1]-b[1]) / 2,
+	}
+}
+
+func circle3(a, b, c FloatVertex) Circle {
+	bx, by := b[0]-a[0], b[1]-a[1]
+	cx, cy := c[0]-a[0], c[1]-a[1]
+	d := 2 * (bx*cy - by*cx)
+	if d == 0 {
+		ret := circle2(a, b)
+		for _, cc := range []Circle{circle2(a, c), circle2(b, c)} {
+			if ret.Radius < cc.Radius {
+				ret = cc
+			}
+		}
+		return ret
+	}
+	b2, c2 := bx*bx+by*by, cx*cx+cy*cy
+	ux, uy := (cy*b2-by*c2)/d, (bx*c2-cx*b2)/d
+	return Circle{
+		Center: FloatVertex{a[0] + ux, a[1] + uy},
+		Radius: math.Hypot(ux, uy),
+	}
+}
+
+// floatVertices converts the vertices vs to FloatVertex.
+func floatVertices(vs []Vertex) []FloatVertex {
+	ret := make([]FloatVertex, len(vs))
+	for i, v := range vs {
+		ret[i] = FloatVertex{float64(v[0]), float64(v[1])}
+	}
+	return ret
+}
+
+// convexHull returns the convex hull of the points pts, using Andrew's monotone
+// chain algorithm. The hull runs clockwise on the screen coordinates and does
+// not contain collinear points.
+func convexHull(pts []FloatVertex) []FloatVertex {
+	pts = append([]FloatVertex(nil), pts...)
+	sort.Slice(pts, func(i, j int) bool {
+		if pts[i][0] != pts[j][0] {
+			return pts[i][0] < pts[j][0]
+		}
+		return pts[i][1] < pts[j][1]
+	})
+	if len(pts) < 3 {
+		return pts
+	}
+	cross := func(o, a, b FloatVertex) float64 {
+		return (a[0]-o[0])*(b[1]-o[1]) - (a[1]-o[1])*(b[0]-o[0])
+	}
+	hull := make([]FloatVertex, 0, len(pts)*2)
+	for _, pt := range pts {
+		for len(hull) >= 2 && cross(hull[len(hull)-2], hull[len(hull)-1], pt) <= 0 {
+			hull = hull[:len(hull)-1]
+		}
+		hull = append(hull, pt)
+	}
+	lower := len(hull) + 1
+	for i := len(pts) - 2; 0 <= i; i-- {
+		pt := pts[i]
+		for lower <= len(hull) && cross(hull[len(hull)-2], hull[len(hull)-1], pt) <= 0 {
+			hull = hull[:len(hull)-1]
+		}
+		hull = append(hull, pt)
+	}
+	return hull[:len(hull)-1]
+}
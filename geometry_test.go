@@ -0,0 +1,99 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath_test
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/tunabay/go-bitarray"
+	"github.com/tunabay/go-bmppath"
+)
+
+func TestPath_EnclosingCircle(t *testing.T) {
+	bmp := bitarray.NewBufferFromBitArray(
+		bitarray.MustParse(strings.Join([]string{
+			"0110",
+			"1111",
+			"0110",
+		}, "")),
+	)
+	path, err := bmppath.New(bmp, 4)
+	if err != nil {
+		t.Fatalf("New(): %v", err)
+	}
+	c := path.EnclosingCircle(0)
+	if math.Abs(c.Center.X()-2) > 1e-9 || math.Abs(c.Center.Y()-1.5) > 1e-9 {
+		t.Errorf("unexpected center: %s", c.Center)
+	}
+	if want := math.Hypot(2, 0.5); math.Abs(c.Radius-want) > 1e-9 {
+		t.Errorf("unexpected radius: got %g, want %g", c.Radius, want)
+	}
+}
+
+func TestPath_OrientedBoundingBox(t *testing.T) {
+	bmp := bitarray.NewBufferFromBitArray(
+		bitarray.MustParse(strings.Join([]string{
+			"11000",
+			"11100",
+			"01110",
+			"00111",
+			"00011",
+		}, "")),
+	)
+	path, err := bmppath.New(bmp, 5)
+	if err != nil {
+		t.Fatalf("New(): %v", err)
+	}
+	b := path.OrientedBoundingBox(0)
+	if math.Abs(b.Angle-math.Pi/4) > 1e-9 {
+		t.Errorf("unexpected angle: got %g, want π/4", b.Angle)
+	}
+	if b.Area() >= 25 {
+		t.Errorf("unexpected area: got %g, want < 25", b.Area())
+	}
+	if math.Abs(b.Center.X()-2.5) > 1e-9 || math.Abs(b.Center.Y()-2.5) > 1e-9 {
+		t.Errorf("unexpected center: %s", b.Center)
+	}
+
+	bmp = bitarray.NewBufferFromBitArray(bitarray.MustParse("111111"))
+	if path, err = bmppath.New(bmp, 3); err != nil {
+		t.Fatalf("New(): %v", err)
+	}
+	b = path.OrientedBoundingBox(0)
+	if b.Angle != 0 || b.Width != 3 || b.Height != 2 {
+		t.Errorf("unexpected box: %+v", b)
+	}
+	want := [4]bmppath.FloatVertex{{0, 0}, {3, 0}, {3, 2}, {0, 2}}
+	for i, c := range b.Corners() {
+		if math.Abs(c.X()-want[i].X()) > 1e-9 || math.Abs(c.Y()-want[i].Y()) > 1e-9 {
+			t.Errorf("corner #%d: got %s, want %s", i, c, want[i])
+		}
+	}
+}
+
+func ExamplePath_ConvexHull() {
+	bmp := bitarray.NewBufferFromBitArray(
+		bitarray.MustParse(strings.Join([]string{
+			"01000",
+			"11100",
+			"01000",
+			"00001",
+		}, "")),
+	)
+	path, err := bmppath.New(bmp, 5)
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Println(path.PathConvexHull(0))
+	fmt.Println(path.ConvexHull())
+
+	// Output:
+	// [(0, 1) (1, 0) (2, 0) (3, 1) (3, 2) (2, 3) (1, 3) (0, 2)]
+	// [(0, 1) (1, 0) (2, 0) (5, 3) (5, 4) (4, 4) (1, 3) (0, 2)]
+}
@@ -0,0 +1,90 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package geompath_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/tunabay/go-bitarray"
+	"github.com/tunabay/go-bmppath"
+	"github.com/tunabay/go-bmppath/geompath"
+	"github.com/twpayne/go-geom"
+)
+
+func ExampleMultiPolygon() {
+	bmp := bitarray.NewBufferFromBitArray(bitarray.MustParse(strings.Join([]string{
+		"1110",
+		"1010",
+		"1110",
+	}, "")))
+	path, err := bmppath.New(bmp, 4)
+	if err != nil {
+		panic(err)
+	}
+
+	mp := geompath.MultiPolygon(path)
+	for i := 0; i < mp.NumPolygons(); i++ {
+		poly := mp.Polygon(i)
+		for j := 0; j < poly.NumLinearRings(); j++ {
+			fmt.Println(poly.LinearRing(j).Coords())
+		}
+	}
+
+	// Output:
+	// [[0 0] [3 0] [3 3] [0 3] [0 0]]
+	// [[1 1] [1 2] [2 2] [2 1] [1 1]]
+}
+
+func TestFromGeometry(t *testing.T) {
+	bmp := bitarray.NewBufferFromBitArray(bitarray.MustParse(strings.Join([]string{
+		"1111111",
+		"1000001",
+		"1011101",
+		"1010101",
+		"1011101",
+		"1000001",
+		"1111111",
+	}, "")))
+	path, err := bmppath.New(bmp, 7)
+	if err != nil {
+		t.Fatalf("New(): %v", err)
+	}
+	mp := geompath.MultiPolygon(path)
+	if mp.NumPolygons() != 2 || mp.Polygon(0).NumLinearRings() != 2 || mp.Polygon(1).NumLinearRings() != 2 {
+		t.Fatalf("unexpected structure: %v", mp.Coords())
+	}
+	fp, err := geompath.FromGeometry(mp, 7, 7)
+	if err != nil {
+		t.Fatalf("FromGeometry(): %v", err)
+	}
+	if !fp.Rasterize().BitArray().Equal(bmp.BitArray()) {
+		t.Errorf("unexpected result: %v", fp.Vertices)
+	}
+
+	// the orientation of the rings is not significant
+	coords := mp.Coords()
+	for _, poly := range coords {
+		for _, r := range poly {
+			for i, j := 0, len(r)-1; i < j; i, j = i+1, j-1 {
+				r[i], r[j] = r[j], r[i]
+			}
+		}
+	}
+	reversed := geom.NewMultiPolygon(geom.XY).MustSetCoords(coords)
+	if fp, err = geompath.FromGeometry(reversed, 7, 7); err != nil {
+		t.Fatalf("FromGeometry(): %v", err)
+	}
+	if !fp.Path().Equal(path) {
+		t.Errorf("unexpected result: %v", fp.Vertices)
+	}
+
+	pt := geom.NewPoint(geom.XY).MustSetCoords(geom.Coord{1, 2})
+	if _, err := geompath.FromGeometry(pt, 7, 7); !errors.Is(err, geompath.ErrUnsupportedType) {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
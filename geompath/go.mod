@@ -0,0 +1,11 @@
+module github.com/tunabay/go-bmppath/geompath
+
+go 1.18
+
+require (
+	github.com/tunabay/go-bitarray v1.3.1
+	github.com/tunabay/go-bmppath v0.0.0-00010101000000-000000000000
+	github.com/twpayne/go-geom v1.5.2
+)
+
+replace github.com/tunabay/go-bmppath => ../
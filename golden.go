@@ -0,0 +1,196 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath
+
+import (
+	_ "embed" // for the golden corpus
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/tunabay/go-bitarray"
+)
+
+//go:embed golden/corpus.txt
+var goldenCorpus string
+
+// GoldenVersion is the version of the golden corpus returned by GoldenCorpus.
+// It is incremented whenever an expected output changes, so that the changes
+// of the behavior at the edge cases are explicit.
+const GoldenVersion = 1
+
+// GoldenCase is a case of the golden corpus, a tricky bitmap image such as a
+// checkerboard or the rings touching at a corner, and the closed paths
+// expected to be traced from it.
+type GoldenCase struct {
+	// Name is the unique name of the case.
+	Name string
+
+	// Description describes the case and the expected behavior.
+	Description string
+
+	// Width is the width of the bitmap image.
+	Width int
+
+	// Bitmap is the bitmap image.
+	Bitmap *bitarray.Buffer
+
+	// Want is the expected result, compared in the canonical form as Equal
+	// does.
+	Want *Path
+}
+
+// GoldenCorpus returns the golden corpus, the curated cases of the tricky
+// bitmap images with the expected results, which documents the behavior of New
+// at the edge cases as a testable contract. The corpus of the version
+// GoldenVersion is embedded in the package. A new slice is returned each time,
+// so that it can be modified by the caller.
+func GoldenCorpus() []*GoldenCase {
+	cases, err := parseGoldenCorpus(goldenCorpus)
+	if err != nil {
+		panic("bmppath: broken golden corpus: " + err.Error())
+	}
+	return cases
+}
+
+// GoldenFailure is a case of the golden corpus failed in CheckGolden.
+type GoldenFailure struct {
+	// Name is the name of the case.
+	Name string
+
+	// Diff is the difference between the expected and the actual results
+	// returned by Diff, or empty if Err is not nil.
+	Diff string
+
+	// Err is the error returned by the trace function.
+	Err error
+}
+
+// CheckGolden traces the bitmap image of each case of the golden corpus with
+// trace, and returns the failed cases in the order of the corpus. It returns
+// nil if all the cases pass. nil trace is the same as New. This allows the
+// wrappers and the alternative implementations to be verified against the
+// same contract as this package, such as:
+//
+//	for _, f := range bmppath.CheckGolden(nil) {
+//		t.Errorf("%s: %v\n%s", f.Name, f.Err, f.Diff)
+//	}
+func CheckGolden(trace func(bm *bitarray.Buffer, width int) (*Path, error)) []GoldenFailure {
+	if trace == nil {
+		trace = New
+	}
+	var ret []GoldenFailure
+	for _, c := range GoldenCorpus() {
+		p, err := trace(c.Bitmap, c.Width)
+		if err != nil {
+			ret = append(ret, GoldenFailure{Name: c.Name, Err: err})
+			continue
+		}
+		if d := Diff(c.Want, p); d != "" {
+			ret = append(ret, GoldenFailure{Name: c.Name, Diff: d})
+		}
+	}
+	return ret
+}
+
+// parseGoldenCorpus parses the golden corpus s.
+func parseGoldenCorpus(s string) ([]*GoldenCase, error) {
+	var ret []*GoldenCase
+	var cur *GoldenCase
+	var rows []string
+	section, version := "", 0
+	names := make(map[string]bool)
+	for i, line := range strings.Split(s, "\n") {
+		errorf := func(format string, a ...interface{}) error {
+			return fmt.Errorf("%w: line %d: %s", ErrInvalidEncoding, i+1, fmt.Sprintf(format, a...))
+		}
+		line = strings.TrimRight(line, " \t\r")
+		switch {
+		case cur == nil && (line == "" || strings.HasPrefix(line, "#")):
+		case cur == nil && strings.HasPrefix(line, "version "):
+			v, err := strconv.Atoi(strings.TrimPrefix(line, "version "))
+			if err != nil || v != GoldenVersion {
+				return nil, errorf("unsupported version %q", line)
+			}
+			version = v
+		case cur == nil && strings.HasPrefix(line, "case "):
+			if version == 0 {
+				return nil, errorf("no version")
+			}
+			cur = &GoldenCase{Name: strings.TrimPrefix(line, "case ")}
+			if names[cur.Name] {
+				return nil, errorf("duplicate case %q", cur.Name)
+			}
+			names[cur.Name], section, rows = true, "", nil
+		case cur == nil:
+			return nil, errorf("unexpected %q", line)
+		case section == "" && strings.HasPrefix(line, "# "):
+			if cur.Description != "" {
+				cur.Description += " "
+			}
+			cur.Description += strings.TrimPrefix(line, "# ")
+		case line == "bitmap" && section == "":
+			section = line
+		case line == "want" && section == "bitmap":
+			if len(rows) == 0 {
+				return nil, errorf("%s: no bitmap", cur.Name)
+			}
+			cur.Width = len(rows[0])
+			ba, err := bitarray.Parse(strings.Join(rows, ""))
+			if err != nil || cur.Width*len(rows) != ba.Len() {
+				return nil, errorf("%s: invalid bitmap", cur.Name)
+			}
+			cur.Bitmap = bitarray.NewBufferFromBitArray(ba)
+			cur.Want = &Path{Width: cur.Width, Height: len(rows)}
+			section = line
+		case line == "end" && section == "want":
+			ret = append(ret, cur)
+			cur = nil
+		case section == "bitmap":
+			if len(rows) != 0 && len(line) != len(rows[0]) {
+				return nil, errorf("%s: row length %d != %d", cur.Name, len(line), len(rows[0]))
+			}
+			rows = append(rows, line)
+		case section == "want":
+			vs, err := parseVertices(line)
+			if err != nil {
+				return nil, errorf("%s: %v", cur.Name, err)
+			}
+			cur.Want.Vertices = append(cur.Want.Vertices, vs)
+		default:
+			return nil, errorf("%s: unexpected %q", cur.Name, line)
+		}
+	}
+	if cur != nil {
+		return nil, fmt.Errorf("%w: %s: no end", ErrInvalidEncoding, cur.Name)
+	}
+	return ret, nil
+}
+
+// parseVertices parses the vertices in the form returned by PathString, such
+// as "(0, 0), (1, 0), (1, 1), (0, 1)".
+func parseVertices(s string) ([]Vertex, error) {
+	if !strings.HasPrefix(s, "(") || !strings.HasSuffix(s, ")") {
+		return nil, fmt.Errorf("malformed vertices %q", s)
+	}
+	fs := strings.Split(s[1:len(s)-1], "), (")
+	ret := make([]Vertex, len(fs))
+	for i, f := range fs {
+		xy := strings.Split(f, ", ")
+		if len(xy) != 2 {
+			return nil, fmt.Errorf("malformed vertex %q", f)
+		}
+		x, err := strconv.Atoi(xy[0])
+		if err != nil {
+			return nil, fmt.Errorf("malformed vertex %q: %w", f, err)
+		}
+		y, err := strconv.Atoi(xy[1])
+		if err != nil {
+			return nil, fmt.Errorf("malformed vertex %q: %w", f, err)
+		}
+		ret[i] = Vertex{x, y}
+	}
+	return ret, nil
+}
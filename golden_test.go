@@ -0,0 +1,62 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/tunabay/go-bitarray"
+	"github.com/tunabay/go-bmppath"
+)
+
+func TestCheckGolden(t *testing.T) {
+	cases := bmppath.GoldenCorpus()
+	if len(cases) == 0 {
+		t.Fatal("empty corpus")
+	}
+	for _, c := range cases {
+		if c.Name == "" || c.Description == "" || c.Want == nil {
+			t.Errorf("incomplete case: %+v", c)
+		}
+	}
+	for _, f := range bmppath.CheckGolden(nil) {
+		t.Errorf("%s: %v\n%s", f.Name, f.Err, f.Diff)
+	}
+
+	// the corpus holds under the alternative tracing of EdgeGrid
+	viaEdgeGrid := func(bm *bitarray.Buffer, width int) (*bmppath.Path, error) {
+		g, err := bmppath.NewEdgeGrid(bm, width, nil)
+		if err != nil {
+			return nil, err
+		}
+		return g.Path(), nil
+	}
+	for _, f := range bmppath.CheckGolden(viaEdgeGrid) {
+		t.Errorf("EdgeGrid: %s: %v\n%s", f.Name, f.Err, f.Diff)
+	}
+
+	// a broken implementation is detected
+	errBroken := errors.New("broken")
+	broken := func(bm *bitarray.Buffer, width int) (*bmppath.Path, error) {
+		if bm.OnesCount() == bm.Len() {
+			return nil, errBroken
+		}
+		p, err := bmppath.New(bm, width)
+		if err != nil {
+			return nil, err
+		}
+		return p.Reorder(bmppath.OrderNone, true).Translate(1, 0), nil
+	}
+	fs := bmppath.CheckGolden(broken)
+	if len(fs) != len(cases) {
+		t.Errorf("unexpected number of failures: %d", len(fs))
+	}
+	for _, f := range fs {
+		if f.Name == "full" && !errors.Is(f.Err, errBroken) {
+			t.Errorf("unexpected failure: %+v", f)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath
+
+import (
+	"errors"
+	"fmt"
+	"go/format"
+	"go/token"
+	"io"
+	"strings"
+)
+
+// ErrInvalidName is an error thrown when the name is not valid for its use.
+var ErrInvalidName = errors.New("invalid name")
+
+// GoTarget specifies the drawing package targeted by the code written by
+// WriteGoSource.
+type GoTarget int
+
+const (
+	// GoTargetVector targets the Rasterizer of golang.org/x/image/vector.
+	GoTargetVector GoTarget = iota
+
+	// GoTargetGG targets the Context of github.com/fogleman/gg.
+	GoTargetGG
+)
+
+// GoSourceOptions represents the options for WriteGoSource.
+type GoSourceOptions struct {
+	// Package is the package name of the generated file. If empty, "main" is
+	// used.
+	Package string
+
+	// Name is the name of the shape, used as the prefix or the suffix of the
+	// declared identifiers. It should start with an upper case letter for
+	// them to be exported. If empty, "Shape" is used.
+	Name string
+
+	// Target is the drawing package targeted by the draw function.
+	Target GoTarget
+}
+
+// WriteGoSource writes a gofmt-ed Go source file that embeds the closed paths
+// as code, so that a traced shape, such as a logo, can be compiled into a
+// binary without shipping SVG assets and a parser. With the default Name
+// "Shape", the file declares the constants ShapeWidth and ShapeHeight for the
+// canvas size, the variable ShapeVertices holding the vertices, and the
+// function DrawShape, which adds the closed paths to a vector.Rasterizer or a
+// gg.Context as specified by opts, scaled by s and translated by (x, y). The
+// caller then draws the rasterizer or calls Fill of the context, whose default
+// nonzero winding rule fills the paths in the same way as WriteSVG. nil opts is
+// the same as the zero value of GoSourceOptions.
+func (p *Path) WriteGoSource(w io.Writer, opts *GoSourceOptions) error {
+	if opts == nil {
+		opts = &GoSourceOptions{}
+	}
+	pkg, name := opts.Package, opts.Name
+	if pkg == "" {
+		pkg = "main"
+	}
+	if name == "" {
+		name = "Shape"
+	}
+	if !token.IsIdentifier(pkg) || pkg == "_" {
+		return fmt.Errorf("%w: package %q", ErrInvalidName, pkg)
+	}
+	if !token.IsIdentifier(name) || name == "_" {
+		return fmt.Errorf("%w: %q", ErrInvalidName, name)
+	}
+
+	imp, elem, ctx := "golang.org/x/image/vector", "float32", "z *vector.Rasterizer"
+	if opts.Target == GoTargetGG {
+		imp, elem, ctx = "github.com/fogleman/gg", "float64", "dc *gg.Context"
+	}
+	recv := ctx[:strings.IndexByte(ctx, ' ')]
+
+	var sb strings.Builder
+	sb.WriteString("// Code generated by bmppath. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&sb, "package %s\n\nimport %q\n\n", pkg, imp)
+	fmt.Fprintf(&sb, "// %[1]sWidth and %[1]sHeight are the size of the canvas of %[1]s.\n", name)
+	fmt.Fprintf(&sb, "const (\n%[1]sWidth = %[2]d\n%[1]sHeight = %[3]d\n)\n\n", name, p.Width, p.Height)
+	fmt.Fprintf(&sb, "// %[1]sVertices is the vertices of the closed paths of %[1]s.\n", name)
+	fmt.Fprintf(&sb, "var %sVertices = [][][2]%s{\n", name, elem)
+	for _, vs := range p.Vertices {
+		if len(vs) == 0 {
+			continue
+		}
+		sb.WriteString("{")
+		for i, v := range vs {
+			if i != 0 {
+				sb.WriteString(", ")
+			}
+			fmt.Fprintf(&sb, "{%d, %d}", v[0], v[1])
+		}
+		sb.WriteString("},\n")
+	}
+	sb.WriteString("}\n\n")
+	fmt.Fprintf(&sb, "// Draw%[1]s adds the closed paths of %[1]s to %[2]s, scaled by s and\n", name, recv)
+	sb.WriteString("// translated by (x, y).\n")
+	fmt.Fprintf(&sb, "func Draw%s(%s, x, y, s %s) {\n", name, ctx, elem)
+	fmt.Fprintf(&sb, "for _, vs := range %sVertices {\n", name)
+	fmt.Fprintf(&sb, "%s.MoveTo(x+vs[0][0]*s, y+vs[0][1]*s)\n", recv)
+	fmt.Fprintf(&sb, "for _, v := range vs[1:] {\n%s.LineTo(x+v[0]*s, y+v[1]*s)\n}\n", recv)
+	fmt.Fprintf(&sb, "%s.ClosePath()\n}\n}\n", recv)
+
+	src, err := format.Source([]byte(sb.String()))
+	if err != nil {
+		return fmt.Errorf("format failure: %w", err)
+	}
+	if _, err := w.Write(src); err != nil {
+		return fmt.Errorf("write failure: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath_test
+
+import (
+	"errors"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+
+	"github.com/tunabay/go-bitarray"
+	"github.com/tunabay/go-bmppath"
+)
+
+func TestPath_WriteGoSource(t *testing.T) {
+	bmp := bitarray.NewBufferFromBitArray(bitarray.MustParse("01101"))
+	path, err := bmppath.New(bmp, 5)
+	if err != nil {
+		t.Fatalf("New(): %v", err)
+	}
+
+	tcs := []struct {
+		opts     *bmppath.GoSourceOptions
+		pkg, imp string
+		decls    []string
+		contains []string
+	}{
+		{
+			nil, "main", `"golang.org/x/image/vector"`,
+			[]string{"ShapeWidth", "ShapeHeight", "ShapeVertices", "DrawShape"},
+			[]string{
+				"\tShapeWidth  = 5\n\tShapeHeight = 1\n",
+				"{{1, 0}, {3, 0}, {3, 1}, {1, 1}},\n",
+				"func DrawShape(z *vector.Rasterizer, x, y, s float32) {",
+			},
+		},
+		{
+			&bmppath.GoSourceOptions{Package: "logo", Name: "Logo", Target: bmppath.GoTargetGG},
+			"logo", `"github.com/fogleman/gg"`,
+			[]string{"LogoWidth", "LogoHeight", "LogoVertices", "DrawLogo"},
+			[]string{
+				"var LogoVertices = [][][2]float64{",
+				"func DrawLogo(dc *gg.Context, x, y, s float64) {",
+				"\t\tdc.ClosePath()\n",
+			},
+		},
+	}
+	for i, tc := range tcs {
+		var sb strings.Builder
+		if err := path.WriteGoSource(&sb, tc.opts); err != nil {
+			t.Errorf("#%d: WriteGoSource(): %v", i, err)
+			continue
+		}
+		src := sb.String()
+		f, err := parser.ParseFile(token.NewFileSet(), "shape.go", src, 0)
+		if err != nil {
+			t.Errorf("#%d: invalid source: %v\n%s", i, err, src)
+			continue
+		}
+		if f.Name.Name != tc.pkg {
+			t.Errorf("#%d: unexpected package: %s", i, f.Name.Name)
+		}
+		if len(f.Imports) != 1 || f.Imports[0].Path.Value != tc.imp {
+			t.Errorf("#%d: unexpected imports: %v", i, f.Imports)
+		}
+		for _, name := range tc.decls {
+			if obj := f.Scope.Lookup(name); obj == nil || !ast.IsExported(name) {
+				t.Errorf("#%d: %s not declared", i, name)
+			}
+		}
+		for _, s := range tc.contains {
+			if !strings.Contains(src, s) {
+				t.Errorf("#%d: %q not found:\n%s", i, s, src)
+			}
+		}
+	}
+
+	for _, opts := range []*bmppath.GoSourceOptions{
+		{Package: "my-logo"},
+		{Name: "func"},
+		{Name: "_"},
+	} {
+		if err := path.WriteGoSource(&strings.Builder{}, opts); !errors.Is(err, bmppath.ErrInvalidName) {
+			t.Errorf("unexpected error for %+v: %v", opts, err)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath
+
+import (
+	"strings"
+	"unicode"
+)
+
+// PixelGrid is the metadata of the pixel grid of the source raster, recorded in
+// the output by WriteSVGOptions, WriteGeoJSON, and WritePDF when specified, so
+// that the downstream tools can re-align the vector output with the source
+// raster exactly. The values are recorded as they are, together with the size
+// of the canvas as the raster size in pixels.
+type PixelGrid struct {
+	// PixelWidth and PixelHeight are the size of a pixel of the source
+	// raster in Unit. Zero means the default value 1.
+	PixelWidth, PixelHeight float64
+
+	// OffsetX and OffsetY are the position of the upper left corner of the
+	// source raster in Unit, such as the position of the cropped area in the
+	// original image, or the world coordinates of a georeferenced raster.
+	OffsetX, OffsetY float64
+
+	// Unit is the unit of the values, such as "mm" or "m". If empty, "px"
+	// is used.
+	Unit string
+}
+
+// gridField is a field of the PixelGrid metadata, the value of which is either
+// a float64 or a string.
+type gridField struct {
+	name  string
+	value interface{}
+}
+
+// fields returns the fields of the metadata of the raster of the size w x h,
+// named in lower camel case. g may be nil, for which it returns nil.
+func (g *PixelGrid) fields(w, h float64) []gridField {
+	if g == nil {
+		return nil
+	}
+	pw, ph, unit := g.PixelWidth, g.PixelHeight, g.Unit
+	if pw == 0 {
+		pw = 1
+	}
+	if ph == 0 {
+		ph = 1
+	}
+	if unit == "" {
+		unit = "px"
+	}
+	return []gridField{
+		{"rasterWidth", w},
+		{"rasterHeight", h},
+		{"pixelWidth", pw},
+		{"pixelHeight", ph},
+		{"offsetX", g.OffsetX},
+		{"offsetY", g.OffsetY},
+		{"unit", unit},
+	}
+}
+
+// kebabCase converts the lower camel case name s into the kebab case.
+func kebabCase(s string) string {
+	var sb strings.Builder
+	for _, r := range s {
+		if unicode.IsUpper(r) {
+			sb.WriteByte('-')
+			r = unicode.ToLower(r)
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
@@ -0,0 +1,79 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath_test
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/tunabay/go-bitarray"
+	"github.com/tunabay/go-bmppath"
+)
+
+func ExamplePixelGrid() {
+	bmp := bitarray.NewBufferFromBitArray(bitarray.MustParse("01101"))
+	path, err := bmppath.New(bmp, 5)
+	if err != nil {
+		panic(err)
+	}
+
+	// the area cropped at (120, 80) of a 300dpi scan
+	grid := &bmppath.PixelGrid{
+		PixelWidth:  25.4 / 300,
+		PixelHeight: 25.4 / 300,
+		OffsetX:     120 * 25.4 / 300,
+		OffsetY:     80 * 25.4 / 300,
+		Unit:        "mm",
+	}
+	opts := &bmppath.SVGOptions{Format: bmppath.FormatCoord(3, ""), Grid: grid}
+	if err := path.WriteSVGOptions(os.Stdout, opts); err != nil {
+		panic(err)
+	}
+
+	// Output:
+	// <?xml version="1.0" encoding="utf-8"?>
+	// <svg version="1.1" xmlns="http://www.w3.org/2000/svg" viewBox="0 0 5 1" data-raster-width="5" data-raster-height="1" data-pixel-width="0.08466666666666667" data-pixel-height="0.08466666666666667" data-offset-x="10.16" data-offset-y="6.773333333333333" data-unit="mm">
+	// <path fill="#fff" d="m0,0h5v1h-5z"/><path d="m1,0h2v1h-2zm3,0h1v1h-1z"/>
+	// </svg>
+}
+
+func TestPath_WriteGeoJSON_grid(t *testing.T) {
+	bmp := bitarray.NewBufferFromBitArray(bitarray.MustParse("10010000"))
+	path, err := bmppath.New(bmp, 4)
+	if err != nil {
+		t.Fatalf("New(): %v", err)
+	}
+	var sb strings.Builder
+	opts := &bmppath.GeoOptions{Grid: &bmppath.PixelGrid{PixelWidth: 30, PixelHeight: 30, Unit: `m "utm"`}}
+	if err := path.WriteGeoJSON(&sb, opts); err != nil {
+		t.Fatalf("WriteGeoJSON(): %v", err)
+	}
+	var feature struct {
+		Type       string
+		Properties map[string]interface{}
+		Geometry   struct{ Type string }
+	}
+	if err := json.Unmarshal([]byte(sb.String()), &feature); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, sb.String())
+	}
+	if feature.Type != "Feature" || feature.Geometry.Type != "MultiPolygon" {
+		t.Errorf("unexpected feature: %s", sb.String())
+	}
+	for k, want := range map[string]interface{}{
+		"rasterWidth":  4.0,
+		"rasterHeight": 2.0,
+		"pixelWidth":   30.0,
+		"pixelHeight":  30.0,
+		"offsetX":      0.0,
+		"offsetY":      0.0,
+		"unit":         `m "utm"`,
+	} {
+		if got := feature.Properties[k]; got != want {
+			t.Errorf("%s: got %v, want %v", k, got, want)
+		}
+	}
+}
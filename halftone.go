@@ -0,0 +1,136 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strings"
+)
+
+// HalftoneOptions represents the options for Halftone.
+type HalftoneOptions struct {
+	// MaxArea is the maximum area of a dot in pixels. The larger clusters,
+	// such as the dots merged with each other in the dark areas, are kept
+	// as they are. Zero means the default value 64.
+	MaxArea int
+
+	// MinRoundness is the minimum roundness of a dot, the ratio of the area
+	// of the cluster to the area of its enclosing circle. The clusters less
+	// round than it, such as the thin lines, are kept as they are. Zero
+	// means the default value 0.5.
+	MinRoundness float64
+}
+
+// Halftone is the result of Halftone, a set of paths with the halftone dots
+// replaced by the circles.
+type Halftone struct {
+	Width, Height int
+
+	// Dots is the circles replacing the detected dots.
+	Dots []Circle
+
+	// Rest is the closed paths that are not detected as dots.
+	Rest *Path
+}
+
+// Halftone detects the halftone dots in p, and replaces each of them with a
+// circle of the same area centered at its centroid. A dot is an outline without
+// holes that is small and round enough as specified by opts. This turns the
+// thousands of jagged blobs of a scanned halftone image into clean vector
+// dots. nil opts is the same as the zero value of HalftoneOptions. p itself is
+// not modified.
+func (p *Path) Halftone(opts *HalftoneOptions) *Halftone {
+	if opts == nil {
+		opts = &HalftoneOptions{}
+	}
+	maxArea, minRound := opts.MaxArea, opts.MinRoundness
+	if maxArea == 0 {
+		maxArea = 64
+	}
+	if minRound == 0 {
+		minRound = 0.5
+	}
+	ret := &Halftone{
+		Width:  p.Width,
+		Height: p.Height,
+		Rest:   &Path{Width: p.Width, Height: p.Height},
+	}
+	for _, g := range p.groups() {
+		vs := p.Vertices[g[0]]
+		if len(g) == 1 {
+			if a := signedArea(vs); 0 < a && a <= int64(maxArea) {
+				ec := enclosingCircle(convexHull(floatVertices(vs)))
+				if minRound <= float64(a)/(math.Pi*ec.Radius*ec.Radius) {
+					ret.Dots = append(ret.Dots, Circle{
+						Center: centroid(vs),
+						Radius: math.Sqrt(float64(a) / math.Pi),
+					})
+					continue
+				}
+			}
+		}
+		for _, i := range g {
+			ret.Rest.Vertices = append(ret.Rest.Vertices, p.Vertices[i])
+			if p.Labels != nil {
+				ret.Rest.Labels = append(ret.Rest.Labels, p.PathLabel(i))
+			}
+		}
+	}
+	return ret
+}
+
+// centroid returns the centroid of the area enclosed by the closed path vs.
+func centroid(vs []Vertex) FloatVertex {
+	var a, cx, cy float64
+	for i, v0 := range vs {
+		v1 := vs[(i+1)%len(vs)]
+		cross := float64(v0[0])*float64(v1[1]) - float64(v1[0])*float64(v0[1])
+		a += cross
+		cx += float64(v0[0]+v1[0]) * cross
+		cy += float64(v0[1]+v1[1]) * cross
+	}
+	if a == 0 {
+		return FloatVertex{float64(vs[0][0]), float64(vs[0][1])}
+	}
+	return FloatVertex{cx / (3 * a), cy / (3 * a)}
+}
+
+// WriteSVG writes the halftone as an SVG document, in the same manner as
+// Path.WriteSVG, with the dots as the <circle> elements. The coordinates and
+// the radii of the circles are rounded to 3 decimal places.
+func (h *Halftone) WriteSVG(w io.Writer) error {
+	f := FormatCoord(3, "")
+	var sb strings.Builder
+	fmt.Fprintln(&sb, `<?xml version="1.0" encoding="utf-8"?>`)
+	fmt.Fprint(&sb, `<svg version="1.1" xmlns="http://www.w3.org/2000/svg"`)
+	fmt.Fprintf(&sb, ` viewBox="0 0 %d %d">`, h.Width, h.Height)
+	fmt.Fprintln(&sb)
+	fmt.Fprintf(&sb, `<path fill="#fff" d="m0,0h%dv%dh-%dz"/>`, h.Width, h.Height, h.Width)
+	fmt.Fprintln(&sb)
+	if _, err := io.WriteString(w, sb.String()); err != nil {
+		return fmt.Errorf("write failure: %w", err)
+	}
+	sb.Reset()
+	if h.Rest != nil && len(h.Rest.Vertices) != 0 {
+		if _, err := io.WriteString(w, `<path d="`); err != nil {
+			return fmt.Errorf("write failure: %w", err)
+		}
+		if err := h.Rest.WriteSVGD(w); err != nil {
+			return err
+		}
+		fmt.Fprintln(&sb, `"/>`)
+	}
+	for _, c := range h.Dots {
+		fmt.Fprintf(&sb, `<circle cx="%s" cy="%s" r="%s"/>`, f(c.Center[0]), f(c.Center[1]), f(c.Radius))
+		fmt.Fprintln(&sb)
+	}
+	fmt.Fprintln(&sb, `</svg>`)
+	if _, err := io.WriteString(w, sb.String()); err != nil {
+		return fmt.Errorf("write failure: %w", err)
+	}
+	return nil
+}
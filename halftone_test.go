@@ -0,0 +1,52 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath_test
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/tunabay/go-bitarray"
+	"github.com/tunabay/go-bmppath"
+)
+
+func ExamplePath_Halftone() {
+	bmp := bitarray.NewBufferFromBitArray(
+		bitarray.MustParse(strings.Join([]string{
+			"0110000",
+			"1111010",
+			"1111000",
+			"0110000",
+			"0000000",
+			"1111111",
+		}, "")),
+	)
+	path, err := bmppath.New(bmp, 7)
+	if err != nil {
+		panic(err)
+	}
+
+	ht := path.Halftone(nil)
+	for _, dot := range ht.Dots {
+		fmt.Printf("%v r=%.3f\n", dot.Center, dot.Radius)
+	}
+	fmt.Println("rest:", ht.Rest.NumPath())
+	if err := ht.WriteSVG(os.Stdout); err != nil {
+		panic(err)
+	}
+
+	// Output:
+	// (2, 2) r=1.954
+	// (5.5, 1.5) r=0.564
+	// rest: 1
+	// <?xml version="1.0" encoding="utf-8"?>
+	// <svg version="1.1" xmlns="http://www.w3.org/2000/svg" viewBox="0 0 7 6">
+	// <path fill="#fff" d="m0,0h7v6h-7z"/>
+	// <path d="m0,5h7v1h-7z"/>
+	// <circle cx="2" cy="2" r="1.954"/>
+	// <circle cx="5.5" cy="1.5" r="0.564"/>
+	// </svg>
+}
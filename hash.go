@@ -0,0 +1,37 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+)
+
+// Hash returns the 128-bit FNV-1a digest of the geometry of p, which can be
+// used as the key to cache the results of expensive processing of the traced
+// shapes. It is computed from the canonical form used by Equal, so that the
+// Paths equal in the sense of Equal always have the same digest, regardless of
+// the order of the closed paths, their starting vertices, and the labels. The
+// digest does not depend on the platform or the process.
+func (p *Path) Hash() [16]byte {
+	h := fnv.New128a()
+	var b [8]byte
+	put := func(n int) {
+		binary.BigEndian.PutUint64(b[:], uint64(int64(n)))
+		_, _ = h.Write(b[:])
+	}
+	put(p.Width)
+	put(p.Height)
+	for _, vs := range p.canonical() {
+		put(len(vs))
+		for _, v := range vs {
+			put(v[0])
+			put(v[1])
+		}
+	}
+	var ret [16]byte
+	copy(ret[:], h.Sum(nil))
+	return ret
+}
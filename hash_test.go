@@ -0,0 +1,41 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/tunabay/go-bitarray"
+	"github.com/tunabay/go-bmppath"
+)
+
+func TestPath_Hash(t *testing.T) {
+	bmp := bitarray.NewBufferFromBitArray(
+		bitarray.MustParse(strings.Join([]string{
+			"1101",
+			"1001",
+			"0011",
+		}, "")),
+	)
+	path, err := bmppath.New(bmp, 4)
+	if err != nil {
+		t.Fatalf("New(): %v", err)
+	}
+	h := path.Hash()
+
+	reordered := path.Reorder(bmppath.OrderTwoOpt, true)
+	reordered.Vertices[0], reordered.Vertices[1] = reordered.Vertices[1], reordered.Vertices[0]
+	reordered.Labels = []string{"a", "b"}
+	if got := reordered.Hash(); got != h {
+		t.Errorf("digest changed by reordering: %x != %x", got, h)
+	}
+	if got := path.Reverse().Hash(); got == h {
+		t.Errorf("digest not changed by reversing: %x", got)
+	}
+	if got := path.Translate(1, 0).Hash(); got == h {
+		t.Errorf("digest not changed by translation: %x", got)
+	}
+}
@@ -0,0 +1,131 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath
+
+// Node is a node of the containment hierarchy of the closed paths, returned by
+// Hierarchy.
+type Node struct {
+	// Index is the index of the closed path in Path.Vertices, or -1 for the
+	// root node representing the whole canvas.
+	Index int
+
+	// Children are the nodes of the closed paths directly inside this one.
+	Children []*Node
+}
+
+// Hierarchy returns the containment hierarchy of the closed paths as a tree.
+// The root node represents the whole canvas, and each of the other nodes
+// represents a closed path, with the closed paths directly inside it as its
+// children. For example, the outline of the glyph "o" has its counter, the
+// hole, as a child, and a dot of ink inside the counter would be a child of
+// the hole. The children are in the same order as p.Vertices.
+func (p *Path) Hierarchy() *Node {
+	nodes := make([]*Node, len(p.Vertices))
+	for i := range nodes {
+		nodes[i] = &Node{Index: i}
+	}
+	root := &Node{Index: -1}
+	for i, parent := range p.parents() {
+		if parent == -1 {
+			root.Children = append(root.Children, nodes[i])
+		} else {
+			nodes[parent].Children = append(nodes[parent].Children, nodes[i])
+		}
+	}
+	return root
+}
+
+// Depth returns the nesting depth of the closed path specified by the index n,
+// that is, the number of the other closed paths containing it. The outermost
+// closed paths have the depth 0. n must be less than p.NumPath() otherwise it
+// panics. Since this examines all the closed paths, use Hierarchy instead to
+// query many closed paths.
+func (p *Path) Depth(n int) int {
+	areas := make([]int64, len(p.Vertices))
+	for i, vs := range p.Vertices {
+		areas[i] = abs64(signedArea(vs))
+	}
+	d := 0
+	for i, vs := range p.Vertices {
+		if i != n && areas[n] < areas[i] && containsPoint(vs, p.Vertices[n][0]) {
+			d++
+		}
+	}
+	return d
+}
+
+// IsHole reports whether the closed path specified by the index n is a hole,
+// determined by the parity of its nesting depth. n must be less than
+// p.NumPath() otherwise it panics.
+func (p *Path) IsHole(n int) bool { return p.Depth(n)%2 == 1 }
+
+// parents returns the index of the closed path directly containing each closed
+// path, or -1 if it is not inside any other closed path.
+func (p *Path) parents() []int {
+	areas := make([]int64, len(p.Vertices))
+	for i, vs := range p.Vertices {
+		areas[i] = abs64(signedArea(vs))
+	}
+	ret := make([]int, len(p.Vertices))
+	for i, vs := range p.Vertices {
+		ret[i] = -1
+		for j, cvs := range p.Vertices {
+			if i == j || areas[j] <= areas[i] || !containsPoint(cvs, vs[0]) {
+				continue
+			}
+			if ret[i] == -1 || areas[j] < areas[ret[i]] {
+				ret[i] = j
+			}
+		}
+	}
+	return ret
+}
+
+// groups returns the indexes of the closed paths grouped into the regions, each
+// of which consists of an outline followed by the holes directly inside it.
+// The holes that are not inside any outline form groups by themselves.
+func (p *Path) groups() [][]int {
+	parents := p.parents()
+	var ret [][]int
+	gidx := make(map[int]int)
+	for i, vs := range p.Vertices {
+		if 0 <= signedArea(vs) {
+			gidx[i] = len(ret)
+			ret = append(ret, []int{i})
+		}
+	}
+	for i, vs := range p.Vertices {
+		if 0 <= signedArea(vs) {
+			continue
+		}
+		if g, ok := gidx[parents[i]]; ok {
+			ret[g] = append(ret[g], i)
+			continue
+		}
+		ret = append(ret, []int{i})
+	}
+	return ret
+}
+
+// containsPoint reports whether the point pt is inside the closed path vs, by
+// counting the vertical edges crossed by the ray cast from pt toward +x. pt is
+// assumed not to be on the closed path.
+func containsPoint(vs []Vertex, pt Vertex) bool {
+	in := false
+	for i, v0 := range vs {
+		v1 := vs[(i+1)%len(vs)]
+		if v0[0] != v1[0] || v0[0] <= pt[0] {
+			continue
+		}
+		y0, y1 := v0[1], v1[1]
+		if y1 < y0 {
+			y0, y1 = y1, y0
+		}
+		if y0 <= pt[1] && pt[1] < y1 {
+			in = !in
+		}
+	}
+	return in
+}
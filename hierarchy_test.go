@@ -0,0 +1,70 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath_test
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/tunabay/go-bitarray"
+	"github.com/tunabay/go-bmppath"
+)
+
+func ExamplePath_Hierarchy() {
+	bmp := bitarray.NewBufferFromBitArray(
+		bitarray.MustParse(strings.Join([]string{
+			"11111001",
+			"10001000",
+			"10101011",
+			"10001011",
+			"11111000",
+		}, "")),
+	)
+	path, err := bmppath.New(bmp, 8)
+	if err != nil {
+		panic(err)
+	}
+
+	var dump func(n *bmppath.Node, indent string)
+	dump = func(n *bmppath.Node, indent string) {
+		for _, c := range n.Children {
+			fmt.Printf("%s%d: %s\n", indent, c.Index, path.PathString(c.Index))
+			dump(c, indent+"  ")
+		}
+	}
+	dump(path.Hierarchy(), "")
+
+	// Output:
+	// 0: (0, 0), (5, 0), (5, 5), (0, 5)
+	//   1: (1, 1), (1, 4), (4, 4), (4, 1)
+	//     2: (2, 2), (3, 2), (3, 3), (2, 3)
+	// 3: (6, 2), (8, 2), (8, 4), (6, 4)
+	// 4: (7, 0), (8, 0), (8, 1), (7, 1)
+}
+
+func ExamplePath_IsHole() {
+	bmp := bitarray.NewBufferFromBitArray(
+		bitarray.MustParse(strings.Join([]string{
+			"11111",
+			"10001",
+			"10101",
+			"10001",
+			"11111",
+		}, "")),
+	)
+	path, err := bmppath.New(bmp, 5)
+	if err != nil {
+		panic(err)
+	}
+
+	for i := 0; i < path.NumPath(); i++ {
+		fmt.Printf("%d: depth=%d, hole=%t\n", i, path.Depth(i), path.IsHole(i))
+	}
+
+	// Output:
+	// 0: depth=0, hole=false
+	// 1: depth=1, hole=true
+	// 2: depth=2, hole=false
+}
@@ -0,0 +1,74 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strings"
+)
+
+// HPGLOptions represents the options for WriteHPGL.
+type HPGLOptions struct {
+	// Pen is the number of the pen selected with SP. Zero means the default
+	// value 1.
+	Pen int
+
+	// Scale is the number of the plotter units per pixel. The coordinates
+	// are rounded to the nearest plotter unit. Since the plotter unit is
+	// 0.025mm, 40 draws one pixel as 1mm. Zero means the default value 40.
+	Scale float64
+}
+
+// WriteHPGL writes the closed paths as an HP-GL program for the pen plotters.
+// The pen moves to the start of each closed path with PU, and then draws along
+// its vertices with PD back to the start, in the absolute coordinates set by
+// PA. Since the y-axis of HP-GL points upward, the paths are flipped vertically
+// in the same manner as WriteDXF. nil opts is the same as the zero value of
+// HPGLOptions.
+func (p *Path) WriteHPGL(w io.Writer, opts *HPGLOptions) error {
+	if opts == nil {
+		opts = &HPGLOptions{}
+	}
+	pen := opts.Pen
+	if pen == 0 {
+		pen = 1
+	}
+	scale := opts.Scale
+	if scale == 0 {
+		scale = 40
+	}
+	xy := func(v Vertex) string {
+		x := math.Round(float64(v[0]) * scale)
+		y := math.Round(float64(p.Height-v[1]) * scale)
+		return fmt.Sprintf("%.0f,%.0f", x, y)
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "IN;SP%d;PA;\n", pen)
+	for _, vs := range p.Vertices {
+		if len(vs) == 0 {
+			continue
+		}
+		fmt.Fprintf(&sb, "PU%s;PD", xy(vs[0]))
+		for i := range vs {
+			if i != 0 {
+				sb.WriteString(",")
+			}
+			sb.WriteString(xy(vs[(i+1)%len(vs)]))
+		}
+		sb.WriteString(";\n")
+		if _, err := io.WriteString(w, sb.String()); err != nil {
+			return fmt.Errorf("write failure: %w", err)
+		}
+		sb.Reset()
+	}
+	sb.WriteString("PU;SP0;\n")
+	if _, err := io.WriteString(w, sb.String()); err != nil {
+		return fmt.Errorf("write failure: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,32 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath_test
+
+import (
+	"os"
+
+	"github.com/tunabay/go-bitarray"
+	"github.com/tunabay/go-bmppath"
+)
+
+func ExamplePath_WriteHPGL() {
+	bmp := bitarray.NewBufferFromBitArray(bitarray.MustParse("01101"))
+	path, err := bmppath.New(bmp, 5)
+	if err != nil {
+		panic(err)
+	}
+
+	// pen 2, and 1 pixel as 0.5mm
+	opts := &bmppath.HPGLOptions{Pen: 2, Scale: 20}
+	if err := path.WriteHPGL(os.Stdout, opts); err != nil {
+		panic(err)
+	}
+
+	// Output:
+	// IN;SP2;PA;
+	// PU20,20;PD60,20,60,0,20,0,20,20;
+	// PU80,20;PD100,20,100,0,80,0,80,20;
+	// PU;SP0;
+}
@@ -0,0 +1,185 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"sort"
+	"strings"
+)
+
+// HTMLPreviewOptions represents the options for WriteHTMLPreview.
+type HTMLPreviewOptions struct {
+	// Title is the title of the document. If empty, "bmppath preview" is
+	// used.
+	Title string
+
+	// Grid shows the pixel grid initially. It can be toggled in the
+	// document anyway.
+	Grid bool
+}
+
+// WriteHTMLPreview writes a standalone HTML document to inspect the vectorized
+// bitmap image in the web browsers, without any external resources. The
+// document embeds the SVG image, which can be zoomed with the mouse wheel or
+// the buttons and panned by dragging, with a checkbox to toggle the pixel grid.
+// Each closed path is drawn as its own element, larger ones first, so that the
+// closed path under the pointer is highlighted and its index, label, number of
+// vertices, and area are shown. nil opts is the same as the zero value of
+// HTMLPreviewOptions.
+func (p *Path) WriteHTMLPreview(w io.Writer, opts *HTMLPreviewOptions) error {
+	if opts == nil {
+		opts = &HTMLPreviewOptions{}
+	}
+	title := opts.Title
+	if title == "" {
+		title = "bmppath preview"
+	}
+	areas := make([]int64, len(p.Vertices))
+	order := make([]int, 0, len(p.Vertices))
+	for i, vs := range p.Vertices {
+		if len(vs) != 0 {
+			areas[i] = signedArea(vs)
+			order = append(order, i)
+		}
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return abs64(areas[order[j]]) < abs64(areas[order[i]])
+	})
+
+	var sb strings.Builder
+	sb.WriteString("<!DOCTYPE html>\n")
+	sb.WriteString("<html>\n<head>\n<meta charset=\"utf-8\">\n")
+	fmt.Fprintf(&sb, "<title>%s</title>\n", html.EscapeString(title))
+	sb.WriteString(htmlPreviewStyle)
+	sb.WriteString("</head>\n<body>\n")
+	sb.WriteString(`<div id="bar"><button id="zoom-in">+</button><button id="zoom-out">&minus;</button><button id="reset">Reset</button>`)
+	checked := ""
+	if opts.Grid {
+		checked = " checked"
+	}
+	fmt.Fprintf(&sb, `<label><input type="checkbox" id="grid-toggle"%s> Pixel grid</label>`, checked)
+	fmt.Fprintf(&sb, "<span id=\"info\">%dx%d, %d closed paths</span></div>\n", p.Width, p.Height, len(p.Vertices))
+	fmt.Fprintf(&sb, `<svg id="view" xmlns="http://www.w3.org/2000/svg" viewBox="0 0 %d %d">`+"\n", p.Width, p.Height)
+	fmt.Fprintf(&sb, `<rect width="%d" height="%d" fill="#fff"/>`+"\n", p.Width, p.Height)
+	for _, n := range order {
+		vs := p.Vertices[n]
+		class := "contour"
+		if areas[n] < 0 {
+			class += " hole"
+		}
+		label := p.PathLabel(n)
+		fmt.Fprintf(&sb, `<path class="%s" data-index="%d" data-label="%s" data-vertices="%d" data-area="%d" d="`, class, n, html.EscapeString(label), len(vs), abs64(areas[n]))
+		_ = pathSVGD(&sb, vs, Vertex{})
+		fmt.Fprintf(&sb, `"><title>#%d`, n)
+		if label != "" {
+			sb.WriteString(" " + html.EscapeString(label))
+		}
+		sb.WriteString("</title></path>\n")
+	}
+	sb.WriteString(`<path id="grid" d="`)
+	for x := 0; x <= p.Width; x++ {
+		fmt.Fprintf(&sb, "M%d,0v%d", x, p.Height)
+	}
+	for y := 0; y <= p.Height; y++ {
+		fmt.Fprintf(&sb, "M0,%dh%d", y, p.Width)
+	}
+	sb.WriteString("\"/>\n</svg>\n")
+	sb.WriteString(htmlPreviewScript)
+	sb.WriteString("</body>\n</html>\n")
+	if _, err := io.WriteString(w, sb.String()); err != nil {
+		return fmt.Errorf("write failure: %w", err)
+	}
+	return nil
+}
+
+const htmlPreviewStyle = `<style>
+html, body { margin: 0; height: 100%; font: 14px sans-serif; }
+body { display: flex; flex-direction: column; }
+#bar { display: flex; gap: 8px; align-items: center; padding: 6px; background: #eee; }
+#info { margin-left: auto; }
+#view { flex: 1; background: #888; cursor: grab; touch-action: none; }
+#view.dragging { cursor: grabbing; }
+.contour { fill: #000; }
+.contour.hole { fill: #fff; }
+.contour:hover { fill: #d40; }
+.contour.hole:hover { fill: #fb9; }
+#grid { fill: none; stroke: #08f; stroke-opacity: 0.4; stroke-width: 1; vector-effect: non-scaling-stroke; pointer-events: none; }
+#grid.hidden { display: none; }
+</style>
+`
+
+const htmlPreviewScript = `<script>
+(function () {
+	const svg = document.getElementById("view");
+	const grid = document.getElementById("grid");
+	const info = document.getElementById("info");
+	const toggle = document.getElementById("grid-toggle");
+	const initial = svg.getAttribute("viewBox").split(" ").map(Number);
+	const summary = info.textContent;
+	let vb = initial.slice();
+	const update = () => svg.setAttribute("viewBox", vb.join(" "));
+	const toUser = (e) => {
+		const pt = svg.createSVGPoint();
+		pt.x = e.clientX;
+		pt.y = e.clientY;
+		return pt.matrixTransform(svg.getScreenCTM().inverse());
+	};
+	const zoom = (k, cx, cy) => {
+		vb = [cx - (cx - vb[0]) * k, cy - (cy - vb[1]) * k, vb[2] * k, vb[3] * k];
+		update();
+	};
+	const center = () => [vb[0] + vb[2] / 2, vb[1] + vb[3] / 2];
+	svg.addEventListener("wheel", (e) => {
+		e.preventDefault();
+		const pt = toUser(e);
+		zoom(e.deltaY < 0 ? 1 / 1.2 : 1.2, pt.x, pt.y);
+	}, { passive: false });
+	let last = null;
+	svg.addEventListener("pointerdown", (e) => {
+		last = toUser(e);
+		svg.setPointerCapture(e.pointerId);
+		svg.classList.add("dragging");
+	});
+	svg.addEventListener("pointermove", (e) => {
+		if (last === null) {
+			return;
+		}
+		const pt = toUser(e);
+		vb[0] -= pt.x - last.x;
+		vb[1] -= pt.y - last.y;
+		update();
+		last = toUser(e);
+	});
+	const release = () => {
+		last = null;
+		svg.classList.remove("dragging");
+	};
+	svg.addEventListener("pointerup", release);
+	svg.addEventListener("pointercancel", release);
+	document.getElementById("zoom-in").addEventListener("click", () => zoom(1 / 1.5, ...center()));
+	document.getElementById("zoom-out").addEventListener("click", () => zoom(1.5, ...center()));
+	document.getElementById("reset").addEventListener("click", () => {
+		vb = initial.slice();
+		update();
+	});
+	const showGrid = () => grid.classList.toggle("hidden", !toggle.checked);
+	toggle.addEventListener("change", showGrid);
+	showGrid();
+	svg.addEventListener("mouseover", (e) => {
+		const d = e.target.dataset;
+		if (d.index === undefined) {
+			return;
+		}
+		info.textContent = "#" + d.index + (d.label ? " " + d.label : "") +
+			(e.target.classList.contains("hole") ? " (hole)" : "") +
+			": " + d.vertices + " vertices, area " + d.area;
+	});
+	svg.addEventListener("mouseout", () => { info.textContent = summary; });
+})();
+</script>
+`
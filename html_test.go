@@ -0,0 +1,87 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath_test
+
+import (
+	"encoding/xml"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/tunabay/go-bitarray"
+	"github.com/tunabay/go-bmppath"
+)
+
+func TestPath_WriteHTMLPreview(t *testing.T) {
+	bmp := bitarray.NewBufferFromBitArray(
+		bitarray.MustParse(strings.Join([]string{
+			"11101",
+			"10101",
+			"11100",
+		}, "")),
+	)
+	path, err := bmppath.New(bmp, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path.Labels = []string{"<ring>"}
+
+	var sb strings.Builder
+	opts := &bmppath.HTMLPreviewOptions{Title: "a & b"}
+	if err := path.WriteHTMLPreview(&sb, opts); err != nil {
+		t.Fatal(err)
+	}
+	doc := sb.String()
+	if !strings.HasPrefix(doc, "<!DOCTYPE html>\n") {
+		t.Errorf("unexpected head: %q", doc[:20])
+	}
+	if !strings.Contains(doc, "<title>a &amp; b</title>") {
+		t.Error("title not escaped")
+	}
+	if strings.Contains(doc, `id="grid-toggle" checked`) {
+		t.Error("grid shown by default")
+	}
+
+	// the embedded SVG is well-formed, and has each closed path, larger
+	// ones first
+	i, j := strings.Index(doc, "<svg"), strings.Index(doc, "</svg>")
+	if i < 0 || j < i {
+		t.Fatal("svg not found")
+	}
+	dec := xml.NewDecoder(strings.NewReader(doc[i : j+len("</svg>")]))
+	var got []string
+	for {
+		tok, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("malformed svg: %v", err)
+		}
+		se, ok := tok.(xml.StartElement)
+		if !ok || se.Name.Local != "path" {
+			continue
+		}
+		var class, index, label string
+		for _, a := range se.Attr {
+			switch a.Name.Local {
+			case "class":
+				class = a.Value
+			case "data-index":
+				index = a.Value
+			case "data-label":
+				label = a.Value
+			}
+		}
+		if class != "" {
+			got = append(got, index+" "+class+" "+label)
+		}
+	}
+	want := []string{"0 contour <ring>", "2 contour ", "1 contour hole "}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("unexpected contours: got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,98 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strings"
+)
+
+// FrameShape specifies the shape of the frame drawn by WriteIconSVG.
+type FrameShape int
+
+const (
+	// FrameRoundRect is a square with rounded corners.
+	FrameRoundRect FrameShape = iota
+
+	// FrameCircle is a circle.
+	FrameCircle
+)
+
+// IconFrame represents the frame, or the badge, in which WriteIconSVG places
+// the traced image.
+type IconFrame struct {
+	// Shape is the shape of the frame.
+	Shape FrameShape
+
+	// Size is the width and height of the resulting document. If zero, 100
+	// is used.
+	Size float64
+
+	// Radius is the corner radius of FrameRoundRect.
+	Radius float64
+
+	// Padding is the minimum space between the frame and the image. For
+	// FrameCircle, the image is placed in the square inscribed in the circle
+	// shrunk by Padding.
+	Padding float64
+
+	// Fill and Color are the fill colors of the frame and the image. If
+	// empty, "#000" and "#fff" are used respectively.
+	Fill, Color string
+}
+
+// WriteIconSVG writes an SVG document in which the vectorized image is placed
+// inside the frame f. The image is trimmed to the bounding box of its vertices,
+// uniformly scaled to fit in the frame with the padding, and centered. This
+// automates the common composition of an icon in a badge. nil f is the same as
+// the zero value of IconFrame, a black square with white image.
+func (p *Path) WriteIconSVG(w io.Writer, f *IconFrame) error {
+	if f == nil {
+		f = &IconFrame{}
+	}
+	size, fill, color := f.Size, f.Fill, f.Color
+	if size == 0 {
+		size = 100
+	}
+	if fill == "" {
+		fill = "#000"
+	}
+	if color == "" {
+		color = "#fff"
+	}
+	fill, color = escapeAttr(fill), escapeAttr(color)
+	var sb strings.Builder
+	fmt.Fprintln(&sb, `<?xml version="1.0" encoding="utf-8"?>`)
+	fmt.Fprint(&sb, `<svg version="1.1" xmlns="http://www.w3.org/2000/svg"`)
+	fmt.Fprintf(&sb, ` viewBox="0 0 %g %g">`, size, size)
+	fmt.Fprintln(&sb)
+	inner := size - 2*f.Padding
+	switch f.Shape {
+	case FrameCircle:
+		fmt.Fprintf(&sb, `<circle fill="%s" cx="%g" cy="%g" r="%g"/>`, fill, size/2, size/2, size/2)
+		inner /= math.Sqrt2
+	default:
+		fmt.Fprintf(&sb, `<rect fill="%s" width="%g" height="%g" rx="%g"/>`, fill, size, size, f.Radius)
+	}
+	if r, ok := vertexBounds(p.Vertices...); ok && 0 < inner {
+		w, h := float64(r.Dx()), float64(r.Dy())
+		s := math.Min(inner/w, inner/h)
+		t := Transform{
+			ScaleX:     s,
+			ScaleY:     s,
+			TranslateX: (size-w*s)/2 - float64(r.Min.X)*s,
+			TranslateY: (size-h*s)/2 - float64(r.Min.Y)*s,
+		}
+		fmt.Fprintf(&sb, `<path fill="%s" transform="%s" d="%s"/>`, color, t, p.SVGDString())
+	}
+	fmt.Fprintln(&sb)
+	fmt.Fprintln(&sb, `</svg>`)
+	if _, err := fmt.Fprint(w, sb.String()); err != nil {
+		return fmt.Errorf("write failure: %w", err)
+	}
+	return nil
+}
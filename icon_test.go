@@ -0,0 +1,61 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath_test
+
+import (
+	"encoding/xml"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/tunabay/go-bitarray"
+	"github.com/tunabay/go-bmppath"
+)
+
+func ExamplePath_WriteIconSVG() {
+	bmp := bitarray.NewBufferFromBitArray(
+		bitarray.MustParse(strings.Join([]string{
+			"0000",
+			"0110",
+			"0100",
+			"0000",
+		}, "")),
+	)
+	path, err := bmppath.New(bmp, 4)
+	if err != nil {
+		panic(err)
+	}
+
+	_ = path.WriteIconSVG(os.Stdout, &bmppath.IconFrame{
+		Shape:   bmppath.FrameRoundRect,
+		Size:    24,
+		Radius:  4,
+		Padding: 4,
+	})
+
+	// Output:
+	// <?xml version="1.0" encoding="utf-8"?>
+	// <svg version="1.1" xmlns="http://www.w3.org/2000/svg" viewBox="0 0 24 24">
+	// <rect fill="#000" width="24" height="24" rx="4"/><path fill="#fff" transform="translate(-4 -4) scale(8 8)" d="m1,1h2v1h-1v1h-1z"/>
+	// </svg>
+}
+
+func TestPath_WriteIconSVG_escape(t *testing.T) {
+	path := &bmppath.Path{Width: 1, Height: 1, Vertices: [][]bmppath.Vertex{{{0, 0}, {1, 0}, {1, 1}, {0, 1}}}}
+	var sb strings.Builder
+	if err := path.WriteIconSVG(&sb, &bmppath.IconFrame{
+		Shape: bmppath.FrameCircle,
+		Fill:  `red"/><script>alert(1)</script><x a="`,
+		Color: "<blue>",
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := xml.Unmarshal([]byte(sb.String()), new(struct{})); err != nil {
+		t.Errorf("broken SVG: %v\n%s", err, sb.String())
+	}
+	if strings.Contains(sb.String(), "<script>") || strings.Contains(sb.String(), "<blue>") {
+		t.Errorf("colors not escaped:\n%s", sb.String())
+	}
+}
@@ -0,0 +1,133 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+
+	"github.com/tunabay/go-bitarray"
+)
+
+// Binarization specifies the method to convert a grayscale image into a bitmap
+// image.
+type Binarization int
+
+const (
+	// BinarizeFixed makes the pixels darker than the fixed Threshold ink.
+	BinarizeFixed Binarization = iota
+
+	// BinarizeAreaPreserving chooses the threshold so that the total ink
+	// area matches the mean gray level of the image. For example, an image
+	// that is 30% dark on average results in a bitmap image with 30% of the
+	// pixels set to ink. This keeps the visual weight of the halftone-ish
+	// images.
+	BinarizeAreaPreserving
+)
+
+// NewFromImage creates a set of paths from the image img. img is converted to
+// grayscale, then binarized as specified by opts, where the dark pixels become
+// ink, i.e. 1 in the bitmap image passed to NewWithOptions. The binarized
+// image is then preprocessed and traced in the same way as NewWithOptions. nil
+// opts is the same as the zero value of Options.
+func NewFromImage(img image.Image, opts *Options) (*Path, error) {
+	if img == nil {
+		return nil, fmt.Errorf("%w: img == nil", ErrInvalidBitmap)
+	}
+	b := img.Bounds()
+	if b.Dx() < 1 || b.Dy() < 1 {
+		return nil, fmt.Errorf("%w: empty image: %v", ErrInvalidBitmap, b)
+	}
+	if opts == nil {
+		opts = &Options{}
+	}
+	return NewWithOptions(opts.binarize(opts.adjustLevels(toGray(img))), b.Dx(), opts)
+}
+
+// adjustLevels applies the input levels and gamma correction specified by opts
+// to the gray levels gray in place, and returns it.
+func (opts *Options) adjustLevels(gray []uint8) []uint8 {
+	black, white, gamma := int(opts.BlackPoint), int(opts.WhitePoint), opts.Gamma
+	if white == 0 {
+		white = 255
+	}
+	if gamma <= 0 {
+		gamma = 1
+	}
+	if black == 0 && white == 255 && gamma == 1 {
+		return gray
+	}
+	var lut [256]uint8
+	for i := range lut {
+		switch {
+		case i <= black:
+			lut[i] = 0
+		case white <= i:
+			lut[i] = 255
+		default:
+			v := math.Pow(float64(i-black)/float64(white-black), 1/gamma)
+			lut[i] = uint8(math.Round(v * 255))
+		}
+	}
+	for i, g := range gray {
+		gray[i] = lut[g]
+	}
+	return gray
+}
+
+// toGray returns the gray levels of the pixels of img in row-major order.
+func toGray(img image.Image) []uint8 {
+	b := img.Bounds()
+	ret := make([]uint8, 0, b.Dx()*b.Dy())
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			g, _ := color.GrayModel.Convert(img.At(x, y)).(color.Gray)
+			ret = append(ret, g.Y)
+		}
+	}
+	return ret
+}
+
+// binarize converts the gray levels gray into a bitmap image as specified by
+// opts.
+func (opts *Options) binarize(gray []uint8) *bitarray.Buffer {
+	th := int(opts.Threshold)
+	if th == 0 {
+		th = 128
+	}
+	if opts.Binarization == BinarizeAreaPreserving {
+		th = areaPreservingThreshold(gray)
+	}
+	bm := bitarray.NewBuffer(len(gray))
+	for i, g := range gray {
+		if int(g) < th {
+			bm.PutBitAt(i, 1)
+		}
+	}
+	return bm
+}
+
+// areaPreservingThreshold returns the threshold that makes the number of ink
+// pixels closest to the total darkness of gray.
+func areaPreservingThreshold(gray []uint8) int {
+	var hist [256]int
+	dark := 0
+	for _, g := range gray {
+		hist[g]++
+		dark += 255 - int(g)
+	}
+	target := (dark + 127) / 255
+	th, n := 0, 0
+	for ; th < 256; th++ {
+		next := n + hist[th]
+		if target-n <= next-target {
+			break
+		}
+		n = next
+	}
+	return th
+}
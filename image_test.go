@@ -0,0 +1,70 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath_test
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"testing"
+
+	"github.com/tunabay/go-bmppath"
+)
+
+func ExampleNewFromImage() {
+	img := image.NewGray(image.Rect(0, 0, 4, 2))
+	copy(img.Pix, []uint8{
+		0, 200, 220, 255,
+		240, 230, 180, 210,
+	})
+
+	for _, opts := range []*bmppath.Options{
+		nil,
+		{Threshold: 215},
+		{Binarization: bmppath.BinarizeAreaPreserving},
+	} {
+		path, err := bmppath.NewFromImage(img, opts)
+		if err != nil {
+			panic(err)
+		}
+		fmt.Printf("area=%d: %s\n", path.Area(), path.SVGDString())
+	}
+
+	// Output:
+	// area=1: m0,0h1v1h-1z
+	// area=4: m0,0h2v1h2v1h-2v-1h-2z
+	// area=2: m0,0h1v1h-1zm2,1h1v1h-1z
+}
+
+func TestNewFromImage_error(t *testing.T) {
+	if _, err := bmppath.NewFromImage(nil, nil); !errors.Is(err, bmppath.ErrInvalidBitmap) {
+		t.Errorf("nil: unexpected error: %v", err)
+	}
+	if _, err := bmppath.NewFromImage(image.NewGray(image.Rect(0, 0, 0, 5)), nil); !errors.Is(err, bmppath.ErrInvalidBitmap) {
+		t.Errorf("empty: unexpected error: %v", err)
+	}
+}
+
+func ExampleOptions_levels() {
+	img := image.NewGray(image.Rect(0, 0, 4, 1))
+	copy(img.Pix, []uint8{170, 190, 210, 250})
+
+	for _, opts := range []*bmppath.Options{
+		nil,
+		{BlackPoint: 160, WhitePoint: 240},
+		{Gamma: 0.2},
+	} {
+		path, err := bmppath.NewFromImage(img, opts)
+		if err != nil {
+			panic(err)
+		}
+		fmt.Printf("area=%d\n", path.Area())
+	}
+
+	// Output:
+	// area=0
+	// area=2
+	// area=3
+}
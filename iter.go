@@ -0,0 +1,38 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+//go:build go1.23
+
+package bmppath
+
+import (
+	"iter"
+)
+
+// Paths returns an iterator over the closed paths, yielding the index and the
+// vertices of each closed path. The yielded slices share the memory with
+// p.Vertices, so they must not be modified.
+func (p *Path) Paths() iter.Seq2[int, []Vertex] {
+	return func(yield func(int, []Vertex) bool) {
+		for i, vs := range p.Vertices {
+			if !yield(i, vs) {
+				return
+			}
+		}
+	}
+}
+
+// Segments returns an iterator over the edges of the closed path specified by
+// the index n, including the closing edge from the last vertex to the first
+// one. n must be less than p.NumPath() otherwise it panics.
+func (p *Path) Segments(n int) iter.Seq[Segment] {
+	vs := p.Vertices[n]
+	return func(yield func(Segment) bool) {
+		for i, v := range vs {
+			if !yield(Segment{A: v, B: vs[(i+1)%len(vs)]}) {
+				return
+			}
+		}
+	}
+}
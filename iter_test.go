@@ -0,0 +1,49 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+//go:build go1.23
+
+package bmppath_test
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/tunabay/go-bitarray"
+	"github.com/tunabay/go-bmppath"
+)
+
+func ExamplePath_Paths() {
+	bmp := bitarray.NewBufferFromBitArray(
+		bitarray.MustParse(strings.Join([]string{
+			"1101",
+			"1001",
+		}, "")),
+	)
+	path, err := bmppath.New(bmp, 4)
+	if err != nil {
+		panic(err)
+	}
+
+	for i, vs := range path.Paths() {
+		fmt.Printf("%d: %d vertices\n", i, len(vs))
+		for s := range path.Segments(i) {
+			fmt.Println("  ", s)
+		}
+	}
+
+	// Output:
+	// 0: 6 vertices
+	//    (0, 0)-(2, 0)
+	//    (2, 0)-(2, 1)
+	//    (2, 1)-(1, 1)
+	//    (1, 1)-(1, 2)
+	//    (1, 2)-(0, 2)
+	//    (0, 2)-(0, 0)
+	// 1: 4 vertices
+	//    (3, 0)-(4, 0)
+	//    (4, 0)-(4, 2)
+	//    (4, 2)-(3, 2)
+	//    (3, 2)-(3, 0)
+}
@@ -0,0 +1,103 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath
+
+import (
+	"fmt"
+	"image"
+)
+
+// Job is a piece of a Path split by SplitJobs to fit the work area of a
+// machine.
+type Job struct {
+	// Col and Row are the position of the piece in the grid of the pieces.
+	Col, Row int
+
+	// Bounds is the area of the original canvas covered by the piece.
+	Bounds image.Rectangle
+
+	// Path is the piece on the canvas of the size of the work area, with the
+	// area Bounds placed at the margin from the upper left corner, and with
+	// the registration marks around it.
+	Path *Path
+}
+
+// SplitJobs splits p into the pieces fitting the work area of the size width x
+// height, for plotting or cutting the artwork larger than the bed of the
+// machine. Each piece covers the area of (width - 2*margin) x (height -
+// 2*margin) of p, surrounded by the margin of the work area. The registration
+// marks are drawn in the margin, as the L-shaped marks at the four corners of
+// the covered area with their edges on the boundary lines of it, so that the
+// pieces can be trimmed and aligned with each other. The marks are omitted if
+// margin is less than 2. The pieces without any closed path are also omitted.
+// p itself is not modified.
+func (p *Path) SplitJobs(width, height, margin int) ([]*Job, error) {
+	cw, ch := width-margin*2, height-margin*2
+	if margin < 0 || cw < 1 || ch < 1 {
+		return nil, fmt.Errorf("%w: work area %dx%d with margin %d", ErrInvalidWidth, width, height, margin)
+	}
+	var ret []*Job
+	for row := 0; row*ch < p.Height; row++ {
+		for col := 0; col*cw < p.Width; col++ {
+			r := image.Rect(col*cw, row*ch, (col+1)*cw, (row+1)*ch)
+			r = r.Intersect(image.Rect(0, 0, p.Width, p.Height))
+			piece := p.KeepRect(r)
+			if piece.NumPath() == 0 {
+				continue
+			}
+			piece = piece.Translate(margin-r.Min.X, margin-r.Min.Y)
+			piece.Width, piece.Height = width, height
+			if 2 <= margin {
+				piece.Append(registrationMarks(width, height, margin, r.Size()), 0, 0)
+			}
+			ret = append(ret, &Job{Col: col, Row: row, Bounds: r, Path: piece})
+		}
+	}
+	return ret, nil
+}
+
+// registrationMarks returns the L-shaped marks at the corners of the area of
+// the size sz placed at (margin, margin) on the canvas of the size width x
+// height.
+func registrationMarks(width, height, margin int, sz image.Point) *Path {
+	gap := margin / 4
+	if gap < 1 {
+		gap = 1
+	}
+	ret := &Path{Width: width, Height: height}
+	rect := func(x0, y0, x1, y1 int) {
+		ret.Vertices = append(ret.Vertices, []Vertex{{x0, y0}, {x1, y0}, {x1, y1}, {x0, y1}})
+	}
+	// span returns the range of the arm from c in the direction s, and edge
+	// returns the range of the width 1 next to c in the direction s.
+	span := func(c, s int) (int, int) {
+		if s < 0 {
+			return c - margin, c - gap
+		}
+		return c + gap, c + margin
+	}
+	edge := func(c, s int) (int, int) {
+		if s < 0 {
+			return c - 1, c
+		}
+		return c, c + 1
+	}
+	for _, s := range [4][2]int{{-1, -1}, {1, -1}, {1, 1}, {-1, 1}} {
+		cx, cy := margin, margin
+		if 0 < s[0] {
+			cx += sz.X
+		}
+		if 0 < s[1] {
+			cy += sz.Y
+		}
+		x0, x1 := span(cx, s[0])
+		y0, y1 := edge(cy, s[1])
+		rect(x0, y0, x1, y1)
+		x0, x1 = edge(cx, s[0])
+		y0, y1 = span(cy, s[1])
+		rect(x0, y0, x1, y1)
+	}
+	return ret
+}
@@ -0,0 +1,102 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath_test
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/tunabay/go-bitarray"
+	"github.com/tunabay/go-bmppath"
+)
+
+func ExamplePath_SplitJobs() {
+	bmp := bitarray.NewBufferFromBitArray(bitarray.MustParse(strings.Join([]string{
+		"1100000011",
+		"1100000011",
+		"0000000000",
+		"1111111111",
+	}, "")))
+	path, err := bmppath.New(bmp, 10)
+	if err != nil {
+		panic(err)
+	}
+
+	// the work area of 12x6 with the margin 2 covers 8x2 of the artwork
+	jobs, err := path.SplitJobs(12, 6, 2)
+	if err != nil {
+		panic(err)
+	}
+	for _, job := range jobs {
+		fmt.Println(job.Col, job.Row, job.Bounds, job.Path.NumPath())
+	}
+
+	// Output:
+	// 0 0 (0,0)-(8,2) 9
+	// 1 0 (8,0)-(10,2) 9
+	// 0 1 (0,2)-(8,4) 9
+	// 1 1 (8,2)-(10,4) 9
+}
+
+func TestPath_SplitJobs(t *testing.T) {
+	const width, height = 20, 12
+	rnd := rand.New(rand.NewSource(1))
+	bmp := bitarray.NewBuffer(width * height)
+	for i := 0; i < bmp.Len(); i++ {
+		bmp.PutBitAt(i, byte(rnd.Intn(2)))
+	}
+	path, err := bmppath.New(bmp, width)
+	if err != nil {
+		t.Fatalf("New(): %v", err)
+	}
+	const jw, jh, margin = 10, 8, 2
+	jobs, err := path.SplitJobs(jw, jh, margin)
+	if err != nil {
+		t.Fatalf("SplitJobs(): %v", err)
+	}
+	if len(jobs) != 12 {
+		t.Fatalf("unexpected number of jobs: %d", len(jobs))
+	}
+	content := image.Rect(margin, margin, jw-margin, jh-margin)
+	for _, job := range jobs {
+		if job.Path.Width != jw || job.Path.Height != jh {
+			t.Errorf("%d,%d: unexpected size: %dx%d", job.Col, job.Row, job.Path.Width, job.Path.Height)
+		}
+		got := job.Path.Rasterize()
+		for y := 0; y < jh; y++ {
+			for x := 0; x < jw; x++ {
+				var want byte
+				ox, oy := x-margin+job.Bounds.Min.X, y-margin+job.Bounds.Min.Y
+				if image.Pt(ox, oy).In(job.Bounds) {
+					want = bmp.BitAt(oy*width + ox)
+				} else if image.Pt(x, y).In(content) {
+					want = 0
+				} else {
+					continue // margin with the marks
+				}
+				if b := got.BitAt(y*jw + x); b != want {
+					t.Errorf("%d,%d: pixel (%d, %d): got %d, want %d", job.Col, job.Row, x, y, b, want)
+				}
+			}
+		}
+		marks := 0
+		for n := 0; n < job.Path.NumPath(); n++ {
+			if !job.Path.PathBounds(n).Overlaps(content) {
+				marks++
+			}
+		}
+		if marks != 8 {
+			t.Errorf("%d,%d: unexpected number of marks: %d", job.Col, job.Row, marks)
+		}
+	}
+
+	if _, err := path.SplitJobs(4, 4, 2); !errors.Is(err, bmppath.ErrInvalidWidth) {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
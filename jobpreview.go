@@ -0,0 +1,105 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strings"
+)
+
+// WriteJobPreview writes an SVG document previewing the closed paths as a
+// plotter job. The closed paths are stroked instead of filled, with the
+// arrowheads indicating the drawing direction on the first edge and the long
+// edges of each closed path, and the numbers indicating the drawing order at
+// their starting vertices. The pen-up travels from the origin and between the
+// closed paths are drawn as the dashed lines. The order and the direction are
+// those of p.Vertices, so use Reorder beforehand to preview the optimized job.
+func (p *Path) WriteJobPreview(w io.Writer) error {
+	f := FormatCoord(3, "")
+	u := math.Max(0.05, float64(p.Width)/64)
+	if h := float64(p.Height) / 64; u < h {
+		u = h
+	}
+	a := u * 2 // the size of the arrowheads
+
+	var sb strings.Builder
+	fmt.Fprintln(&sb, `<?xml version="1.0" encoding="utf-8"?>`)
+	fmt.Fprint(&sb, `<svg version="1.1" xmlns="http://www.w3.org/2000/svg"`)
+	fmt.Fprintf(&sb, ` viewBox="0 0 %d %d">`, p.Width, p.Height)
+	fmt.Fprintln(&sb)
+	fmt.Fprintf(&sb, `<path fill="#fff" d="m0,0h%dv%dh-%dz"/>`, p.Width, p.Height, p.Width)
+	fmt.Fprintln(&sb)
+
+	// pen-up travels
+	fmt.Fprintf(&sb, `<g id="travel" fill="none" stroke="#888" stroke-width="%s" stroke-dasharray="%s">`, f(u/2), f(u*2))
+	fmt.Fprintln(&sb)
+	var pen Vertex
+	for _, vs := range p.Vertices {
+		if len(vs) == 0 {
+			continue
+		}
+		if vs[0] != pen {
+			fmt.Fprintf(&sb, `<path d="M%d,%dL%d,%d"/>`, pen[0], pen[1], vs[0][0], vs[0][1])
+			fmt.Fprintln(&sb)
+		}
+		pen = vs[0]
+	}
+	fmt.Fprintln(&sb, `</g>`)
+
+	// contours
+	fmt.Fprintf(&sb, `<g id="contours" fill="none" stroke="#000" stroke-width="%s">`, f(u/2))
+	fmt.Fprintln(&sb)
+	for _, vs := range p.Vertices {
+		if len(vs) == 0 {
+			continue
+		}
+		sb.WriteString(`<path d="`)
+		_ = pathSVGD(&sb, vs, Vertex{})
+		fmt.Fprintln(&sb, `"/>`)
+	}
+	fmt.Fprintln(&sb, `</g>`)
+
+	// arrowheads at the midpoints of the edges
+	fmt.Fprintln(&sb, `<g id="arrows" fill="#c00">`)
+	for _, vs := range p.Vertices {
+		for i, v0 := range vs {
+			v1 := vs[(i+1)%len(vs)]
+			dx, dy := float64(v1[0]-v0[0]), float64(v1[1]-v0[1])
+			l := math.Hypot(dx, dy)
+			if l == 0 || (0 < i && l < a*4) {
+				continue
+			}
+			dx, dy = dx/l, dy/l
+			mx, my := float64(v0[0])+dx*l/2, float64(v0[1])+dy*l/2
+			sb.WriteString(`<path d="M`)
+			writeSVGNumbers(&sb, f, mx+dx*a/2, my+dy*a/2)
+			sb.WriteString("L")
+			writeSVGNumbers(&sb, f, mx-dx*a/2-dy*a/2, my-dy*a/2+dx*a/2)
+			sb.WriteString("L")
+			writeSVGNumbers(&sb, f, mx-dx*a/2+dy*a/2, my-dy*a/2-dx*a/2)
+			fmt.Fprintln(&sb, `z"/>`)
+		}
+	}
+	fmt.Fprintln(&sb, `</g>`)
+
+	// drawing order
+	fmt.Fprintf(&sb, `<g id="numbers" fill="#00c" font-size="%s">`, f(u*3))
+	fmt.Fprintln(&sb)
+	for i, vs := range p.Vertices {
+		if len(vs) == 0 {
+			continue
+		}
+		fmt.Fprintf(&sb, `<text x="%d" y="%d">%d</text>`, vs[0][0], vs[0][1], i+1)
+		fmt.Fprintln(&sb)
+	}
+	fmt.Fprintln(&sb, `</g>`)
+	fmt.Fprintln(&sb, `</svg>`)
+	if _, err := fmt.Fprint(w, sb.String()); err != nil {
+		return fmt.Errorf("write failure: %w", err)
+	}
+	return nil
+}
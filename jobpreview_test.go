@@ -0,0 +1,82 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath_test
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/tunabay/go-bitarray"
+	"github.com/tunabay/go-bmppath"
+)
+
+func TestPath_WriteJobPreview(t *testing.T) {
+	bmp := bitarray.NewBufferFromBitArray(bitarray.MustParse(strings.Join([]string{
+		"00000000",
+		"01111110",
+		"01000010",
+		"01011010",
+		"01000010",
+		"01111110",
+		"00000000",
+		"00000011",
+	}, "")))
+	path, err := bmppath.New(bmp, 8)
+	if err != nil {
+		t.Fatalf("New(): %v", err)
+	}
+	var buf bytes.Buffer
+	if err := path.WriteJobPreview(&buf); err != nil {
+		t.Fatalf("WriteJobPreview(): %v", err)
+	}
+	type group struct {
+		ID    string `xml:"id,attr"`
+		Paths []struct {
+			D string `xml:"d,attr"`
+		} `xml:"path"`
+		Texts []struct {
+			X    int    `xml:"x,attr"`
+			Y    int    `xml:"y,attr"`
+			Text string `xml:",chardata"`
+		} `xml:"text"`
+	}
+	var doc struct {
+		Groups []group `xml:"g"`
+	}
+	if err := xml.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("invalid document: %v\n%s", err, buf.String())
+	}
+	groups := map[string]group{}
+	for _, g := range doc.Groups {
+		groups[g.ID] = g
+	}
+
+	n := path.NumPath()
+	if got := len(groups["contours"].Paths); got != n {
+		t.Errorf("unexpected number of contours: got %d, want %d", got, n)
+	}
+	// the first closed path starts away from the origin
+	if got := len(groups["travel"].Paths); got != n {
+		t.Errorf("unexpected number of travels: got %d, want %d", got, n)
+	}
+	if got := len(groups["arrows"].Paths); got < n {
+		t.Errorf("unexpected number of arrows: got %d, want >= %d", got, n)
+	}
+	texts := groups["numbers"].Texts
+	if len(texts) != n {
+		t.Fatalf("unexpected number of numbers: got %d, want %d", len(texts), n)
+	}
+	for i, text := range texts {
+		if text.Text != strconv.Itoa(i+1) {
+			t.Errorf("#%d: unexpected number: %q", i, text.Text)
+		}
+		if v := path.Vertices[i][0]; text.X != v.X() || text.Y != v.Y() {
+			t.Errorf("#%d: unexpected position: (%d, %d), want %s", i, text.X, text.Y, v)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// jsonPath is the JSON representation of a Path.
+type jsonPath struct {
+	Width    int       `json:"width"`
+	Height   int       `json:"height"`
+	Vertices [][][]int `json:"vertices"`
+	Labels   []string  `json:"labels,omitempty"`
+}
+
+// MarshalJSON implements the json.Marshaler interface. The schema is stable and
+// is an object with the following members:
+//
+//	width     the width of the canvas, a non-negative integer
+//	height    the height of the canvas, a non-negative integer
+//	vertices  the array of the closed paths, each of which is the array of
+//	          the vertices [x, y] of the integer coordinates
+//	labels    the array of the labels of the closed paths, omitted if nil
+//
+// For example:
+//
+//	{"width":5,"height":1,"vertices":[[[1,0],[3,0],[3,1],[1,1]]]}
+func (p *Path) MarshalJSON() ([]byte, error) {
+	jp := jsonPath{
+		Width:    p.Width,
+		Height:   p.Height,
+		Vertices: make([][][]int, len(p.Vertices)),
+		Labels:   p.Labels,
+	}
+	for i, vs := range p.Vertices {
+		jp.Vertices[i] = make([][]int, len(vs))
+		for j, v := range vs {
+			jp.Vertices[i][j] = []int{v[0], v[1]}
+		}
+	}
+	return json.Marshal(jp)
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface. It accepts the
+// schema written by MarshalJSON, and returns ErrInvalidEncoding if the data
+// does not conform to it.
+func (p *Path) UnmarshalJSON(data []byte) error {
+	var jp jsonPath
+	if err := json.Unmarshal(data, &jp); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidEncoding, err)
+	}
+	if jp.Width < 0 || jp.Height < 0 {
+		return fmt.Errorf("%w: negative size %dx%d", ErrInvalidEncoding, jp.Width, jp.Height)
+	}
+	if len(jp.Vertices) < len(jp.Labels) {
+		return fmt.Errorf("%w: %d labels for %d paths", ErrInvalidEncoding, len(jp.Labels), len(jp.Vertices))
+	}
+	vss := make([][]Vertex, len(jp.Vertices))
+	for i, jvs := range jp.Vertices {
+		vss[i] = make([]Vertex, len(jvs))
+		for j, jv := range jvs {
+			if len(jv) != 2 {
+				return fmt.Errorf("%w: vertex #%d of path #%d has %d coordinates", ErrInvalidEncoding, j, i, len(jv))
+			}
+			vss[i][j] = Vertex{jv[0], jv[1]}
+		}
+	}
+	*p = Path{Width: jp.Width, Height: jp.Height, Vertices: vss, Labels: jp.Labels}
+	return nil
+}
+
+// jsonContour is the JSON representation of a closed path written by
+// WriteNDJSON.
+type jsonContour struct {
+	Index    int      `json:"index"`
+	Label    string   `json:"label,omitempty"`
+	Hole     bool     `json:"hole"`
+	Vertices [][2]int `json:"vertices"`
+}
+
+// WriteNDJSON writes the closed paths as newline-delimited JSON, one object per
+// closed path, so that the extremely large traces can be piped into the data
+// pipelines such as jq without building a giant document in memory. Each line
+// is an object with the following members:
+//
+//	index     the index of the closed path
+//	label     the label of the closed path, omitted if not labeled
+//	hole      whether the closed path is a hole, running counterclockwise
+//	vertices  the array of the vertices [x, y] of the integer coordinates
+//
+// For example:
+//
+//	{"index":0,"hole":false,"vertices":[[1,0],[3,0],[3,1],[1,1]]}
+//
+// The canvas size is not written.
+func (p *Path) WriteNDJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	for i, vs := range p.Vertices {
+		c := jsonContour{
+			Index:    i,
+			Label:    p.PathLabel(i),
+			Hole:     signedArea(vs) < 0,
+			Vertices: make([][2]int, len(vs)),
+		}
+		for j, v := range vs {
+			c.Vertices[j] = v
+		}
+		if err := enc.Encode(c); err != nil {
+			return fmt.Errorf("write failure: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/tunabay/go-bitarray"
+	"github.com/tunabay/go-bmppath"
+)
+
+func ExamplePath_MarshalJSON() {
+	bmp := bitarray.NewBufferFromBitArray(bitarray.MustParse("01101"))
+	path, err := bmppath.New(bmp, 5)
+	if err != nil {
+		panic(err)
+	}
+
+	data, err := json.Marshal(path)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(string(data))
+
+	var decoded bmppath.Path
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		panic(err)
+	}
+	fmt.Println(decoded.Equal(path))
+
+	// Output:
+	// {"width":5,"height":1,"vertices":[[[1,0],[3,0],[3,1],[1,1]],[[4,0],[5,0],[5,1],[4,1]]]}
+	// true
+}
+
+func TestPath_UnmarshalJSON(t *testing.T) {
+	var p bmppath.Path
+	data := `{"width":2,"height":2,"vertices":[[[0,0],[1,0],[1,1],[0,1]]],"labels":["dot"]}`
+	if err := json.Unmarshal([]byte(data), &p); err != nil {
+		t.Fatalf("Unmarshal(): %v", err)
+	}
+	if p.Width != 2 || p.Height != 2 || p.NumPath() != 1 || p.PathLabel(0) != "dot" {
+		t.Errorf("unexpected path: %+v", p)
+	}
+	out, err := json.Marshal(&p)
+	if err != nil {
+		t.Fatalf("Marshal(): %v", err)
+	}
+	if string(out) != data {
+		t.Errorf("unexpected output: %s", out)
+	}
+
+	for _, data := range []string{
+		`{"width":-1,"height":2,"vertices":[]}`,
+		`{"width":2,"height":2,"vertices":[[[0,0,0]]]}`,
+		`{"width":2,"height":2,"vertices":[],"labels":["a"]}`,
+		`{"width":"2"}`,
+	} {
+		if err := json.Unmarshal([]byte(data), &p); !errors.Is(err, bmppath.ErrInvalidEncoding) {
+			t.Errorf("%s: unexpected error: %v", data, err)
+		}
+	}
+}
+
+func ExamplePath_WriteNDJSON() {
+	bmp := bitarray.NewBufferFromBitArray(
+		bitarray.MustParse(strings.Join([]string{
+			"111",
+			"101",
+			"111",
+		}, "")),
+	)
+	path, err := bmppath.New(bmp, 3)
+	if err != nil {
+		panic(err)
+	}
+	path.Labels = []string{"ring"}
+
+	if err := path.WriteNDJSON(os.Stdout); err != nil {
+		panic(err)
+	}
+
+	// Output:
+	// {"index":0,"label":"ring","hole":false,"vertices":[[0,0],[3,0],[3,3],[0,3]]}
+	// {"index":1,"hole":true,"vertices":[[1,1],[1,2],[2,2],[2,1]]}
+}
@@ -0,0 +1,120 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// KiCadOptions represents the options for WriteKiCad.
+type KiCadOptions struct {
+	// Layer is the layer on which the polygons are placed. If empty,
+	// "F.SilkS" is used.
+	Layer string
+
+	// PixelSize is the size of a pixel in millimeters. Zero means the
+	// default value 0.1.
+	PixelSize float64
+
+	// Board writes the board graphics gr_poly, to be pasted into a
+	// .kicad_pcb file, instead of the footprint graphics fp_poly.
+	Board bool
+
+	// Footprint, if not empty, wraps the polygons in a footprint of this
+	// name, so that the output is a complete .kicad_mod file which can be
+	// placed in a footprint library. It is not used with Board.
+	Footprint string
+}
+
+// WriteKiCad writes the vectorized bitmap image as the filled polygons of
+// KiCad in the s-expression format of KiCad 7 or later, such as for the logos
+// on the silkscreen generated from the bitmap images. The canvas is centered
+// at the origin and scaled by PixelSize. Since the polygons of KiCad cannot
+// have holes, each outline and the holes directly inside it are written as a
+// single polygon, in which each hole is joined to the nearest vertex by a
+// zero-width bridge going back and forth along the same line. nil opts is the
+// same as the zero value of KiCadOptions.
+func (p *Path) WriteKiCad(w io.Writer, opts *KiCadOptions) error {
+	if opts == nil {
+		opts = &KiCadOptions{}
+	}
+	layer, size := opts.Layer, opts.PixelSize
+	if layer == "" {
+		layer = "F.SilkS"
+	}
+	if size == 0 {
+		size = 0.1
+	}
+	elem, indent := "fp_poly", ""
+	if opts.Board {
+		elem = "gr_poly"
+	}
+	f := FormatCoord(6, "")
+	cx, cy := float64(p.Width)/2, float64(p.Height)/2
+
+	var sb strings.Builder
+	if opts.Footprint != "" && !opts.Board {
+		fmt.Fprintf(&sb, "(footprint %q\n", opts.Footprint)
+		sb.WriteString("  (layer \"F.Cu\")\n")
+		sb.WriteString("  (attr board_only exclude_from_pos_files exclude_from_bom)\n")
+		indent = "  "
+	}
+	for _, g := range p.groups() {
+		if signedArea(p.Vertices[g[0]]) < 0 {
+			continue // holes outside any outline
+		}
+		fmt.Fprintf(&sb, "%s(%s\n%s  (pts", indent, elem, indent)
+		for i, v := range p.bridgeHoles(g) {
+			if i%4 == 0 {
+				fmt.Fprintf(&sb, "\n%s   ", indent)
+			}
+			x, y := (float64(v[0])-cx)*size, (float64(v[1])-cy)*size
+			fmt.Fprintf(&sb, " (xy %s %s)", f(x), f(y))
+		}
+		fmt.Fprintf(&sb, "\n%s  )\n", indent)
+		fmt.Fprintf(&sb, "%s  (stroke (width 0) (type solid))\n", indent)
+		fmt.Fprintf(&sb, "%s  (fill solid)\n", indent)
+		fmt.Fprintf(&sb, "%s  (layer %q)\n", indent, layer)
+		fmt.Fprintf(&sb, "%s)\n", indent)
+	}
+	if indent != "" {
+		sb.WriteString(")\n")
+	}
+	if _, err := io.WriteString(w, sb.String()); err != nil {
+		return fmt.Errorf("write failure: %w", err)
+	}
+	return nil
+}
+
+// bridgeHoles joins the outline and the holes of the group g, as returned by
+// groups, into a single ring. Each hole is spliced into the ring at the pair of
+// the vertices of the ring and the hole nearest to each other, with the bridge
+// traversed in both directions.
+func (p *Path) bridgeHoles(g []int) []Vertex {
+	ring := append([]Vertex(nil), p.Vertices[g[0]]...)
+	for _, n := range g[1:] {
+		hole := p.Vertices[n]
+		if len(hole) == 0 {
+			continue
+		}
+		bi, bj, bd := 0, 0, int64(-1)
+		for i, u := range ring {
+			for j, v := range hole {
+				if d := sqDist(v[0]-u[0], v[1]-u[1]); bd < 0 || d < bd {
+					bi, bj, bd = i, j, d
+				}
+			}
+		}
+		joined := make([]Vertex, 0, len(ring)+len(hole)+2)
+		joined = append(joined, ring[:bi+1]...)
+		joined = append(joined, hole[bj:]...)
+		joined = append(joined, hole[:bj+1]...)
+		joined = append(joined, ring[bi:]...)
+		ring = joined
+	}
+	return ring
+}
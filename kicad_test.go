@@ -0,0 +1,53 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath_test
+
+import (
+	"os"
+	"strings"
+
+	"github.com/tunabay/go-bitarray"
+	"github.com/tunabay/go-bmppath"
+)
+
+func ExamplePath_WriteKiCad() {
+	bmp := bitarray.NewBufferFromBitArray(
+		bitarray.MustParse(strings.Join([]string{
+			"1110",
+			"1010",
+			"1110",
+			"0001",
+		}, "")),
+	)
+	path, err := bmppath.New(bmp, 4)
+	if err != nil {
+		panic(err)
+	}
+
+	opts := &bmppath.KiCadOptions{
+		PixelSize: 0.25,
+		Footprint: "Logo",
+	}
+	if err := path.WriteKiCad(os.Stdout, opts); err != nil {
+		panic(err)
+	}
+
+	// Output:
+	// (footprint "Logo"
+	//   (layer "F.Cu")
+	//   (attr board_only exclude_from_pos_files exclude_from_bom)
+	//   (fp_poly
+	//     (pts
+	//       (xy -0.5 -0.5) (xy -0.25 -0.25) (xy -0.25 0) (xy 0 0)
+	//       (xy 0 -0.25) (xy -0.25 -0.25) (xy -0.5 -0.5) (xy 0.25 -0.5)
+	//       (xy 0.25 0.25) (xy 0.5 0.25) (xy 0.5 0.5) (xy 0.25 0.5)
+	//       (xy 0.25 0.25) (xy -0.5 0.25)
+	//     )
+	//     (stroke (width 0) (type solid))
+	//     (fill solid)
+	//     (layer "F.SilkS")
+	//   )
+	// )
+}
@@ -0,0 +1,60 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath
+
+import (
+	"sort"
+)
+
+// PathLabel returns the label of the closed path specified by the index n, or
+// an empty string if it is not labeled.
+func (p *Path) PathLabel(n int) string {
+	if n < len(p.Labels) {
+		return p.Labels[n]
+	}
+	return ""
+}
+
+// LabelContours attaches the names to the closed paths using the reference
+// points refs, which maps each name to the coordinate of a pixel. Each name is
+// attached to the innermost closed path containing the center of the pixel,
+// that is, the outline of the ink or the hole where the pixel is. The labels
+// are stored in p.Labels, and they are used by the writers and exporters. The
+// names whose pixels are not inside any closed path are returned in sorted
+// order. When multiple names point to the same closed path, the
+// lexicographically last one wins.
+func (p *Path) LabelContours(refs map[string]Vertex) []string {
+	names := make([]string, 0, len(refs))
+	for name := range refs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	areas := make([]int64, len(p.Vertices))
+	for i, vs := range p.Vertices {
+		if areas[i] = signedArea(vs); areas[i] < 0 {
+			areas[i] = -areas[i]
+		}
+	}
+	var unmatched []string
+	for _, name := range names {
+		ref := refs[name]
+		found := -1
+		for i, vs := range p.Vertices {
+			if containsPoint(vs, ref) && (found == -1 || areas[i] < areas[found]) {
+				found = i
+			}
+		}
+		if found == -1 {
+			unmatched = append(unmatched, name)
+			continue
+		}
+		for len(p.Labels) < len(p.Vertices) {
+			p.Labels = append(p.Labels, "")
+		}
+		p.Labels[found] = name
+	}
+	return unmatched
+}
@@ -0,0 +1,45 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath_test
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/tunabay/go-bitarray"
+	"github.com/tunabay/go-bmppath"
+)
+
+func ExamplePath_LabelContours() {
+	bmp := bitarray.NewBufferFromBitArray(
+		bitarray.MustParse(strings.Join([]string{
+			"11101",
+			"10100",
+			"11101",
+		}, "")),
+	)
+	path, err := bmppath.New(bmp, 5)
+	if err != nil {
+		panic(err)
+	}
+
+	unmatched := path.LabelContours(map[string]bmppath.Vertex{
+		"body":  {0, 0},
+		"hole1": {1, 1},
+		"dot":   {4, 2},
+		"void":  {3, 1},
+	})
+	for i := 0; i < path.NumPath(); i++ {
+		fmt.Printf("%d: %q: %s\n", i, path.PathLabel(i), path.PathString(i))
+	}
+	fmt.Println("unmatched:", unmatched)
+
+	// Output:
+	// 0: "body": (0, 0), (3, 0), (3, 3), (0, 3)
+	// 1: "hole1": (1, 1), (1, 2), (2, 2), (2, 1)
+	// 2: "": (4, 0), (5, 0), (5, 1), (4, 1)
+	// 3: "dot": (4, 2), (5, 2), (5, 3), (4, 3)
+	// unmatched: [void]
+}
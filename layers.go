@@ -0,0 +1,118 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+	"path/filepath"
+
+	"github.com/tunabay/go-bitarray"
+)
+
+// ErrInvalidPalette is an error thrown when the palette has no colors.
+var ErrInvalidPalette = errors.New("invalid palette")
+
+// Layer is a color layer of an image traced by NewLayers.
+type Layer struct {
+	// Color is the color of the layer, one of the colors of the palette.
+	Color color.Color
+
+	// Path is the set of paths of the area painted with Color.
+	Path *Path
+}
+
+// NewLayers traces the color image img into the layers, one for each color of
+// palette, in the same order. Each pixel is assigned to the color of palette
+// closest to it, as palette.Index does, and becomes ink of the bitmap image of
+// that layer. The bitmap images are then preprocessed and traced in the same
+// way as NewWithOptions. All the layers have the same canvas as img, so that
+// they are registered with each other. The grayscale conversion and the
+// binarization options of opts are not used. nil opts is the same as the zero
+// value of Options.
+func NewLayers(img image.Image, palette color.Palette, opts *Options) ([]*Layer, error) {
+	if img == nil {
+		return nil, fmt.Errorf("%w: img == nil", ErrInvalidBitmap)
+	}
+	b := img.Bounds()
+	if b.Dx() < 1 || b.Dy() < 1 {
+		return nil, fmt.Errorf("%w: empty image: %v", ErrInvalidBitmap, b)
+	}
+	if len(palette) == 0 {
+		return nil, fmt.Errorf("%w: no colors", ErrInvalidPalette)
+	}
+	bms := make([]*bitarray.Buffer, len(palette))
+	for i := range bms {
+		bms[i] = bitarray.NewBuffer(b.Dx() * b.Dy())
+	}
+	i := 0
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			bms[palette.Index(img.At(x, y))].PutBitAt(i, 1)
+			i++
+		}
+	}
+	ret := make([]*Layer, len(palette))
+	for i, bm := range bms {
+		p, err := NewWithOptions(bm, b.Dx(), opts)
+		if err != nil {
+			return nil, fmt.Errorf("layer #%d: %w", i, err)
+		}
+		ret[i] = &Layer{Color: palette[i], Path: p}
+	}
+	return ret, nil
+}
+
+// SeparationOptions represents the options for ExportSeparations.
+type SeparationOptions struct {
+	// Format is the file format to write.
+	Format ExportFormat
+
+	// Marks is the printer's marks drawn identically on every separation.
+	// If nil, the crop marks and the registration targets are drawn with
+	// the default parameters. The marks are not written in DXF.
+	Marks *PrintMarks
+
+	// Prefix is the prefix of the file names. If empty, "separation" is
+	// used.
+	Prefix string
+}
+
+// ExportSeparations writes each layer into its own file in the directory dir,
+// as the monochrome separation for screen printing or other processes that
+// print one color at a time. Each file has the paths of the layer filled with
+// black, regardless of the color of the layer, with the same printer's marks
+// on the same page, so that the separations can be registered with each other.
+// The files are named "<prefix>-<n>-<rrggbb>" with the extension of the format,
+// where n is the index of the layer and rrggbb is the hexadecimal RGB value of
+// its color, e.g. "separation-0-ff0000.svg". nil opts is the same as the zero
+// value of SeparationOptions.
+func ExportSeparations(dir string, layers []*Layer, opts *SeparationOptions) error {
+	if opts == nil {
+		opts = &SeparationOptions{}
+	}
+	marks := opts.Marks
+	if marks == nil {
+		marks = &PrintMarks{CropMarks: true, RegistrationTargets: true}
+	}
+	prefix := opts.Prefix
+	if prefix == "" {
+		prefix = "separation"
+	}
+	write, ext := opts.Format.writer(marks)
+	for n, l := range layers {
+		r, g, b, _ := l.Color.RGBA()
+		name := fmt.Sprintf("%s-%d-%02x%02x%02x%s", prefix, n, r>>8, g>>8, b>>8, ext)
+		if err := writeFile(filepath.Join(dir, name), func(w io.Writer) error {
+			return write(l.Path, w)
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
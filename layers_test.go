@@ -0,0 +1,87 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/tunabay/go-bmppath"
+)
+
+func TestNewLayers(t *testing.T) {
+	white := color.RGBA{0xff, 0xff, 0xff, 0xff}
+	red := color.RGBA{0xff, 0x00, 0x00, 0xff}
+	blue := color.RGBA{0x00, 0x00, 0xff, 0xff}
+	img := image.NewRGBA(image.Rect(0, 0, 6, 4))
+	for y := 0; y < 4; y++ {
+		for x := 0; x < 6; x++ {
+			img.Set(x, y, white)
+		}
+	}
+	for y := 1; y < 3; y++ {
+		img.Set(1, y, color.RGBA{0xf0, 0x10, 0x10, 0xff}) // close to red
+		img.Set(4, y, blue)
+	}
+
+	layers, err := bmppath.NewLayers(img, color.Palette{white, red, blue}, nil)
+	if err != nil {
+		t.Fatalf("NewLayers(): %v", err)
+	}
+	if len(layers) != 3 {
+		t.Fatalf("unexpected number of layers: %d", len(layers))
+	}
+	for i, want := range []string{
+		"1 outline(s), 2 hole(s)",
+		"1 outline(s), 0 hole(s)",
+		"1 outline(s), 0 hole(s)",
+	} {
+		l := layers[i]
+		if l.Path.Width != 6 || l.Path.Height != 4 {
+			t.Errorf("#%d: unexpected canvas: %dx%d", i, l.Path.Width, l.Path.Height)
+		}
+		outlines := l.Path.Hierarchy().Children
+		holes := 0
+		for _, n := range outlines {
+			holes += len(n.Children)
+		}
+		if got := fmt.Sprintf("%d outline(s), %d hole(s)", len(outlines), holes); got != want {
+			t.Errorf("#%d: got %q, want %q", i, got, want)
+		}
+	}
+
+	if _, err := bmppath.NewLayers(img, nil, nil); !errors.Is(err, bmppath.ErrInvalidPalette) {
+		t.Errorf("unexpected error for empty palette: %v", err)
+	}
+	if _, err := bmppath.NewLayers(nil, color.Palette{white}, nil); !errors.Is(err, bmppath.ErrInvalidBitmap) {
+		t.Errorf("unexpected error for nil image: %v", err)
+	}
+
+	dir := t.TempDir()
+	if err := bmppath.ExportSeparations(dir, layers[1:], &bmppath.SeparationOptions{Format: bmppath.ExportEPS}); err != nil {
+		t.Fatalf("ExportSeparations(): %v", err)
+	}
+	var heads [][]byte
+	for _, name := range []string{"separation-0-ff0000.eps", "separation-1-0000ff.eps"} {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatalf("ReadFile(): %v", err)
+		}
+		// the header and the marks, before the paths
+		heads = append(heads, data[:bytes.LastIndex(data, []byte("gsave\n"))])
+	}
+	if !bytes.Equal(heads[0], heads[1]) {
+		t.Errorf("inconsistent marks:\n%s\n%s", heads[0], heads[1])
+	}
+	if !bytes.Contains(heads[0], []byte(" arc\n")) {
+		t.Errorf("no registration targets:\n%s", heads[0])
+	}
+}
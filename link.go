@@ -0,0 +1,110 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath
+
+import (
+	"fmt"
+	"sort"
+)
+
+// segment is the straight edge from the grid point from to to in the direction
+// dir.
+type segment struct {
+	from, to Vertex
+	dir      int
+	used     bool
+}
+
+// linker links the straight edges into the closed paths without a bitmap or an
+// EdgeGrid, so that its cost does not depend on the area the edges spread over.
+// The edges must not overlap, and no edge may pass through the starting or the
+// ending point of another edge.
+type linker struct {
+	segs   []segment
+	out    map[Vertex]*[4]int // index+1 of segs for each direction
+	starts []int              // indexes of the rightward segs
+}
+
+func newLinker() linker {
+	return linker{out: make(map[Vertex]*[4]int)}
+}
+
+// addSegment adds the edge from the grid point from to to in the direction dir.
+func (l *linker) addSegment(from, to Vertex, dir int) {
+	l.segs = append(l.segs, segment{from: from, to: to, dir: dir})
+	o := l.out[from]
+	if o == nil {
+		o = &[4]int{}
+		l.out[from] = o
+	}
+	o[dir] = len(l.segs)
+	if dir == 1 {
+		l.starts = append(l.starts, len(l.segs)-1)
+	}
+}
+
+// link links the edges into the closed paths in the same way as
+// (*EdgeGrid).paths, and assembles them into a Path of the size width x height
+// in the same way as (*EdgeGrid).trace. Therefore, the result is identical to
+// the one traced from the bitmap having the same edges. It returns an error if
+// some of the edges cannot be linked into the closed paths.
+func (l *linker) link(width, height int) (*Path, error) {
+	sort.Slice(l.starts, func(i, j int) bool {
+		a, b := l.segs[l.starts[i]].from, l.segs[l.starts[j]].from
+		return a[1] < b[1] || a[1] == b[1] && a[0] < b[0]
+	})
+	a := &arena{}
+	ps := &pathSet{width: width, height: height}
+	for _, i := range l.starts {
+		if l.segs[i].used {
+			continue
+		}
+		l.segs[i].used = true
+		s := l.segs[i].from
+		path := newPath(a, s)
+		dir, c := 1, l.segs[i].to
+		for c != s {
+			next := -1
+			if o := l.out[c]; o != nil {
+				for _, nd := range turns[dir] {
+					if k := o[nd]; k != 0 && !l.segs[k-1].used {
+						next = k - 1
+						break
+					}
+				}
+			}
+			if next < 0 {
+				return nil, fmt.Errorf("unmatched edge at %v", c)
+			}
+			if nd := l.segs[next].dir; nd != dir {
+				path.addVertex(c[0], c[1])
+				dir = nd
+			}
+			l.segs[next].used = true
+			c = l.segs[next].to
+		}
+		path.close()
+		ps.addPath(path)
+	}
+	for _, seg := range l.segs {
+		if !seg.used {
+			return nil, fmt.Errorf("unmatched edge at %v", seg.from)
+		}
+	}
+	sort.Sort(pathList(ps.paths))
+	ps.merge(nil)
+	ps.sort()
+
+	return &Path{
+		Width:    width,
+		Height:   height,
+		Vertices: ps.pub(),
+	}, nil
+}
+
+// step returns the grid point next to v in the direction dir.
+func step(v Vertex, dir int) Vertex {
+	return Vertex{v[0] + moves[dir][0], v[1] + moves[dir][1]}
+}
@@ -0,0 +1,105 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath
+
+// PrintMarks specifies the printer's marks drawn around the artwork by the
+// print-oriented encoders, WritePDF, WriteEPSOptions, and WriteSVGOptions. The
+// canvas of the artwork is the trim box, and the page is extended around it to
+// contain the bleed and the marks. The lengths are in points, 1/72 inch. On the
+// SVG documents, one user unit, which is one pixel of the artwork, is regarded
+// as 1/96 inch as in CSS.
+type PrintMarks struct {
+	// Bleed is the bleed margin around the trim box. The page is extended
+	// by it even without the marks, and the PDF documents also declare it
+	// as the BleedBox.
+	Bleed float64
+
+	// CropMarks draws the crop marks at the four corners of the trim box.
+	CropMarks bool
+
+	// RegistrationTargets draws the registration targets, the circles with
+	// the crosshairs, at the middle of the four sides of the trim box.
+	RegistrationTargets bool
+
+	// Offset is the distance from the trim box to the marks. It is raised to
+	// Bleed if it is less, so that the marks do not intrude into the bleed.
+	Offset float64
+
+	// Length is the length of the crop marks and the size of the
+	// registration targets. Zero means the default value 18.
+	Length float64
+
+	// LineWidth is the width of the lines of the marks. Zero means the
+	// default value 0.25.
+	LineWidth float64
+}
+
+// params returns the offset, the length, and the line width of the marks with
+// the default values applied.
+func (m *PrintMarks) params() (float64, float64, float64) {
+	offset, length, lw := m.Offset, m.Length, m.LineWidth
+	if offset < m.Bleed {
+		offset = m.Bleed
+	}
+	if length == 0 {
+		length = 18
+	}
+	if lw == 0 {
+		lw = 0.25
+	}
+	return offset, length, lw
+}
+
+// extent returns the width of the area added around the trim box to contain
+// the bleed and the marks. m may be nil.
+func (m *PrintMarks) extent() float64 {
+	switch {
+	case m == nil:
+		return 0
+	case !m.CropMarks && !m.RegistrationTargets:
+		return m.Bleed
+	}
+	offset, length, lw := m.params()
+	return offset + length + lw
+}
+
+// shapes returns the line segments and the circles of the marks around the trim
+// box of the size tw x th, relative to its upper left corner with the y-axis
+// pointing downward. m may be nil.
+func (m *PrintMarks) shapes(tw, th float64) ([][2]FloatVertex, []Circle) {
+	if m == nil {
+		return nil, nil
+	}
+	offset, length, _ := m.params()
+	var lines [][2]FloatVertex
+	var circles []Circle
+	if m.CropMarks {
+		for _, s := range [4][2]float64{{-1, -1}, {1, -1}, {1, 1}, {-1, 1}} {
+			cx, cy := 0.0, 0.0
+			if 0 < s[0] {
+				cx = tw
+			}
+			if 0 < s[1] {
+				cy = th
+			}
+			lines = append(lines,
+				[2]FloatVertex{{cx + s[0]*offset, cy}, {cx + s[0]*(offset+length), cy}},
+				[2]FloatVertex{{cx, cy + s[1]*offset}, {cx, cy + s[1]*(offset+length)}},
+			)
+		}
+	}
+	if m.RegistrationTargets {
+		d := offset + length/2
+		for _, c := range []FloatVertex{{tw / 2, -d}, {tw + d, th / 2}, {tw / 2, th + d}, {-d, th / 2}} {
+			h := length * 3 / 8
+			lines = append(lines,
+				[2]FloatVertex{{c[0] - h, c[1]}, {c[0] + h, c[1]}},
+				[2]FloatVertex{{c[0], c[1] - h}, {c[0], c[1] + h}},
+			)
+			circles = append(circles, Circle{Center: c, Radius: length / 4})
+		}
+	}
+	return lines, circles
+}
@@ -0,0 +1,153 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath_test
+
+import (
+	"bytes"
+	"encoding/xml"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/tunabay/go-bitarray"
+	"github.com/tunabay/go-bmppath"
+)
+
+func ExamplePath_WriteEPSOptions() {
+	bmp := bitarray.NewBufferFromBitArray(bitarray.MustParse("0110"))
+	path, err := bmppath.New(bmp, 4)
+	if err != nil {
+		panic(err)
+	}
+
+	opts := &bmppath.EPSOptions{
+		Marks: &bmppath.PrintMarks{
+			CropMarks: true,
+			Offset:    1,
+			Length:    4,
+			LineWidth: 0.5,
+		},
+	}
+	if err := path.WriteEPSOptions(os.Stdout, opts); err != nil {
+		panic(err)
+	}
+
+	// Output:
+	// %!PS-Adobe-3.0 EPSF-3.0
+	// %%BoundingBox: 0 0 16 13
+	// %%Pages: 0
+	// %%EndComments
+	// gsave
+	// 0.5 setlinewidth
+	// newpath
+	// 5 7 moveto
+	// 1 7 lineto
+	// 6 8 moveto
+	// 6 12 lineto
+	// 11 7 moveto
+	// 15 7 lineto
+	// 10 8 moveto
+	// 10 12 lineto
+	// 11 6 moveto
+	// 15 6 lineto
+	// 10 5 moveto
+	// 10 1 lineto
+	// 5 6 moveto
+	// 1 6 lineto
+	// 6 5 moveto
+	// 6 1 lineto
+	// stroke
+	// grestore
+	// gsave
+	// 6 6 translate
+	// newpath
+	// 1 1 moveto
+	// 3 1 lineto
+	// 3 0 lineto
+	// 1 0 lineto
+	// closepath
+	// fill
+	// grestore
+	// %%EOF
+}
+
+func TestPrintMarks(t *testing.T) {
+	bmp := bitarray.NewBufferFromBitArray(bitarray.MustParse(strings.Join([]string{
+		"0110",
+		"1111",
+		"0110",
+	}, "")))
+	path, err := bmppath.New(bmp, 4)
+	if err != nil {
+		t.Fatalf("New(): %v", err)
+	}
+	marks := &bmppath.PrintMarks{
+		Bleed:               9,
+		CropMarks:           true,
+		RegistrationTargets: true,
+		Offset:              6,
+		Length:              18,
+		LineWidth:           1,
+	}
+
+	// the offset is raised to the bleed: 9 + 18 + 1 = 28
+	var buf bytes.Buffer
+	if err := path.WritePDF(&buf, &bmppath.PDFOptions{Width: 72, Marks: marks}); err != nil {
+		t.Fatalf("WritePDF(): %v", err)
+	}
+	doc := buf.String()
+	for _, want := range []string{
+		"/MediaBox [0 0 128 110] /BleedBox [19 19 109 91] /TrimBox [28 28 100 82]",
+		"18 0 0 -18 28 82 cm\n",
+		"1 w\n",
+	} {
+		if !strings.Contains(doc, want) {
+			t.Errorf("PDF: %q not found:\n%s", want, doc)
+		}
+	}
+	// 8 crop marks and 4 targets of 2 lines and 4 curves, besides the 11
+	// lines of the artwork
+	if got := strings.Count(doc, " l\n") - 11; got != 16 {
+		t.Errorf("PDF: unexpected number of lines: %d", got)
+	}
+	if got := strings.Count(doc, " c\n"); got != 16 {
+		t.Errorf("PDF: unexpected number of curves: %d", got)
+	}
+
+	buf.Reset()
+	if err := path.WriteSVGOptions(&buf, &bmppath.SVGOptions{Marks: marks}); err != nil {
+		t.Fatalf("WriteSVGOptions(): %v", err)
+	}
+	var svg struct {
+		ViewBox string `xml:"viewBox,attr"`
+		Group   struct {
+			Circles []struct {
+				R string `xml:"r,attr"`
+			} `xml:"circle"`
+		} `xml:"g"`
+	}
+	if err := xml.Unmarshal(buf.Bytes(), &svg); err != nil {
+		t.Fatalf("SVG: invalid document: %v\n%s", err, buf.String())
+	}
+	// 28pt is 37.333... user units
+	if want := "-37.33333333333333 -37.33333333333333 78.66666666666666 77.66666666666666"; svg.ViewBox != want {
+		t.Errorf("SVG: unexpected viewBox: got %q, want %q", svg.ViewBox, want)
+	}
+	if len(svg.Group.Circles) != 4 || svg.Group.Circles[0].R != "6" {
+		t.Errorf("SVG: unexpected targets: %+v", svg.Group.Circles)
+	}
+
+	// the bleed alone only extends the page
+	buf.Reset()
+	if err := path.WritePDF(&buf, &bmppath.PDFOptions{Marks: &bmppath.PrintMarks{Bleed: 3}}); err != nil {
+		t.Fatalf("WritePDF(): %v", err)
+	}
+	if want := "/MediaBox [0 0 10 9] /BleedBox [0 0 10 9] /TrimBox [3 3 7 6]"; !strings.Contains(buf.String(), want) {
+		t.Errorf("PDF: %q not found:\n%s", want, buf.String())
+	}
+	if strings.Contains(buf.String(), " w\n") {
+		t.Errorf("PDF: unexpected marks:\n%s", buf.String())
+	}
+}
@@ -0,0 +1,113 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath
+
+import (
+	"image"
+	"math"
+)
+
+// descriptorGrid is the number of the zones along each side of the bounding
+// box in the ShapeDescriptor.
+const descriptorGrid = 8
+
+// ShapeDescriptor is a set of features of a shape invariant to translation and
+// scaling, used to compare the shapes traced at different positions and sizes,
+// such as the glyphs of a scanned text.
+type ShapeDescriptor struct {
+	// Aspect is the ratio of the width to the height of the bounding box.
+	Aspect float64
+
+	// Holes is the number of the holes.
+	Holes int
+
+	// Zones is the ink coverage of each of the 8x8 zones dividing the
+	// bounding box, in the raster order, each in the range [0, 1].
+	Zones [descriptorGrid * descriptorGrid]float64
+}
+
+// Descriptor returns the ShapeDescriptor of all the closed paths of p together,
+// measured in the bounding box of them.
+func (p *Path) Descriptor() *ShapeDescriptor {
+	ret := &ShapeDescriptor{Aspect: 1}
+	if len(p.Vertices) == 0 {
+		return ret
+	}
+	r := p.PathBounds(0)
+	for n := range p.Vertices[1:] {
+		r = r.Union(p.PathBounds(n + 1))
+	}
+	if r.Empty() {
+		return ret
+	}
+	ret.Aspect = float64(r.Dx()) / float64(r.Dy())
+	ret.Holes = p.NumHoles()
+
+	bm := p.Float().Rasterize()
+	cw, ch := float64(r.Dx())/descriptorGrid, float64(r.Dy())/descriptorGrid
+	overlap := func(a0, a1, b0, b1 float64) float64 {
+		return math.Max(0, math.Min(a1, b1)-math.Max(a0, b0))
+	}
+	cr := r.Intersect(image.Rect(0, 0, p.Width, p.Height))
+	for y := cr.Min.Y; y < cr.Max.Y; y++ {
+		for x := cr.Min.X; x < cr.Max.X; x++ {
+			if bm.BitAt(y*p.Width+x) == 0 {
+				continue
+			}
+			// the pixel in the coordinates relative to the bounding box
+			px, py := float64(x-r.Min.X), float64(y-r.Min.Y)
+			gx0, gx1 := int(px/cw), int(math.Min((px+1)/cw, descriptorGrid-1))
+			gy0, gy1 := int(py/ch), int(math.Min((py+1)/ch, descriptorGrid-1))
+			for gy := gy0; gy <= gy1; gy++ {
+				oy := overlap(py, py+1, float64(gy)*ch, float64(gy+1)*ch)
+				for gx := gx0; gx <= gx1; gx++ {
+					ox := overlap(px, px+1, float64(gx)*cw, float64(gx+1)*cw)
+					ret.Zones[gy*descriptorGrid+gx] += ox * oy
+				}
+			}
+		}
+	}
+	for i := range ret.Zones {
+		ret.Zones[i] /= cw * ch
+	}
+	return ret
+}
+
+// Similarity returns the similarity of the shapes described by d and e, in the
+// range [0, 1], where 1 means they are identical. It decreases with the mean
+// difference of the ink coverage of the zones, the ratio of the aspect ratios,
+// and the difference of the numbers of the holes.
+func (d *ShapeDescriptor) Similarity(e *ShapeDescriptor) float64 {
+	var diff float64
+	for i, z := range d.Zones {
+		diff += math.Abs(z - e.Zones[i])
+	}
+	diff /= float64(len(d.Zones))
+	diff += math.Abs(math.Log(d.Aspect/e.Aspect)) / 4
+	diff += float64(abs(d.Holes-e.Holes)) / 4
+	return math.Max(0, 1-diff)
+}
+
+// Match finds the template most similar to p by the ShapeDescriptor, and
+// returns its key and the similarity as the confidence in the range [0, 1]. It
+// enables simple bitmap font capture pipelines, combined with SegmentText, by
+// matching each glyph against the templates traced from the known characters.
+// If multiple templates are equally similar, the smallest key is chosen. If
+// templates is empty, it returns 0 and 0.
+func Match(p *Path, templates map[rune]*Path) (rune, float64) {
+	d := p.Descriptor()
+	var best rune
+	conf := -1.0
+	for r, t := range templates {
+		s := d.Similarity(t.Descriptor())
+		if conf < s || (s == conf && r < best) {
+			best, conf = r, s
+		}
+	}
+	if conf < 0 {
+		return 0, 0
+	}
+	return best, conf
+}
@@ -0,0 +1,50 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath_test
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/tunabay/go-bitarray"
+	"github.com/tunabay/go-bmppath"
+)
+
+// glyph traces the rows of a bitmap glyph, where '#' is ink.
+func glyph(rows ...string) *bmppath.Path {
+	s := strings.Map(func(r rune) rune {
+		if r == '#' {
+			return '1'
+		}
+		return '0'
+	}, strings.Join(rows, ""))
+	p, err := bmppath.New(bitarray.NewBufferFromBitArray(bitarray.MustParse(s)), len(rows[0]))
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+func ExampleMatch() {
+	templates := map[rune]*bmppath.Path{
+		'I': glyph("###", ".#.", ".#.", ".#.", "###"),
+		'L': glyph("#..", "#..", "#..", "#..", "###"),
+		'O': glyph("###", "#.#", "#.#", "#.#", "###"),
+		'T': glyph("###", ".#.", ".#.", ".#.", ".#."),
+	}
+
+	// a noisy L scanned at 3x
+	scanned := glyph("###", "##.", "#..", "#..", "###").Scale(3).Translate(2, 1)
+	r, conf := bmppath.Match(scanned, templates)
+	fmt.Printf("%c %.2f\n", r, conf)
+
+	scanned = glyph("###", "#.#", "#.#", "#.#", "###").Scale(4).Translate(3, 5)
+	r, conf = bmppath.Match(scanned, templates)
+	fmt.Printf("%c %.2f\n", r, conf)
+
+	// Output:
+	// L 0.80
+	// O 1.00
+}
@@ -0,0 +1,233 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath
+
+import (
+	"image"
+	"sort"
+)
+
+// touch is a vertex of the closed path with the index n in pathSet.paths, and
+// the range of the touches at the same coordinates in the index of merge.
+type touch struct {
+	v      *vertex
+	n      int
+	lo, hi int
+}
+
+// merge merges the closed paths touching each other at a vertex, splicing the
+// later one in the order of ps.paths into the earlier one at the first
+// touching vertex. The result is the same as comparing all the vertices of all
+// the pairs of the closed paths repeatedly until no pair touches, but the
+// touching pairs are found by the index of the vertices sorted by the
+// coordinates, so that it runs in near linear time even for the inputs with
+// thousands of closed paths, such as the dithered halftones.
+func (ps *pathSet) merge(opts *Options) {
+	total := 0
+	for _, p := range ps.paths {
+		total += p.nVertices
+	}
+	index := make([]touch, 0, total)
+	for n, p := range ps.paths {
+		for v := p.head; ; {
+			index = append(index, touch{v: v, n: n})
+			if v = v.next; v == p.head {
+				break
+			}
+		}
+	}
+	sort.Slice(index, func(i, j int) bool {
+		vi, vj := index[i].v, index[j].v
+		return vi.y < vj.y || (vi.y == vj.y && vi.x < vj.x)
+	})
+	// the vertices of each closed path at the shared coordinates, which
+	// are the only ones that can touch the other closed paths
+	counts := make([]int, len(ps.paths)+1)
+	for lo := 0; lo < len(index); {
+		hi := lo + 1
+		for hi < len(index) && index[hi].v.x == index[lo].v.x && index[hi].v.y == index[lo].v.y {
+			hi++
+		}
+		for k := lo; k < hi; k++ {
+			index[k].lo, index[k].hi = lo, hi
+			if 1 < hi-lo {
+				counts[index[k].n+1]++
+			}
+		}
+		lo = hi
+	}
+	for n := 1; n < len(counts); n++ {
+		counts[n] += counts[n-1]
+	}
+	flat := make([]touch, counts[len(ps.paths)])
+	shared := make([][]touch, len(ps.paths))
+	for n := range shared {
+		shared[n] = flat[counts[n]:counts[n]:counts[n+1]]
+	}
+	for _, t := range index {
+		if 1 < t.hi-t.lo {
+			shared[t.n] = append(shared[t.n], t)
+		}
+	}
+	parent := make([]int, len(ps.paths))
+	for n, p := range ps.paths {
+		parent[n] = n
+		p.renumber()
+	}
+	// root returns the index of the closed path that the closed path n has
+	// been merged into
+	root := func(n int) int {
+		for parent[n] != n {
+			parent[n] = parent[parent[n]]
+			n = parent[n]
+		}
+		return n
+	}
+
+	var cands intHeap
+	for merged := true; merged; {
+		merged = false
+		for i, p0 := range ps.paths {
+			if p0.deleted {
+				continue
+			}
+			push := func(ts []touch, after int) {
+				for _, t := range ts {
+					for _, u := range index[t.lo:t.hi] {
+						if m := root(u.n); after < m {
+							cands.push(m)
+						}
+					}
+				}
+			}
+			push(shared[i], i)
+			for last := i; len(cands) != 0; {
+				j := cands.pop()
+				if j == last || ps.paths[j].deleted {
+					continue
+				}
+				last = j
+				nv0, nv1 := touching(i, j, index, shared[j], root)
+				opts.warn(WarnMerged, image.Rect(nv0.x-1, nv0.y-1, nv0.x+1, nv0.y+1), "closed paths touching at (%d, %d) merged", nv0.x, nv0.y)
+				p0.splice(nv0, nv1)
+				ps.paths[j].deleted = true
+				parent[j] = i
+				shared[i] = append(shared[i], shared[j]...)
+				push(shared[j], j)
+				shared[j] = nil
+				merged = true
+			}
+		}
+	}
+}
+
+// touching returns the pair of the vertices at which the closed paths with the
+// indexes i and j touch, the first vertex of i from its head at the same
+// coordinates as any vertex of j, and the first vertex of j from its head at
+// the same coordinates as that. sj is the vertices of j at the shared
+// coordinates.
+func touching(i, j int, index []touch, sj []touch, root func(int) int) (*vertex, *vertex) {
+	var t0 touch
+	for _, t := range sj {
+		for _, u := range index[t.lo:t.hi] {
+			if root(u.n) == i && (t0.v == nil || u.v.order < t0.v.order) {
+				t0 = u
+			}
+		}
+	}
+	var nv1 *vertex
+	for _, u := range index[t0.lo:t0.hi] {
+		if root(u.n) == j && (nv1 == nil || u.v.order < nv1.order) {
+			nv1 = u.v
+		}
+	}
+	return t0.v, nv1
+}
+
+// orderSpan is the range of vertex.order.
+const orderSpan = 1 << 62
+
+// splice inserts the closed path starting at nv1 into the closed path p
+// before its vertex nv0, and assigns the orders to the inserted vertices
+// between those of the adjacent ones. If there is not enough room between
+// them, all the vertices of p are renumbered.
+func (p *path) splice(nv0, nv1 *vertex) {
+	lo, hi := nv0.prev.order, nv0.order
+	if nv0 == p.head {
+		// inserted at the end
+		hi = orderSpan
+	}
+	n := uint64(0)
+	for v := nv1; ; {
+		n++
+		if v = v.next; v == nv1 {
+			break
+		}
+	}
+	nv0.ins(nv1)
+	if hi-lo <= n {
+		p.renumber()
+		return
+	}
+	step := (hi - lo) / (n + 1)
+	for v, k := nv1, uint64(1); k <= n; v, k = v.next, k+1 {
+		v.order = lo + step*k
+	}
+}
+
+// renumber assigns the orders to the vertices of p at even intervals.
+func (p *path) renumber() {
+	n := uint64(0)
+	for v := p.head; ; {
+		n++
+		if v = v.next; v == p.head {
+			break
+		}
+	}
+	step := orderSpan / (n + 1)
+	for v, k := p.head, uint64(1); k <= n; v, k = v.next, k+1 {
+		v.order = step * k
+	}
+}
+
+// intHeap is a min-heap of ints. It does not use container/heap, which boxes
+// each of the ints into an interface.
+type intHeap []int
+
+func (h *intHeap) push(x int) {
+	a := append(*h, x)
+	for i := len(a) - 1; 0 < i; {
+		p := (i - 1) / 2
+		if a[p] <= a[i] {
+			break
+		}
+		a[p], a[i] = a[i], a[p]
+		i = p
+	}
+	*h = a
+}
+
+func (h *intHeap) pop() int {
+	a := *h
+	x, n := a[0], len(a)-1
+	a[0] = a[n]
+	a = a[:n]
+	for i := 0; ; {
+		c := 2*i + 1
+		if n <= c {
+			break
+		}
+		if c+1 < n && a[c+1] < a[c] {
+			c++
+		}
+		if a[i] <= a[c] {
+			break
+		}
+		a[i], a[c] = a[c], a[i]
+		i = c
+	}
+	*h = a
+	return x
+}
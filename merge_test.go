@@ -0,0 +1,48 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/tunabay/go-bitarray"
+	"github.com/tunabay/go-bmppath"
+)
+
+func TestNew_merge(t *testing.T) {
+	// a dithered halftone, in which most of the pixels touch each other
+	// only at the corners
+	const width, height = 96, 64
+	r := rand.New(rand.NewSource(1))
+	bmp := bitarray.NewBuffer(width * height)
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			if (x+y)%2 == 0 && r.Intn(4) != 0 {
+				bmp.PutBitAt(width*y+x, 1)
+			}
+		}
+	}
+	path, err := bmppath.New(bmp, width)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a := path.Area(); a != bmp.OnesCount() {
+		t.Errorf("unexpected area: %d, want %d", a, bmp.OnesCount())
+	}
+	if !path.Rasterize().BitArray().Equal(bmp.BitArray()) {
+		t.Error("rasterized image differs from the source")
+	}
+	// no closed paths touch each other after merging
+	owner := make(map[bmppath.Vertex]int)
+	for i, vs := range path.Vertices {
+		for _, v := range vs {
+			if n, ok := owner[v]; ok && n != i {
+				t.Fatalf("closed paths #%d and #%d touch at %v", n, i, v)
+			}
+			owner[v] = i
+		}
+	}
+}
@@ -0,0 +1,74 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath
+
+// NumVertices returns the total number of vertices of all the closed paths.
+func (p *Path) NumVertices() int {
+	n := 0
+	for _, vs := range p.Vertices {
+		n += len(vs)
+	}
+	return n
+}
+
+// NumHoles returns the number of holes, the closed paths running
+// counterclockwise on the screen coordinates.
+func (p *Path) NumHoles() int {
+	n := 0
+	for _, vs := range p.Vertices {
+		if signedArea(vs) < 0 {
+			n++
+		}
+	}
+	return n
+}
+
+// Perimeter returns the total length of all the closed paths.
+func (p *Path) Perimeter() int {
+	l := 0
+	for _, vs := range p.Vertices {
+		for i, v0 := range vs {
+			v1 := vs[(i+1)%len(vs)]
+			l += abs(v1[0]-v0[0]) + abs(v1[1]-v0[1])
+		}
+	}
+	return l
+}
+
+// Area returns the area enclosed by the closed paths, excluding the holes. For
+// a Path created by New, it is the number of pixels set to 1 in the source
+// bitmap image. It panics if the area overflows int, which can happen only on
+// the 32-bit platforms. Use Area64 for such large paths.
+func (p *Path) Area() int {
+	a := p.Area64()
+	if int64(int(a)) != a {
+		panic("bmppath: area overflows int")
+	}
+	return int(a)
+}
+
+// Area64 is identical to Area except that the area is returned as an int64,
+// which does not overflow on the 32-bit platforms.
+func (p *Path) Area64() int64 {
+	var a int64
+	for _, vs := range p.Vertices {
+		a += signedArea(vs)
+	}
+	return a
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+func abs64(n int64) int64 {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,84 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/tunabay/go-bitarray"
+	"github.com/tunabay/go-bmppath"
+)
+
+func ExamplePath_Area() {
+	bmp := bitarray.NewBufferFromBitArray(
+		bitarray.MustParse(strings.Join([]string{
+			"11101",
+			"10100",
+			"11101",
+		}, "")),
+	)
+	path, err := bmppath.New(bmp, 5)
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Println("vertices:", path.NumVertices())
+	fmt.Println("holes:", path.NumHoles())
+	fmt.Println("perimeter:", path.Perimeter())
+	fmt.Println("area:", path.Area())
+
+	// Output:
+	// vertices: 16
+	// holes: 1
+	// perimeter: 24
+	// area: 10
+}
+
+func TestPath_Area_large(t *testing.T) {
+	// the products of the coordinates exceed the range of int32
+	const n = 1 << 20
+	p := &bmppath.Path{
+		Width:  n * 2,
+		Height: n * 2,
+		Vertices: [][]bmppath.Vertex{
+			{{n, n}, {n + 1<<10, n}, {n + 1<<10, n + 1<<10}, {n, n + 1<<10}},
+		},
+	}
+	if got, want := p.Area(), 1<<20; got != want {
+		t.Errorf("unexpected area: got %d, want %d", got, want)
+	}
+}
+
+func TestPath_Area64(t *testing.T) {
+	// the area itself exceeds the range of int32
+	const n = 1 << 16
+	p := &bmppath.Path{
+		Width:  n * 2,
+		Height: n * 2,
+		Vertices: [][]bmppath.Vertex{
+			{{0, 0}, {n * 2, 0}, {n * 2, n * 2}, {0, n * 2}},
+			{{1, 1}, {1, 2}, {2, 2}, {2, 1}},
+		},
+	}
+	const want int64 = 1<<34 - 1
+	if got := p.Area64(); got != want {
+		t.Errorf("unexpected area: got %d, want %d", got, want)
+	}
+	if got := p.InkCoverage(); got < 99.99 || 100 < got {
+		t.Errorf("unexpected coverage: %g", got)
+	}
+	func() {
+		defer func() {
+			if r := recover(); (r != nil) != (intSize == 32) {
+				t.Errorf("unexpected panic on the %d-bit platform: %v", intSize, r)
+			}
+		}()
+		if got := p.Area(); int64(got) != want {
+			t.Errorf("unexpected area: got %d, want %d", got, want)
+		}
+	}()
+}
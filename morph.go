@@ -0,0 +1,146 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath
+
+// despeckle returns a new bitmap in which the 8-connected ink components of at
+// most maxArea pixels are removed, and the 4-connected holes of at most maxArea
+// pixels are filled. Background regions connected to the image border are not
+// treated as holes. If removed is not nil, it is called with the indexes of the
+// pixels of each component removed, and whether it is an ink component.
+func (img *bitmap) despeckle(maxArea int, removed func(comp []int, ink bool)) *bitmap {
+	ret := &bitmap{width: img.width, height: img.height, pix: append([]bool(nil), img.pix...)}
+	for _, ink := range []bool{true, false} {
+		img.components(ink, func(comp []int, border bool) {
+			if len(comp) <= maxArea && (ink || !border) {
+				for _, i := range comp {
+					ret.pix[i] = !ink
+				}
+				if removed != nil {
+					removed(comp, ink)
+				}
+			}
+		})
+	}
+	return ret
+}
+
+// components calls f for each connected component of the pixels of the value
+// ink, with the indexes of the pixels of the component and whether it touches
+// the image border. Ink pixels are 8-connected, and background pixels are
+// 4-connected.
+func (img *bitmap) components(ink bool, f func(comp []int, border bool)) {
+	seen := make([]bool, len(img.pix))
+	var stack, comp []int
+	for i, b := range img.pix {
+		if b != ink || seen[i] {
+			continue
+		}
+		seen[i] = true
+		stack, comp = append(stack[:0], i), comp[:0]
+		border := false
+		for len(stack) != 0 {
+			j := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			comp = append(comp, j)
+			x, y := j%img.width, j/img.width
+			if x == 0 || y == 0 || x == img.width-1 || y == img.height-1 {
+				border = true
+			}
+			for dy := -1; dy <= 1; dy++ {
+				for dx := -1; dx <= 1; dx++ {
+					if (dx == 0 && dy == 0) || (!ink && dx != 0 && dy != 0) {
+						continue
+					}
+					nx, ny := x+dx, y+dy
+					if nx < 0 || ny < 0 || img.width <= nx || img.height <= ny {
+						continue
+					}
+					k := img.width*ny + nx
+					if img.pix[k] == ink && !seen[k] {
+						seen[k] = true
+						stack = append(stack, k)
+					}
+				}
+			}
+		}
+		f(comp, border)
+	}
+}
+
+// dilate returns a new bitmap in which each ink pixel is expanded to the square
+// of the size (2r+1) x (2r+1).
+func (img *bitmap) dilate(r int) *bitmap {
+	return img.morph(r, true)
+}
+
+// erode returns a new bitmap in which each background pixel is expanded to the
+// square of the size (2r+1) x (2r+1). The pixels outside the image are treated
+// as ink, so that the erosion does not eat the ink touching the border.
+func (img *bitmap) erode(r int) *bitmap {
+	return img.morph(r, false)
+}
+
+// morph expands the pixels of the value ink to the square of the radius r. The
+// square is separable, so this is done with a horizontal pass followed by a
+// vertical pass.
+func (img *bitmap) morph(r int, ink bool) *bitmap {
+	w, h := img.width, img.height
+	tmp := make([]bool, len(img.pix))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			v := !ink
+			for dx := -r; dx <= r && v != ink; dx++ {
+				if nx := x + dx; 0 <= nx && nx < w && img.pix[w*y+nx] == ink {
+					v = ink
+				}
+			}
+			tmp[w*y+x] = v
+		}
+	}
+	ret := &bitmap{width: w, height: h, pix: make([]bool, len(img.pix))}
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			v := !ink
+			for dy := -r; dy <= r && v != ink; dy++ {
+				if ny := y + dy; 0 <= ny && ny < h && tmp[w*ny+x] == ink {
+					v = ink
+				}
+			}
+			ret.pix[w*y+x] = v
+		}
+	}
+	return ret
+}
+
+// closeGaps returns a new bitmap processed with the morphological closing,
+// dilation followed by erosion, with the square of the radius r. It bridges
+// the gaps and fills the notches narrower than about 2r+1 pixels.
+func (img *bitmap) closeGaps(r int) *bitmap {
+	return img.dilate(r).erode(r)
+}
+
+// smooth returns a new bitmap processed with the 3x3 majority filter n times.
+// Each pixel becomes ink when 5 or more pixels of the 3x3 neighborhood
+// including itself are ink.
+func (img *bitmap) smooth(n int) *bitmap {
+	for ; 0 < n; n-- {
+		ret := &bitmap{width: img.width, height: img.height, pix: make([]bool, len(img.pix))}
+		for y := 0; y < img.height; y++ {
+			for x := 0; x < img.width; x++ {
+				c := 0
+				for dy := -1; dy <= 1; dy++ {
+					for dx := -1; dx <= 1; dx++ {
+						if img.at(x+dx, y+dy) {
+							c++
+						}
+					}
+				}
+				ret.pix[img.width*y+x] = 5 <= c
+			}
+		}
+		img = ret
+	}
+	return img
+}
@@ -0,0 +1,362 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath
+
+import (
+	"math"
+	"sort"
+)
+
+// JoinStyle specifies the shape of the outer corners of the offset paths.
+type JoinStyle int
+
+const (
+	// JoinMiter extends the offset edges until they meet, producing sharp
+	// corners.
+	JoinMiter JoinStyle = iota
+
+	// JoinRound connects the offset edges with circular arcs.
+	JoinRound
+
+	// JoinBevel connects the offset edges with straight lines, cutting the
+	// corners off.
+	JoinBevel
+)
+
+// roundJoinTolerance is the maximum distance between the circular arcs of
+// JoinRound and the line segments approximating them.
+const roundJoinTolerance = 0.05
+
+// Offset returns a new FloatPath with all the closed paths moved outward by
+// delta, that is, the ink area is grown by delta when delta is positive, and
+// shrunk when negative. This can be used for the kerf compensation of laser
+// cutters, or to create bold variants of traced glyphs. join specifies the
+// shape of the corners that are not filled by simply extending the edges.
+//
+// Each closed path is offset independently. The closed paths that vanish by
+// shrinking are removed, and so are the parts of them that are turned inside
+// out, such as the thin prongs of a shape whose base survives. However, the
+// paths that overlap each other by growing are not merged. Therefore, the
+// result should be rendered with the nonzero fill rule. The Width and Height
+// are not changed. p itself is not modified.
+func (p *Path) Offset(delta float64, join JoinStyle) *FloatPath {
+	ret := &FloatPath{
+		Width:    float64(p.Width),
+		Height:   float64(p.Height),
+		Vertices: make([][]FloatVertex, 0, len(p.Vertices)),
+	}
+	for _, vs := range p.Vertices {
+		fvs := floatVertices(vs)
+		ovs, src := offsetVertices(fvs, delta, join)
+		ret.Vertices = append(ret.Vertices, resolveOffset(fvs, ovs, src)...)
+	}
+	return ret
+}
+
+// offsetVertices offsets the closed path vs by delta. It also returns the index
+// of the edge of vs from which each edge of the result is offset, or -1 for the
+// edges added by join.
+func offsetVertices(vs []FloatVertex, delta float64, join JoinStyle) ([]FloatVertex, []int) {
+	ret := make([]FloatVertex, 0, len(vs))
+	src := make([]int, 0, len(vs))
+	unit := func(a, b FloatVertex) FloatVertex {
+		dx, dy := b[0]-a[0], b[1]-a[1]
+		l := math.Hypot(dx, dy)
+		return FloatVertex{dx / l, dy / l}
+	}
+	n := len(vs)
+	for i, v := range vs {
+		d1, d2 := unit(vs[(i+n-1)%n], v), unit(v, vs[(i+1)%n])
+		n1 := FloatVertex{d1[1], -d1[0]}
+		n2 := FloatVertex{d2[1], -d2[0]}
+		cross := d1[0]*d2[1] - d1[1]*d2[0]
+		dot := d1[0]*d2[0] + d1[1]*d2[1]
+		p1 := FloatVertex{v[0] + n1[0]*delta, v[1] + n1[1]*delta}
+		p2 := FloatVertex{v[0] + n2[0]*delta, v[1] + n2[1]*delta}
+		m := len(ret)
+		switch {
+		case cross*delta < 0 || (cross == 0 && 0 < dot) || delta == 0,
+			join == JoinMiter && 0 < 1+dot:
+			k := delta / (1 + dot)
+			ret = append(ret, FloatVertex{v[0] + (n1[0]+n2[0])*k, v[1] + (n1[1]+n2[1])*k})
+		case join == JoinRound:
+			a1 := math.Atan2(n1[1]*delta, n1[0]*delta)
+			sweep := math.Atan2(cross, dot)
+			if cross == 0 {
+				sweep = math.Copysign(math.Pi, delta)
+			}
+			r := math.Abs(delta)
+			step := math.Pi / 2
+			if roundJoinTolerance < r {
+				step = 2 * math.Acos(1-roundJoinTolerance/r)
+			}
+			segs := int(math.Ceil(math.Abs(sweep) / step))
+			ret = append(ret, p1)
+			for j := 1; j < segs; j++ {
+				sin, cos := math.Sincos(a1 + sweep*float64(j)/float64(segs))
+				ret = append(ret, FloatVertex{v[0] + r*cos, v[1] + r*sin})
+			}
+			ret = append(ret, p2)
+		default:
+			ret = append(ret, p1, p2)
+		}
+		// the points for v are connected by the edges of the join, and
+		// the last one to the next point by the edge offset from the
+		// edge vs[i]-vs[i+1]
+		for ; m < len(ret)-1; m++ {
+			src = append(src, -1)
+		}
+		src = append(src, i)
+	}
+	return ret, src
+}
+
+// offsetEpsilon is the tolerance to find the intersections of the edges of the
+// offset paths, and the distance from an edge to the points at which the
+// winding number on either side of it is measured.
+const offsetEpsilon = 1e-7
+
+// resolveOffset resolves the closed path ovs offset from the closed path vs,
+// where src holds the index of the edge of vs from which each edge of ovs is
+// offset, or -1. ovs is removed if all the edges offset from vs run in the
+// opposite direction to the original edges, which means that the whole closed
+// path is turned inside out. Otherwise, the parts of ovs where the winding
+// number of ovs does not have the same sign as the orientation of vs are turned
+// inside out, and removed. It returns ovs itself if it is simple and is not
+// turned inside out, and nil if nothing remains.
+func resolveOffset(vs, ovs []FloatVertex, src []int) [][]FloatVertex {
+	area := floatSignedArea(vs)
+	ovs, src = dedupFloat(ovs, src)
+	if len(ovs) < 3 || area == 0 {
+		return nil
+	}
+	n := len(ovs)
+
+	// a rectilinear closed path turned inside out both horizontally and
+	// vertically keeps its orientation, which is detected by the edges
+	reversed := true
+	for i, o0 := range ovs {
+		if j := src[i]; 0 <= j {
+			o1, v0, v1 := ovs[(i+1)%n], vs[j], vs[(j+1)%len(vs)]
+			if 0 < (o1[0]-o0[0])*(v1[0]-v0[0])+(o1[1]-o0[1])*(v1[1]-v0[1]) {
+				reversed = false
+				break
+			}
+		}
+	}
+	if reversed {
+		return nil
+	}
+
+	splits := make([][]float64, n)
+	simple := true
+	for i := 0; i < n; i++ {
+		for j := i + 1; j < n; j++ {
+			ts, us := edgeSplits(ovs[i], ovs[(i+1)%n], ovs[j], ovs[(j+1)%n])
+			splits[i] = append(splits[i], ts...)
+			splits[j] = append(splits[j], us...)
+			if len(ts) != 0 || len(us) != 0 {
+				simple = false
+			}
+		}
+	}
+	seen := make(map[[2]int64]bool, n)
+	for _, v := range ovs {
+		k := snapKey(v)
+		if seen[k] {
+			simple = false
+		}
+		seen[k] = true
+	}
+	if simple {
+		if 0 < floatSignedArea(ovs)*area {
+			return [][]FloatVertex{ovs}
+		}
+		return nil
+	}
+
+	// split the edges at the intersections, and keep the ones between the
+	// remaining part and the rest, directed as the original closed path
+	sign := 1
+	if area < 0 {
+		sign = -1
+	}
+	inside := func(v FloatVertex) bool { return 1 <= sign*windingNumber(ovs, v) }
+	type edge struct {
+		from, to FloatVertex
+		used     bool
+	}
+	var edges []edge
+	done := make(map[[4]int64]bool)
+	for i, v0 := range ovs {
+		v1 := ovs[(i+1)%n]
+		ts := append(splits[i], 1)
+		sort.Float64s(ts)
+		a := v0
+		for _, t := range ts {
+			b := FloatVertex{v0[0] + (v1[0]-v0[0])*t, v0[1] + (v1[1]-v0[1])*t}
+			ka, kb := snapKey(a), snapKey(b)
+			if ka == kb {
+				continue
+			}
+			k := [4]int64{ka[0], ka[1], kb[0], kb[1]}
+			if kb[0] < ka[0] || kb[0] == ka[0] && kb[1] < ka[1] {
+				k = [4]int64{kb[0], kb[1], ka[0], ka[1]}
+			}
+			if !done[k] {
+				done[k] = true
+				l := math.Hypot(b[0]-a[0], b[1]-a[1])
+				nx, ny := -(b[1]-a[1])/l*offsetEpsilon, (b[0]-a[0])/l*offsetEpsilon
+				mx, my := (a[0]+b[0])/2, (a[1]+b[1])/2
+				in, out := inside(FloatVertex{mx + nx, my + ny}), inside(FloatVertex{mx - nx, my - ny})
+				switch {
+				case in == out:
+				case in == (0 < sign):
+					edges = append(edges, edge{from: a, to: b})
+				default:
+					edges = append(edges, edge{from: b, to: a})
+				}
+			}
+			a = b
+		}
+	}
+
+	outs := make(map[[2]int64][]int, len(edges))
+	for i, e := range edges {
+		k := snapKey(e.from)
+		outs[k] = append(outs[k], i)
+	}
+	var ret [][]FloatVertex
+	for i := range edges {
+		if edges[i].used {
+			continue
+		}
+		edges[i].used = true
+		start := snapKey(edges[i].from)
+		ring := []FloatVertex{edges[i].from}
+		cur := edges[i].to
+		for snapKey(cur) != start {
+			next := -1
+			for _, j := range outs[snapKey(cur)] {
+				if !edges[j].used {
+					next = j
+					break
+				}
+			}
+			if next < 0 {
+				// not closed due to the rounding errors
+				ring = nil
+				break
+			}
+			edges[next].used = true
+			ring = append(ring, cur)
+			cur = edges[next].to
+		}
+		if ring = dropCollinear(ring); 3 <= len(ring) {
+			ret = append(ret, ring)
+		}
+	}
+	return ret
+}
+
+// edgeSplits returns the parameters at which the edge a-b and the edge c-d
+// are split by their intersections, excluding the ends of the edges. The
+// overlapping collinear edges are split at the ends of each other.
+func edgeSplits(a, b, c, d FloatVertex) (ts, us []float64) {
+	r := FloatVertex{b[0] - a[0], b[1] - a[1]}
+	s := FloatVertex{d[0] - c[0], d[1] - c[1]}
+	q := FloatVertex{c[0] - a[0], c[1] - a[1]}
+	cross := func(u, v FloatVertex) float64 { return u[0]*v[1] - u[1]*v[0] }
+	dot := func(u, v FloatVertex) float64 { return u[0]*v[0] + u[1]*v[1] }
+	within := func(t float64) bool { return offsetEpsilon < t && t < 1-offsetEpsilon }
+	rr, ss := dot(r, r), dot(s, s)
+	den := cross(r, s)
+	if math.Abs(den) <= offsetEpsilon*math.Sqrt(rr*ss) {
+		if offsetEpsilon*math.Sqrt(rr) < math.Abs(cross(q, r)/math.Sqrt(rr)) {
+			return nil, nil
+		}
+		for _, t := range [2]float64{dot(q, r) / rr, dot(FloatVertex{d[0] - a[0], d[1] - a[1]}, r) / rr} {
+			if within(t) {
+				ts = append(ts, t)
+			}
+		}
+		for _, u := range [2]float64{-dot(q, s) / ss, dot(FloatVertex{b[0] - c[0], b[1] - c[1]}, s) / ss} {
+			if within(u) {
+				us = append(us, u)
+			}
+		}
+		return ts, us
+	}
+	t, u := cross(q, s)/den, cross(q, r)/den
+	if t < -offsetEpsilon || 1+offsetEpsilon < t || u < -offsetEpsilon || 1+offsetEpsilon < u {
+		return nil, nil
+	}
+	if within(t) {
+		ts = append(ts, t)
+	}
+	if within(u) {
+		us = append(us, u)
+	}
+	return ts, us
+}
+
+// windingNumber returns the winding number of the closed path vs around v,
+// which is positive inside the clockwise closed paths.
+func windingNumber(vs []FloatVertex, v FloatVertex) int {
+	w := 0
+	for i, v0 := range vs {
+		v1 := vs[(i+1)%len(vs)]
+		switch {
+		case v0[1] <= v[1] && v[1] < v1[1]:
+			if v[0] < v0[0]+(v[1]-v0[1])*(v1[0]-v0[0])/(v1[1]-v0[1]) {
+				w++
+			}
+		case v1[1] <= v[1] && v[1] < v0[1]:
+			if v[0] < v0[0]+(v[1]-v0[1])*(v1[0]-v0[0])/(v1[1]-v0[1]) {
+				w--
+			}
+		}
+	}
+	return w
+}
+
+// snapKey returns the key of v to identify the coincident points in spite of
+// the rounding errors.
+func snapKey(v FloatVertex) [2]int64 {
+	return [2]int64{int64(math.Round(v[0] / offsetEpsilon / 10)), int64(math.Round(v[1] / offsetEpsilon / 10))}
+}
+
+// dedupFloat returns the closed path vs without the consecutive coincident
+// vertices, with src of the edges of vs updated accordingly.
+func dedupFloat(vs []FloatVertex, src []int) ([]FloatVertex, []int) {
+	ret, rsrc := make([]FloatVertex, 0, len(vs)), make([]int, 0, len(vs))
+	for i, v := range vs {
+		if len(ret) != 0 && snapKey(ret[len(ret)-1]) == snapKey(v) {
+			// the edge from the vertex removed takes over
+			rsrc[len(rsrc)-1] = src[i]
+			continue
+		}
+		ret, rsrc = append(ret, v), append(rsrc, src[i])
+	}
+	for 1 < len(ret) && snapKey(ret[0]) == snapKey(ret[len(ret)-1]) {
+		ret, rsrc = ret[:len(ret)-1], rsrc[:len(rsrc)-1]
+	}
+	return ret, rsrc
+}
+
+// dropCollinear returns the closed path vs without the vertices in the middle
+// of straight edges.
+func dropCollinear(vs []FloatVertex) []FloatVertex {
+	ret := make([]FloatVertex, 0, len(vs))
+	for i, v := range vs {
+		v0, v1 := vs[(i+len(vs)-1)%len(vs)], vs[(i+1)%len(vs)]
+		dx0, dy0, dx1, dy1 := v[0]-v0[0], v[1]-v0[1], v1[0]-v[0], v1[1]-v[1]
+		if math.Abs(dx0*dy1-dy0*dx1) <= offsetEpsilon*math.Hypot(dx0, dy0)*math.Hypot(dx1, dy1) && 0 < dx0*dx1+dy0*dy1 {
+			continue
+		}
+		ret = append(ret, v)
+	}
+	return ret
+}
@@ -0,0 +1,169 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath_test
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/tunabay/go-bitarray"
+	"github.com/tunabay/go-bmppath"
+)
+
+func TestPath_Offset(t *testing.T) {
+	bmp := bitarray.NewBufferFromBitArray(
+		bitarray.MustParse(strings.Join([]string{
+			"1111",
+			"1001",
+			"1001",
+			"1111",
+		}, "")),
+	)
+	path, err := bmppath.New(bmp, 4)
+	if err != nil {
+		t.Fatalf("New(): %v", err)
+	}
+	tcs := []struct {
+		delta   float64
+		join    bmppath.JoinStyle
+		nPath   int
+		area    float64
+		epsilon float64
+	}{
+		{0.5, bmppath.JoinMiter, 2, 25 - 1, 0},
+		{0.5, bmppath.JoinBevel, 2, 25 - 1 - 4*0.125, 0},
+		{0.5, bmppath.JoinRound, 2, 25 - 1 - 4*(0.25-math.Pi/16), 0.1},
+		{-0.25, bmppath.JoinMiter, 2, 12.25 - 6.25, 0},
+		{1.5, bmppath.JoinMiter, 1, 49, 0},
+	}
+	for _, tc := range tcs {
+		fp := path.Offset(tc.delta, tc.join)
+		if n := fp.NumPath(); n != tc.nPath {
+			t.Errorf("delta=%g, join=%d: unexpected number of paths: got %d, want %d", tc.delta, tc.join, n, tc.nPath)
+			continue
+		}
+		if a := offsetArea(fp); math.Abs(a-tc.area) > tc.epsilon+1e-9 {
+			t.Errorf("delta=%g, join=%d: unexpected area: got %g, want %g", tc.delta, tc.join, a, tc.area)
+		}
+	}
+}
+
+func TestPath_Offset_collapse(t *testing.T) {
+	newPath := func(width int, rows ...string) *bmppath.Path {
+		p, err := bmppath.New(bitarray.NewBufferFromBitArray(bitarray.MustParse(strings.Join(rows, ""))), width)
+		if err != nil {
+			t.Fatalf("New(): %v", err)
+		}
+		return p
+	}
+	bar := newPath(10, "1111111111", "1111111111")
+	// the prongs vanish by shrinking 1.2, while the base survives
+	u := newPath(7,
+		"1100011",
+		"1100011",
+		"1100011",
+		"1100011",
+		"1111111",
+		"1111111",
+		"1111111",
+	)
+	// the tab is turned inside out, overlapping the part of the square
+	// that also vanishes
+	tab := newPath(6,
+		"001100",
+		"001100",
+		"111111",
+		"111111",
+		"111111",
+		"111111",
+		"111111",
+		"111111",
+	)
+	// the notch below the tab leaves a step
+	notched := newPath(7,
+		"0110000",
+		"0110000",
+		"0111111",
+		"1111111",
+		"1111111",
+		"1111111",
+		"1111111",
+		"1111111",
+	)
+
+	tcs := []struct {
+		path   *bmppath.Path
+		delta  float64
+		join   bmppath.JoinStyle
+		nPath  int
+		area   float64    // NaN to skip
+		bounds [4]float64 // minX, minY, maxX, maxY
+	}{
+		{bar, -1.5, bmppath.JoinMiter, 0, 0, [4]float64{}},
+		{bar, -1.5, bmppath.JoinBevel, 0, 0, [4]float64{}},
+		{bar, -1.5, bmppath.JoinRound, 0, 0, [4]float64{}},
+		{bar, -1, bmppath.JoinMiter, 0, 0, [4]float64{}},
+		{bar, -0.5, bmppath.JoinMiter, 1, 9, [4]float64{0.5, 0.5, 9.5, 1.5}},
+		{u, -1.2, bmppath.JoinMiter, 1, 4.6 * 0.6, [4]float64{1.2, 5.2, 5.8, 5.8}},
+		{u, -1.2, bmppath.JoinBevel, 1, 3.4, [4]float64{1.2, 4.4, 5.8, 5.8}},
+		{u, -1.2, bmppath.JoinRound, 1, math.NaN(), [4]float64{1.2, 4.4, 5.8, 5.8}},
+		{u, -0.5, bmppath.JoinMiter, 1, 36 - 16, [4]float64{0.5, 0.5, 6.5, 6.5}},
+		{tab, -1.5, bmppath.JoinMiter, 1, 9, [4]float64{1.5, 3.5, 4.5, 6.5}},
+		{notched, -1.5, bmppath.JoinMiter, 1, 9 + 2, [4]float64{1.5, 3.5, 5.5, 6.5}},
+	}
+	for i, tc := range tcs {
+		fp := tc.path.Offset(tc.delta, tc.join)
+		if n := fp.NumPath(); n != tc.nPath {
+			t.Errorf("#%d: unexpected number of paths: got %d, want %d: %v", i, n, tc.nPath, fp.Vertices)
+			continue
+		}
+		if a := offsetArea(fp); !math.IsNaN(tc.area) && math.Abs(a-tc.area) > 1e-6 {
+			t.Errorf("#%d: unexpected area: got %g, want %g: %v", i, a, tc.area, fp.Vertices)
+		}
+		for _, vs := range fp.Vertices {
+			for _, v := range vs {
+				if v.X() < tc.bounds[0]-1e-6 || v.Y() < tc.bounds[1]-1e-6 || tc.bounds[2]+1e-6 < v.X() || tc.bounds[3]+1e-6 < v.Y() {
+					t.Errorf("#%d: vertex %v out of bounds %v", i, v, tc.bounds)
+				}
+			}
+		}
+		if tc.nPath == 0 {
+			if ip := fp.Path(); ip.NumPath() != 0 {
+				t.Errorf("#%d: unexpected ink: %v", i, ip.Vertices)
+			}
+		}
+	}
+}
+
+// offsetArea returns the total signed area of the closed paths of fp.
+func offsetArea(fp *bmppath.FloatPath) float64 {
+	a := 0.0
+	for _, vs := range fp.Vertices {
+		for i, v0 := range vs {
+			v1 := vs[(i+1)%len(vs)]
+			a += (v0.X()*v1.Y() - v1.X()*v0.Y()) / 2
+		}
+	}
+	return a
+}
+
+func ExamplePath_Offset() {
+	bmp := bitarray.NewBufferFromBitArray(bitarray.MustParse("1111"))
+	path, err := bmppath.New(bmp, 2)
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Println(path.Offset(0.5, bmppath.JoinMiter).PathString(0))
+	fmt.Println(path.Offset(0.5, bmppath.JoinBevel).PathString(0))
+	fmt.Println(path.Offset(-1, bmppath.JoinMiter).NumPath())
+
+	// Output:
+	// (-0.5, -0.5), (2.5, -0.5), (2.5, 2.5), (-0.5, 2.5)
+	// (-0.5, 0), (0, -0.5), (2, -0.5), (2.5, 0), (2.5, 2), (2, 2.5), (0, 2.5), (-0.5, 2)
+	// 0
+}
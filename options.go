@@ -0,0 +1,189 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath
+
+import (
+	"image"
+
+	"github.com/tunabay/go-bitarray"
+)
+
+// Options represents the options for NewWithOptions and NewFromImage. The zero
+// value is the same as New, which traces the bitmap image as is.
+type Options struct {
+	// Binarization is the method to convert the grayscale image given to
+	// NewFromImage into a bitmap image. It is not used by NewWithOptions.
+	Binarization Binarization
+
+	// Threshold is the gray level cutoff for BinarizeFixed. The pixels
+	// darker than Threshold become ink. Zero means the default value 128.
+	Threshold uint8
+
+	// BlackPoint and WhitePoint are the input levels applied to the
+	// grayscale image given to NewFromImage before the binarization. The
+	// gray levels at or below BlackPoint become black, those at or above
+	// WhitePoint become white, and those in between are stretched linearly.
+	// Raising BlackPoint darkens the faint ink of a scan that is too light.
+	// Zero WhitePoint means the default value 255.
+	BlackPoint, WhitePoint uint8
+
+	// Gamma is the gamma correction applied after the input levels. The
+	// values less than 1 darken the midtones, and the values greater than 1
+	// lighten them. Zero means the default value 1.
+	Gamma float64
+
+	// Polarity specifies which value of the pixels of the bitmap image is
+	// ink. It is applied before the other preprocessing. For NewFromImage,
+	// it is applied to the binarized image, where the dark pixels are 1.
+	Polarity Polarity
+
+	// Deskew enables the deskew preprocessing. The dominant skew angle of the
+	// bitmap image is estimated with the projection profile method, and the
+	// image is rotated back by that angle before it is traced.
+	Deskew bool
+
+	// DeskewMaxAngle is the maximum skew angle in degrees that Deskew
+	// detects. Zero means the default value 5.
+	DeskewMaxAngle float64
+
+	// Despeckle is the maximum area in pixels of the specks to be removed.
+	// The isolated ink components and the holes of this size or smaller are
+	// erased. Zero disables it.
+	Despeckle int
+
+	// CloseGaps is the radius in pixels of the morphological closing, which
+	// bridges the small gaps and fills the narrow notches of the ink. Zero
+	// disables it.
+	CloseGaps int
+
+	// Smooth is the number of times to apply the 3x3 majority filter, which
+	// rounds off the jagged edges of the ink. Zero disables it.
+	Smooth int
+
+	// Pipeline is the sequence of the additional preprocessing Stages, such
+	// as the custom cleanup steps, applied after the preprocessing specified
+	// by the other fields.
+	Pipeline Pipeline
+
+	// Ordering is the strategy to order the closed paths to minimize the
+	// pen-up travel of plotters between them.
+	Ordering Ordering
+
+	// FlexibleStart starts each closed path at the vertex nearest to the
+	// end of the previous closed path, instead of the vertex nearest to the
+	// origin, to further reduce the pen-up travel.
+	FlexibleStart bool
+
+	// Visit, if not nil, is called with the vertices of each closed path as
+	// soon as it is traced, before the closed paths touching each other are
+	// merged and the closed paths are sorted. This allows the streaming
+	// consumers to process the closed paths without waiting for the whole
+	// image to be traced. If Visit returns false, the tracing is stopped and
+	// ErrStopped is returned.
+	Visit func(vs []Vertex) bool
+
+	// Partial makes NewWithOptions return the best-effort partial result
+	// instead of nil when the tracing is stopped by Visit, such as on the
+	// cancellation by the user, on reaching a limit of the number of the
+	// closed paths, or on a write failure of the streaming consumer. The
+	// returned Path holds the closed paths accepted by Visit so far, merged
+	// and sorted as usual, and is flagged Incomplete. ErrStopped is still
+	// returned with it. This is useful for the preview-oriented UIs.
+	Partial bool
+
+	// Workers is the number of the goroutines to trace the bitmap image
+	// concurrently. The large images are split into the horizontal
+	// bands traced in parallel, and the results are combined in the same
+	// order as the single goroutine produces, so the result does not depend
+	// on Workers. Visit is still called from the calling goroutine, in the
+	// same order. Zero or a negative value means runtime.GOMAXPROCS(0), and
+	// 1 disables the concurrency.
+	Workers int
+
+	// Warn, if not nil, is called with each non-fatal issue encountered
+	// during the preprocessing and the tracing, such as the specks erased
+	// by Despeckle, the closed paths touching each other and merged, and
+	// the ink touching the border of the canvas, so that the pipelines can
+	// log the quality concerns without failing. The Path field of the
+	// Warning is always -1, since the closed paths are not yet sorted.
+	Warn func(w Warning)
+}
+
+// Scan returns the preset Options suitable for scanned images. It straightens
+// the image, erases the specks, bridges the small gaps, and slightly smooths
+// the edges before tracing.
+func Scan() *Options {
+	return &Options{
+		Deskew:    true,
+		Despeckle: 4,
+		CloseGaps: 1,
+		Smooth:    1,
+	}
+}
+
+// PixelArt returns the preset Options suitable for pixel art and other
+// computer-generated images. It performs no preprocessing, so that every pixel
+// and hard corner of the image is preserved.
+func PixelArt() *Options {
+	return &Options{}
+}
+
+// prepare applies the preprocessing specified by opts including the Pipeline to
+// the bitmap image bm of the size width x height, and returns the result and
+// its size.
+func (opts *Options) prepare(bm *bitarray.Buffer, width, height int) (*bitarray.Buffer, int, int, error) {
+	bm = opts.preprocess(bm, width, height)
+	if len(opts.Pipeline) == 0 {
+		return bm, width, height, nil
+	}
+	bm, width, err := opts.Pipeline.Apply(bm, width)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	return bm, width, bm.Len() / width, nil
+}
+
+// preprocess applies the preprocessing specified by opts to the bitmap image
+// bm of the size width x height, and returns the result.
+func (opts *Options) preprocess(bm *bitarray.Buffer, width, height int) *bitarray.Buffer {
+	if opts.Polarity == PolarityNormal && !opts.Deskew && opts.Despeckle < 1 && opts.CloseGaps < 1 && opts.Smooth < 1 {
+		return bm
+	}
+	img := newBitmap(bm, width, height)
+	if opts.Polarity.inverted(img) {
+		img = img.invert()
+	}
+	if opts.Deskew {
+		maxAngle := opts.DeskewMaxAngle
+		if maxAngle <= 0 {
+			maxAngle = 5
+		}
+		img = img.deskew(maxAngle)
+	}
+	if 0 < opts.Despeckle {
+		var removed func([]int, bool)
+		if opts.Warn != nil {
+			removed = func(comp []int, ink bool) {
+				r := image.Rectangle{}
+				for _, i := range comp {
+					r = r.Union(image.Rect(i%width, i/width, i%width+1, i/width+1))
+				}
+				what := "ink speck"
+				if !ink {
+					what = "hole"
+				}
+				opts.warn(WarnSpeckle, r, "%s removed, area %d", what, len(comp))
+			}
+		}
+		img = img.despeckle(opts.Despeckle, removed)
+	}
+	if 0 < opts.CloseGaps {
+		img = img.closeGaps(opts.CloseGaps)
+	}
+	if 0 < opts.Smooth {
+		img = img.smooth(opts.Smooth)
+	}
+	return img.buffer()
+}
@@ -0,0 +1,116 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath_test
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/tunabay/go-bitarray"
+	"github.com/tunabay/go-bmppath"
+)
+
+func ExampleScan() {
+	bmp := bitarray.NewBufferFromBitArray(
+		bitarray.MustParse(strings.Join([]string{
+			"1000000000",
+			"0000000000",
+			"0011101100",
+			"0011111100",
+			"0011011100",
+			"0011111100",
+			"0000000000",
+			"0000000010",
+		}, "")),
+	)
+
+	for _, opts := range []*bmppath.Options{bmppath.PixelArt(), bmppath.Scan()} {
+		path, err := bmppath.NewWithOptions(bmp, 10, opts)
+		if err != nil {
+			panic(err)
+		}
+		fmt.Println(path.SVGDString())
+	}
+
+	// Output:
+	// m0,0h1v1h-1zm2,2h3v1h1v-1h2v4h-6zm2,2v1h1v-1zm4,3h1v1h-1z
+	// m3,2h4v1h1v2h-1v1h-4v-1h-1v-2h1z
+}
+
+func ExampleOptions() {
+	bmp := bitarray.NewBufferFromBitArray(
+		bitarray.MustParse(strings.Join([]string{
+			"11111111",
+			"11111111",
+			"00000000",
+			"11111111",
+			"11111111",
+		}, "")),
+	)
+
+	path, err := bmppath.NewWithOptions(bmp, 8, &bmppath.Options{CloseGaps: 1})
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(path.SVGDString())
+
+	// Output:
+	// m0,0h8v5h-8z
+}
+
+func ExampleOptions_visit() {
+	bmp := bitarray.NewBufferFromBitArray(
+		bitarray.MustParse(strings.Join([]string{
+			"11000",
+			"11011",
+			"00011",
+			"11000",
+		}, "")),
+	)
+
+	opts := &bmppath.Options{
+		Visit: func(vs []bmppath.Vertex) bool {
+			fmt.Println("found:", vs)
+			return vs[0].Y() < 2
+		},
+	}
+	_, err := bmppath.NewWithOptions(bmp, 5, opts)
+	fmt.Println(err)
+
+	// Output:
+	// found: [(0, 0) (2, 0) (2, 2) (0, 2)]
+	// found: [(3, 1) (5, 1) (5, 3) (3, 3)]
+	// found: [(0, 3) (2, 3) (2, 4) (0, 4)]
+	// tracing stopped
+}
+
+func ExampleOptions_partial() {
+	bmp := bitarray.NewBufferFromBitArray(
+		bitarray.MustParse(strings.Join([]string{
+			"11000",
+			"11011",
+			"00011",
+			"11000",
+		}, "")),
+	)
+
+	n := 0
+	opts := &bmppath.Options{
+		Visit: func(vs []bmppath.Vertex) bool {
+			n++
+			return n <= 2 // at most 2 closed paths
+		},
+		Partial: true,
+	}
+	path, err := bmppath.NewWithOptions(bmp, 5, opts)
+	fmt.Println(err)
+	fmt.Println(path.Incomplete)
+	fmt.Println(path.SVGDString())
+
+	// Output:
+	// tracing stopped
+	// true
+	// m0,0h2v2h-2zm3,1h2v2h-2z
+}
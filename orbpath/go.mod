@@ -0,0 +1,11 @@
+module github.com/tunabay/go-bmppath/orbpath
+
+go 1.17
+
+require (
+	github.com/paulmach/orb v0.11.1
+	github.com/tunabay/go-bitarray v1.3.1
+	github.com/tunabay/go-bmppath v0.0.0-00010101000000-000000000000
+)
+
+replace github.com/tunabay/go-bmppath => ../
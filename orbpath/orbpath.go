@@ -0,0 +1,110 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+// Package orbpath converts the paths of bmppath to and from the geometry types
+// of github.com/paulmach/orb, so that the geometric algorithms of orb can be
+// applied to the traced images and the results can be brought back for the
+// export.
+//
+// The coordinates are the same as the pixel coordinates of bmppath, so the
+// y-axis points downward. Since the outlines of bmppath run clockwise on the
+// screen, they are counterclockwise in the mathematical sense that orb uses,
+// which matches the convention of the exterior rings of GeoJSON.
+package orbpath
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/paulmach/orb"
+	"github.com/tunabay/go-bmppath"
+)
+
+// ErrUnsupportedType is returned when the geometry type is not supported.
+var ErrUnsupportedType = errors.New("unsupported geometry type")
+
+// MultiPolygon converts p to an orb.MultiPolygon. Each outline becomes the
+// exterior ring of a polygon, with the holes directly inside it as the interior
+// rings. The islands inside the holes become the separate polygons. The rings
+// are closed, that is, the last point is the same as the first one.
+func MultiPolygon(p *bmppath.Path) orb.MultiPolygon {
+	var mp orb.MultiPolygon
+	var walk func(outlines []*bmppath.Node)
+	walk = func(outlines []*bmppath.Node) {
+		for _, o := range outlines {
+			poly := orb.Polygon{ring(p.Vertices[o.Index])}
+			for _, h := range o.Children {
+				poly = append(poly, ring(p.Vertices[h.Index]))
+			}
+			mp = append(mp, poly)
+			for _, h := range o.Children {
+				walk(h.Children)
+			}
+		}
+	}
+	walk(p.Hierarchy().Children)
+	return mp
+}
+
+// FromGeometry converts g, which must be an orb.MultiPolygon, orb.Polygon, or
+// orb.Ring, back to a bmppath.FloatPath on the canvas of the size width and
+// height. The rings may or may not be closed. The exterior rings are oriented
+// as the outlines and the interior rings as the holes, regardless of their
+// orientation in g. Use the Path method of the result to get the paths on the
+// pixel grid. ErrUnsupportedType is returned for the other geometry types.
+func FromGeometry(g orb.Geometry, width, height float64) (*bmppath.FloatPath, error) {
+	var mp orb.MultiPolygon
+	switch g := g.(type) {
+	case orb.MultiPolygon:
+		mp = g
+	case orb.Polygon:
+		mp = orb.MultiPolygon{g}
+	case orb.Ring:
+		mp = orb.MultiPolygon{{g}}
+	default:
+		return nil, fmt.Errorf("%w: %T", ErrUnsupportedType, g)
+	}
+	ret := &bmppath.FloatPath{Width: width, Height: height}
+	for _, poly := range mp {
+		for i, r := range poly {
+			if vs := floatVertices(r, i == 0); len(vs) != 0 {
+				ret.Vertices = append(ret.Vertices, vs)
+			}
+		}
+	}
+	return ret, nil
+}
+
+// ring converts the vertices vs to a closed orb.Ring.
+func ring(vs []bmppath.Vertex) orb.Ring {
+	r := make(orb.Ring, 0, len(vs)+1)
+	for _, v := range vs {
+		r = append(r, orb.Point{float64(v.X()), float64(v.Y())})
+	}
+	if len(vs) != 0 {
+		r = append(r, r[0])
+	}
+	return r
+}
+
+// floatVertices converts the ring r to the vertices of a closed path, dropping
+// the closing point, oriented as an outline if outer is set or as a hole.
+func floatVertices(r orb.Ring, outer bool) []bmppath.FloatVertex {
+	if 1 < len(r) && r[0] == r[len(r)-1] {
+		r = r[:len(r)-1]
+	}
+	vs := make([]bmppath.FloatVertex, len(r))
+	var area float64
+	for i, pt := range r {
+		vs[i] = bmppath.FloatVertex{pt[0], pt[1]}
+		next := r[(i+1)%len(r)]
+		area += pt[0]*next[1] - next[0]*pt[1]
+	}
+	if (0 < area) != outer && area != 0 {
+		for i, j := 0, len(vs)-1; i < j; i, j = i+1, j-1 {
+			vs[i], vs[j] = vs[j], vs[i]
+		}
+	}
+	return vs
+}
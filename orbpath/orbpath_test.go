@@ -0,0 +1,84 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package orbpath_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/paulmach/orb"
+	"github.com/tunabay/go-bitarray"
+	"github.com/tunabay/go-bmppath"
+	"github.com/tunabay/go-bmppath/orbpath"
+)
+
+func ExampleMultiPolygon() {
+	bmp := bitarray.NewBufferFromBitArray(bitarray.MustParse(strings.Join([]string{
+		"1110",
+		"1010",
+		"1110",
+	}, "")))
+	path, err := bmppath.New(bmp, 4)
+	if err != nil {
+		panic(err)
+	}
+
+	mp := orbpath.MultiPolygon(path)
+	for _, poly := range mp {
+		for _, r := range poly {
+			fmt.Println(r, r.Orientation() == orb.CCW)
+		}
+	}
+
+	// Output:
+	// [[0 0] [3 0] [3 3] [0 3] [0 0]] true
+	// [[1 1] [1 2] [2 2] [2 1] [1 1]] false
+}
+
+func TestFromGeometry(t *testing.T) {
+	bmp := bitarray.NewBufferFromBitArray(bitarray.MustParse(strings.Join([]string{
+		"1111111",
+		"1000001",
+		"1011101",
+		"1010101",
+		"1011101",
+		"1000001",
+		"1111111",
+	}, "")))
+	path, err := bmppath.New(bmp, 7)
+	if err != nil {
+		t.Fatalf("New(): %v", err)
+	}
+	mp := orbpath.MultiPolygon(path)
+	if len(mp) != 2 || len(mp[0]) != 2 || len(mp[1]) != 2 {
+		t.Fatalf("unexpected structure: %v", mp)
+	}
+	fp, err := orbpath.FromGeometry(mp, 7, 7)
+	if err != nil {
+		t.Fatalf("FromGeometry(): %v", err)
+	}
+	if !fp.Rasterize().BitArray().Equal(bmp.BitArray()) {
+		t.Errorf("unexpected result: %v", fp.Vertices)
+	}
+
+	// the orientation of the rings is not significant
+	for _, poly := range mp {
+		for _, r := range poly {
+			r.Reverse()
+		}
+	}
+	if fp, err = orbpath.FromGeometry(mp, 7, 7); err != nil {
+		t.Fatalf("FromGeometry(): %v", err)
+	}
+	if !fp.Path().Equal(path) {
+		t.Errorf("unexpected result: %v", fp.Vertices)
+	}
+
+	if _, err := orbpath.FromGeometry(orb.Point{1, 2}, 7, 7); !errors.Is(err, orbpath.ErrUnsupportedType) {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
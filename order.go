@@ -0,0 +1,140 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath
+
+import (
+	"math"
+)
+
+// Ordering specifies the strategy to order the closed paths.
+type Ordering int
+
+const (
+	// OrderNearest repeatedly chooses the closed path starting nearest to
+	// the end of the previous one, starting from the origin. This is the
+	// default.
+	OrderNearest Ordering = iota
+
+	// OrderNone does not reorder the closed paths for the pen travel.
+	OrderNone
+
+	// OrderTwoOpt improves the result of OrderNearest with the 2-opt
+	// heuristic, which repeatedly reverses the sub-sequences of the closed
+	// paths while it shortens the total pen-up travel. It is slower but
+	// produces better tours for plotters.
+	OrderTwoOpt
+)
+
+// Reorder returns a new Path with the closed paths reordered by the strategy o.
+// If flexibleStart is set, each closed path is also rotated to start at the
+// vertex nearest to the start of the previous closed path, which is also its
+// end since the paths are closed. p itself is not modified.
+func (p *Path) Reorder(o Ordering, flexibleStart bool) *Path {
+	ret := &Path{
+		Width:    p.Width,
+		Height:   p.Height,
+		Vertices: make([][]Vertex, len(p.Vertices)),
+	}
+	perm, starts := orderPaths(p.Vertices, o, flexibleStart)
+	for i, n := range perm {
+		vs := p.Vertices[n]
+		ret.Vertices[i] = append(append(make([]Vertex, 0, len(vs)), vs[starts[i]:]...), vs[:starts[i]]...)
+		if p.Labels != nil {
+			ret.Labels = append(ret.Labels, p.PathLabel(n))
+		}
+	}
+	return ret
+}
+
+// orderPaths determines the order of the closed paths vss by the strategy o.
+// It returns the indexes of vss in the new order, and the index of the starting
+// vertex of each of them.
+func orderPaths(vss [][]Vertex, o Ordering, flexibleStart bool) ([]int, []int) {
+	perm := make([]int, len(vss))
+	starts := make([]int, len(vss))
+	for i := range perm {
+		perm[i] = i
+	}
+	pt := func(i int) Vertex { return vss[perm[i]][starts[i]] }
+
+	// nearest finds the vertex of vss[perm[i]] nearest to cur.
+	nearest := func(i int, cur Vertex) (int64, int) {
+		var mind int64
+		mink := -1
+		for k, v := range vss[perm[i]] {
+			if !flexibleStart && k != 0 {
+				break
+			}
+			if d := sqDist(v[0]-cur[0], v[1]-cur[1]); mink == -1 || d < mind {
+				mind, mink = d, k
+			}
+		}
+		return mind, mink
+	}
+
+	if o != OrderNone {
+		var cur Vertex
+		for i := range perm {
+			var mind int64
+			minj, mink := -1, 0
+			for j := i; j < len(perm); j++ {
+				if d, k := nearest(j, cur); minj == -1 || d < mind {
+					mind, minj, mink = d, j, k
+				}
+			}
+			perm[i], perm[minj] = perm[minj], perm[i]
+			starts[i] = mink
+			cur = pt(i)
+		}
+	}
+	if o == OrderTwoOpt {
+		twoOpt(len(perm), func(i int) Vertex {
+			if i < 0 {
+				return Vertex{}
+			}
+			return pt(i)
+		}, func(i, j int) {
+			for ; i < j; i, j = i+1, j-1 {
+				perm[i], perm[j] = perm[j], perm[i]
+				starts[i], starts[j] = starts[j], starts[i]
+			}
+		})
+	}
+	if flexibleStart && o != OrderNearest {
+		var cur Vertex
+		for i := range perm {
+			_, starts[i] = nearest(i, cur)
+			cur = pt(i)
+		}
+	}
+	return perm, starts
+}
+
+// twoOpt improves the order of the n points with the 2-opt heuristic, which
+// repeatedly reverses a sub-sequence while it shortens the tour. pt returns the
+// i-th point, or the origin for -1, and rev reverses the sub-sequence [i, j].
+// The tour starts from the origin and does not return.
+func twoOpt(n int, pt func(i int) Vertex, rev func(i, j int)) {
+	dist := func(a, b Vertex) float64 {
+		return math.Hypot(float64(a[0]-b[0]), float64(a[1]-b[1]))
+	}
+	for improved := true; improved; {
+		improved = false
+		for i := 0; i < n-1; i++ {
+			for j := i + 1; j < n; j++ {
+				d0 := dist(pt(i-1), pt(i))
+				d1 := dist(pt(i-1), pt(j))
+				if j+1 < n {
+					d0 += dist(pt(j), pt(j+1))
+					d1 += dist(pt(i), pt(j+1))
+				}
+				if d1 < d0-1e-9 {
+					rev(i, j)
+					improved = true
+				}
+			}
+		}
+	}
+}
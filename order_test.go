@@ -0,0 +1,67 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath_test
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/tunabay/go-bitarray"
+	"github.com/tunabay/go-bmppath"
+)
+
+func travel(p *bmppath.Path) float64 {
+	var cur bmppath.Vertex
+	d := 0.0
+	for _, vs := range p.Vertices {
+		d += math.Hypot(float64(vs[0].X()-cur.X()), float64(vs[0].Y()-cur.Y()))
+		cur = vs[0]
+	}
+	return d
+}
+
+func TestPath_Reorder(t *testing.T) {
+	bmp := bitarray.NewBufferFromBitArray(
+		bitarray.MustParse(strings.Join([]string{
+			"1000000001",
+			"0000000000",
+			"0001001000",
+			"0000000000",
+			"1000000001",
+		}, "")),
+	)
+	path, err := bmppath.New(bmp, 10)
+	if err != nil {
+		t.Fatalf("New(): %v", err)
+	}
+	if n := path.NumPath(); n != 6 {
+		t.Fatalf("unexpected number of paths: got %d, want 6", n)
+	}
+	nearest := travel(path)
+	for _, o := range []bmppath.Ordering{bmppath.OrderNearest, bmppath.OrderTwoOpt} {
+		for _, flex := range []bool{false, true} {
+			q := path.Reorder(o, flex)
+			if !q.Equal(path) {
+				t.Errorf("Reorder(%d, %t) changed the shape:\n%s", o, flex, bmppath.Diff(path, q))
+			}
+			if d := travel(q); nearest+1e-9 < d {
+				t.Errorf("Reorder(%d, %t): travel %g exceeds %g", o, flex, d, nearest)
+			}
+		}
+	}
+
+	opts := &bmppath.Options{Ordering: bmppath.OrderTwoOpt, FlexibleStart: true}
+	traced, err := bmppath.NewWithOptions(bmp, 10, opts)
+	if err != nil {
+		t.Fatalf("NewWithOptions(): %v", err)
+	}
+	if !traced.Equal(path) {
+		t.Errorf("unexpected result:\n%s", bmppath.Diff(path, traced))
+	}
+	if d := travel(traced); nearest+1e-9 < d {
+		t.Errorf("NewWithOptions(): travel %g exceeds %g", d, nearest)
+	}
+}
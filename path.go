@@ -7,7 +7,9 @@ package bmppath
 import (
 	"errors"
 	"fmt"
+	"image"
 	"io"
+	"math/bits"
 	"sort"
 	"strings"
 
@@ -20,6 +22,13 @@ var ErrInvalidWidth = errors.New("invalid width")
 // ErrInvalidBitmap is the error thrown when the source bitmap data is invalid.
 var ErrInvalidBitmap = errors.New("invalid bitmap")
 
+// ErrStopped is the error thrown when the tracing is stopped by Options.Visit.
+var ErrStopped = errors.New("tracing stopped")
+
+// ErrTooLarge is the error thrown when the size of the image or the path to
+// create exceeds the range of int.
+var ErrTooLarge = errors.New("too large")
+
 // Vertex represents the coordinate of one of the vertices that make up the
 // polyline path.
 type Vertex [2]int
@@ -37,6 +46,16 @@ func (v Vertex) Y() int { return v[1] }
 type Path struct {
 	Width, Height int
 	Vertices      [][]Vertex
+
+	// Labels holds the optional names of the closed paths, in the same order
+	// as Vertices. nil means that no closed path is labeled, and an empty
+	// string means that the corresponding closed path is not labeled.
+	Labels []string
+
+	// Incomplete reports whether the tracing was stopped before the whole
+	// image was traced, so that the Path holds only the closed paths traced
+	// so far. It is set only by NewWithOptions with Options.Partial.
+	Incomplete bool
 }
 
 // NumPath returns the number of closed paths in this set of paths.
@@ -82,6 +101,21 @@ func (p *Path) WriteSVGD(w io.Writer) error {
 	return nil
 }
 
+// WriteSVGDAbsolute is identical to WriteSVGD except that the 'd' string is
+// written with the absolute commands M, H, V, and Z, such as "M1,0H3V1H1Z",
+// which some parsers and the diff-based workflows handle better, since each
+// coordinate does not depend on the preceding ones.
+func (p *Path) WriteSVGDAbsolute(w io.Writer) error {
+	return p.Float().WriteSVGDAbsolute(w, nil)
+}
+
+// WriteSVGDOptions is identical to WriteSVGD except that the coordinates are
+// scaled and rounded as specified by opts, as FloatPath.WriteSVGDOptions does.
+// nil opts is the same as the zero value of SVGDOptions.
+func (p *Path) WriteSVGDOptions(w io.Writer, opts *SVGDOptions) error {
+	return p.Float().WriteSVGDOptions(w, opts)
+}
+
 // WriteSVG writes the vectorized bitmap image as an SVG document. It is
 // recommended to use WriteSVGD instead to write customized SVG documents.
 func (p *Path) WriteSVG(w io.Writer) error {
@@ -142,6 +176,10 @@ func pathSVGD(w io.Writer, p []Vertex, z Vertex) error {
 type vertex struct {
 	x, y       int
 	prev, next *vertex
+
+	// order increases along the closed path from its head, which is used
+	// by merge to find the first one of the vertices without traversing
+	order uint64
 }
 
 func (v *vertex) ins(chead *vertex) {
@@ -151,49 +189,88 @@ func (v *vertex) ins(chead *vertex) {
 	v.prev = ctail
 }
 
+// slabSize is the number of the vertices or the paths in each slab of arena.
+const slabSize = 1024
+
+// arena allocates the vertices and the paths from the slabs of slabSize of
+// them rather than one by one, which reduces the allocations on the large
+// images by orders of magnitude. The slabs are reused after reset.
+type arena struct {
+	vertices [][]vertex
+	paths    [][]path
+	nv, np   int
+}
+
+// vertex returns a new vertex at (x, y).
+func (a *arena) vertex(x, y int) *vertex {
+	i, j := a.nv/slabSize, a.nv%slabSize
+	if i == len(a.vertices) {
+		a.vertices = append(a.vertices, make([]vertex, slabSize))
+	}
+	a.nv++
+	v := &a.vertices[i][j]
+	*v = vertex{x: x, y: y}
+	return v
+}
+
+// path returns a new empty path.
+func (a *arena) path() *path {
+	i, j := a.np/slabSize, a.np%slabSize
+	if i == len(a.paths) {
+		a.paths = append(a.paths, make([]path, slabSize))
+	}
+	a.np++
+	p := &a.paths[i][j]
+	*p = path{arena: a}
+	return p
+}
+
+// reset makes all the slabs available again. The vertices and the paths
+// allocated before must not be used after this.
+func (a *arena) reset() { a.nv, a.np = 0, 0 }
+
 type path struct {
 	head, tail *vertex
 	nVertices  int
 	deleted    bool
+	arena      *arena
 }
 
-// dist finds the closest pair of vertices on the two paths p0 and p1, and
-// returns the distance between them and the pair of vertices. If p0 and p1
-// touch at a vertex, the distance 0 and that vertex are returned.
-func dist(p0, p1 *path) (int, *vertex, *vertex) {
-	mind := 0
-	var nv0, nv1 *vertex
-	for v0 := p0.head; ; {
-		for v1 := p1.head; ; {
-			dx, dy := v1.x-v0.x, v1.y-v0.y
-			d := dx*dx + dy*dy
-			if nv0 == nil || d < mind {
-				if d == 0 {
-					return 0, v0, v1
-				}
-				mind, nv0, nv1 = d, v0, v1
-			}
-			v1 = v1.next
-			if v1 == p1.head {
-				break
-			}
-		}
-		if v0 = v0.next; v0 == p0.head {
-			break
-		}
-	}
+// sqDist returns the square of the distance (dx, dy). It is computed in int64
+// so as not to overflow on the 32-bit platforms.
+func sqDist(dx, dy int) int64 {
+	return int64(dx)*int64(dx) + int64(dy)*int64(dy)
+}
 
-	return mind, nv0, nv1
+// addInt returns a + b, and reports whether the result fits in int.
+func addInt(a, b int) (int, bool) {
+	c := a + b
+	return c, (0 <= b) == (a <= c)
 }
 
-func newPath(v Vertex) *path {
-	v0 := &vertex{x: v[0], y: v[1]}
-	return &path{head: v0, tail: v0, nVertices: 1}
+// mulInt returns a * b for the non-negative integers a and b, and reports
+// whether the result fits in int.
+func mulInt(a, b int) (int, bool) {
+	if a != 0 && int(^uint(0)>>1)/a < b {
+		return 0, false
+	}
+	return a * b, true
+}
+
+// newPath creates a path starting at v, which is allocated from a with its
+// vertices.
+func newPath(a *arena, v Vertex) *path {
+	p := a.path()
+	p.head = a.vertex(v[0], v[1])
+	p.tail, p.nVertices = p.head, 1
+	return p
 }
 
 func (p *path) addVertex(x, y int) {
-	p.tail.next = &vertex{x: x, y: y, prev: p.tail}
-	p.tail = p.tail.next
+	v := p.arena.vertex(x, y)
+	v.prev = p.tail
+	p.tail.next = v
+	p.tail = v
 	p.nVertices++
 }
 
@@ -203,11 +280,11 @@ func (p *path) close() {
 }
 
 func (p *path) normalize() {
-	mind := 0
+	var mind int64
 	var minv *vertex
 	v := p.head
 	for {
-		d := v.x*v.x + v.y*v.y
+		d := sqDist(v.x, v.y)
 		if minv == nil || d < mind {
 			mind, minv = d, v
 			if d == 0 {
@@ -222,8 +299,40 @@ func (p *path) normalize() {
 	p.head, p.tail = minv, minv.prev
 }
 
+// bounds returns the bounding box of the vertices of p, the same as
+// vertexBounds returns for p.pub() without allocating the vertices.
+func (p *path) bounds() image.Rectangle {
+	r := image.Rectangle{Min: image.Pt(p.head.x, p.head.y), Max: image.Pt(p.head.x, p.head.y)}
+	for v := p.head.next; v != p.head; v = v.next {
+		switch {
+		case v.x < r.Min.X:
+			r.Min.X = v.x
+		case r.Max.X < v.x:
+			r.Max.X = v.x
+		}
+		switch {
+		case v.y < r.Min.Y:
+			r.Min.Y = v.y
+		case r.Max.Y < v.y:
+			r.Max.Y = v.y
+		}
+	}
+	return r
+}
+
+// translate moves all the vertices of p by v.
+func (p *path) translate(v Vertex) {
+	u := p.head
+	for {
+		u.x, u.y = u.x+v[0], u.y+v[1]
+		if u = u.next; u == p.head {
+			break
+		}
+	}
+}
+
 func (p *path) pub() []Vertex {
-	var ret []Vertex
+	ret := make([]Vertex, 0, p.nVertices)
 	v := p.head
 	for {
 		ret = append(ret, Vertex{v.x, v.y})
@@ -258,13 +367,13 @@ func (ps *pathSet) sort() {
 	}
 	a := make([]*path, 0, n)
 	for i := 0; i < n; i++ {
-		mind, mini := 0, -1
+		var mind int64
+		mini := -1
 		for j, p := range ps.paths {
 			if p == nil || p.deleted {
 				continue
 			}
-			dx, dy := p.head.x-x0, p.head.y-y0
-			if d := dx*dx + dy*dy; mini == -1 || d < mind {
+			if d := sqDist(p.head.x-x0, p.head.y-y0); mini == -1 || d < mind {
 				mind, mini = d, j
 			}
 		}
@@ -276,6 +385,19 @@ func (ps *pathSet) sort() {
 	ps.paths = a
 }
 
+// compact removes the deleted paths and normalizes the remaining paths, without
+// changing the order.
+func (ps *pathSet) compact() {
+	a := make([]*path, 0, len(ps.paths))
+	for _, p := range ps.paths {
+		if !p.deleted {
+			p.normalize()
+			a = append(a, p)
+		}
+	}
+	ps.paths = a
+}
+
 func (ps *pathSet) pub() [][]Vertex {
 	ret := make([][]Vertex, 0, len(ps.paths))
 	for _, p := range ps.paths {
@@ -290,90 +412,141 @@ func (ps *pathSet) pub() [][]Vertex {
 // New creates a set of paths from a binary bitmap image represented by a bit
 // array. The bit array bm must be exactly width * height length.
 func New(bm *bitarray.Buffer, width int) (*Path, error) {
+	return NewWithOptions(bm, width, nil)
+}
+
+// NewWithOptions is identical to New except that the bitmap image is
+// preprocessed as specified by opts before it is traced. nil opts is the same
+// as the zero value of Options, and no preprocessing is performed.
+func NewWithOptions(bm *bitarray.Buffer, width int, opts *Options) (*Path, error) {
+	height, err := bitmapHeight(bm, width)
+	if err != nil {
+		return nil, err
+	}
+	if opts != nil {
+		if bm, width, height, err = opts.prepare(bm, width, height); err != nil {
+			return nil, err
+		}
+	}
+
+	return trace(bm, width, height, opts)
+}
+
+// bitmapHeight validates the bitmap image bm of the width, and returns its
+// height.
+func bitmapHeight(bm *bitarray.Buffer, width int) (int, error) {
 	switch {
 	case width < 1:
-		return nil, fmt.Errorf("%w: %d < 1", ErrInvalidWidth, width)
+		return 0, fmt.Errorf("%w: %d < 1", ErrInvalidWidth, width)
 	case bm == nil:
-		return nil, fmt.Errorf("%w: bm == nil", ErrInvalidBitmap)
+		return 0, fmt.Errorf("%w: bm == nil", ErrInvalidBitmap)
 	}
 	bmlen := bm.Len()
-	height := bmlen / width
 	switch {
 	case bmlen < width:
-		return nil, fmt.Errorf("%w: too short: len=%d < width=%d", ErrInvalidBitmap, bmlen, width)
+		return 0, fmt.Errorf("%w: too short: len=%d < width=%d", ErrInvalidBitmap, bmlen, width)
 	case bmlen%width != 0:
-		return nil, fmt.Errorf("%w: len=%d %% width=%d != 0", ErrInvalidBitmap, bmlen, width)
+		return 0, fmt.Errorf("%w: len=%d %% width=%d != 0", ErrInvalidBitmap, bmlen, width)
 	}
-	ps := &pathSet{width: width, height: height}
+	return bmlen / width, nil
+}
 
-	v := bitarray.NewBuffer((width + 1) * (height + 1) << 2)
-	pix := func(x, y int) bool {
-		// if x < 0 || y < 0 || width <= x || height <= y {
-		// 	return false
-		// }
-		return bm.BitAt(width*y+x) != 0
+// trace creates a set of paths from the bitmap image bm of the size width x
+// height. opts may be nil.
+func trace(bm *bitarray.Buffer, width, height int, opts *Options) (*Path, error) {
+	g, err := newEdgeGrid(bm, width, height)
+	if err != nil {
+		return nil, err
 	}
-	set := func(x, y, dir int, b bool) {
-		bb := byte(0)
-		if b {
-			bb = 1
-		}
-		v.PutBitAt(((width+1)*y+x)*4+dir, bb)
+	return g.trace(opts, &arena{})
+}
+
+// trace links the edges of g into a set of paths, allocated from a. The edges
+// may be consumed, so g must not be used after this. g is no longer read when
+// trace returns, even if the tracing is stopped by Visit, so that its memory
+// can be reused. opts may be nil.
+func (g *EdgeGrid) trace(opts *Options, a *arena) (*Path, error) {
+	width, height := g.Width, g.Height
+	ps := &pathSet{width: width, height: height}
+	stopped := false
+
+	next := g.paths(a)
+	if n := opts.workers(width, height); 1 < n {
+		var stop func()
+		next, stop = g.bandPaths(n)
+		defer stop()
 	}
-	get := func(x, y, dir int) bool {
-		ret := v.BitAt(((width+1)*y+x)*4+dir) != 0
-		if ret {
-			set(x, y, dir, false)
+	for path := next(); path != nil; path = next() {
+		if g.origin != (Vertex{}) {
+			path.translate(g.origin)
 		}
-		return ret
-	}
-	for y := 0; y < height+1; y++ {
-		for x := 0; x < width; x++ {
-			var mu, md bool
-			if 0 < y {
-				mu = pix(x, y-1)
-			}
-			if y < height {
-				md = pix(x, y)
-			}
-			switch {
-			case !mu && md:
-				set(x, y, 1, true)
-			case mu && !md:
-				set(x+1, y, 3, true)
+		if opts != nil && opts.Visit != nil && !opts.Visit(path.pub()) {
+			if !opts.Partial {
+				return nil, ErrStopped
 			}
+			stopped = true
+			break
 		}
-	}
-	for x := 0; x < width+1; x++ {
-		for y := 0; y < height; y++ {
-			var ml, mr bool
-			if 0 < x {
-				ml = pix(x-1, y)
-			}
-			if x < width {
-				mr = pix(x, y)
-			}
-			switch {
-			case !ml && mr:
-				set(x, y+1, 0, true)
-			case ml && !mr:
-				set(x, y, 2, true)
+		if opts != nil && opts.Warn != nil {
+			if r := path.bounds(); r.Min.X == 0 || r.Min.Y == 0 || r.Max.X == width || r.Max.Y == height {
+				opts.warn(WarnBorderInk, r, "closed path touching the border of the canvas")
 			}
 		}
+		ps.addPath(path)
 	}
-	for {
-		s := Vertex{-1, -1}
-		for y := 0; y < height+1 && s[1] < 0; y++ {
-			for x := 0; x < width+1 && s[0] < 0; x++ {
-				if get(x, y, 1) {
-					s = Vertex{x, y}
-				}
+	sort.Sort(pathList(ps.paths))
+	ps.merge(opts)
+	if opts != nil && opts.Ordering == OrderNone {
+		ps.compact()
+	} else {
+		ps.sort()
+	}
+
+	ret := &Path{
+		Width:    ps.width,
+		Height:   ps.height,
+		Vertices: ps.pub(),
+	}
+	if opts != nil && (opts.Ordering == OrderTwoOpt || opts.FlexibleStart) {
+		ret = ret.Reorder(opts.Ordering, opts.FlexibleStart)
+	}
+	if stopped {
+		ret.Incomplete = true
+		return ret, ErrStopped
+	}
+
+	return ret, nil
+}
+
+// paths returns the function returning the closed paths of g one by one, and
+// nil after the last one. Each closed path starts from its first rightward
+// edge in the raster order, and the closed paths are returned in the raster
+// order of their starting points. The edges are consumed as they are linked.
+// The paths are allocated from a.
+func (g *EdgeGrid) paths(a *arena) func() *path {
+	width, height := g.Width, g.Height
+	get := func(x, y, dir int) bool {
+		return g.take((width+1)*y+x, dir)
+	}
+	// the scan resumes from the last starting point, since all the
+	// rightward edges before it have been consumed, skipping a word of the
+	// plane without any edges at a time
+	right := g.planes[1]
+	pos, end := 0, (width+1)*(height+1)
+	return func() *path {
+		for pos < end {
+			if w := right[pos>>6] >> (pos & 63); w != 0 {
+				pos += bits.TrailingZeros64(w)
+				break
 			}
+			pos = (pos | 63) + 1
 		}
-		if s[0] < 0 {
-			break
+		if end <= pos {
+			return nil
 		}
-		path := newPath(s)
+		g.take(pos, 1)
+		s := Vertex{pos % (width + 1), pos / (width + 1)}
+		path := newPath(a, s)
 		dir, cx, cy := 1, s[0]+1, s[1]
 		for cx != s[0] || cy != s[1] {
 			switch dir {
@@ -432,38 +605,6 @@ func New(bm *bitarray.Buffer, width int) (*Path, error) {
 			}
 		}
 		path.close()
-		ps.addPath(path)
+		return path
 	}
-	sort.Sort(pathList(ps.paths))
-	for {
-		eff := false
-		for i, p0 := range ps.paths {
-			if p0.deleted {
-				continue
-			}
-			for j := i + 1; j < len(ps.paths); j++ {
-				p1 := ps.paths[j]
-				if p1.deleted {
-					continue
-				}
-				if d, nv0, nv1 := dist(p0, p1); d == 0 {
-					nv0.ins(nv1)
-					p1.deleted = true
-					eff = true
-				}
-			}
-		}
-		if !eff {
-			break
-		}
-	}
-	ps.sort()
-
-	ret := &Path{
-		Width:    ps.width,
-		Height:   ps.height,
-		Vertices: ps.pub(),
-	}
-
-	return ret, nil
 }
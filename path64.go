@@ -0,0 +1,189 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Vertex64 is identical to Vertex except that it has int64 coordinates.
+type Vertex64 [2]int64
+
+// String returns the string representation of a Vertex64 in "(x, y)" format.
+func (v Vertex64) String() string { return fmt.Sprintf("(%d, %d)", v[0], v[1]) }
+
+// X returns the x-coordinate of Vertex64.
+func (v Vertex64) X() int64 { return v[0] }
+
+// Y returns the y-coordinate of Vertex64.
+func (v Vertex64) Y() int64 { return v[1] }
+
+// Path64 is identical to Path except that it has int64 dimensions and
+// coordinates. It is intended for the tiled or streamed workflows of the huge
+// images, where the tiles are traced separately into Paths and placed on the
+// whole canvas with Append, whose size may exceed the range of int on the
+// 32-bit platforms.
+type Path64 struct {
+	Width, Height int64
+	Vertices      [][]Vertex64
+	Labels        []string
+}
+
+// Int64 converts p to a Path64.
+func (p *Path) Int64() *Path64 {
+	ret := &Path64{
+		Width:    int64(p.Width),
+		Height:   int64(p.Height),
+		Vertices: make([][]Vertex64, len(p.Vertices)),
+	}
+	for i, vs := range p.Vertices {
+		nvs := make([]Vertex64, len(vs))
+		for j, v := range vs {
+			nvs[j] = Vertex64{int64(v[0]), int64(v[1])}
+		}
+		ret.Vertices[i] = nvs
+	}
+	if p.Labels != nil {
+		ret.Labels = append([]string(nil), p.Labels...)
+	}
+	return ret
+}
+
+// Path converts p back to a Path. It returns ErrTooLarge if any of the
+// dimensions or the coordinates does not fit in int.
+func (p *Path64) Path() (*Path, error) {
+	const maxInt, minInt = int64(int(^uint(0) >> 1)), -int64(int(^uint(0)>>1)) - 1
+	fits := func(n int64) bool { return minInt <= n && n <= maxInt }
+	if !fits(p.Width) || !fits(p.Height) {
+		return nil, fmt.Errorf("%w: %dx%d", ErrTooLarge, p.Width, p.Height)
+	}
+	ret := &Path{
+		Width:    int(p.Width),
+		Height:   int(p.Height),
+		Vertices: make([][]Vertex, len(p.Vertices)),
+	}
+	for i, vs := range p.Vertices {
+		nvs := make([]Vertex, len(vs))
+		for j, v := range vs {
+			if !fits(v[0]) || !fits(v[1]) {
+				return nil, fmt.Errorf("%w: %s", ErrTooLarge, v)
+			}
+			nvs[j] = Vertex{int(v[0]), int(v[1])}
+		}
+		ret.Vertices[i] = nvs
+	}
+	if p.Labels != nil {
+		ret.Labels = append([]string(nil), p.Labels...)
+	}
+	return ret, nil
+}
+
+// NumPath returns the number of closed paths in this set of paths.
+func (p *Path64) NumPath() int { return len(p.Vertices) }
+
+// PathLen returns the number of vertices of the closed path specified by the
+// index n. n must be less than p.NumPath() otherwise it panics.
+func (p *Path64) PathLen(n int) int { return len(p.Vertices[n]) }
+
+// PathString returns the string representation of the closed path specified by
+// the index n.
+func (p *Path64) PathString(n int) string {
+	f := make([]string, len(p.Vertices[n]))
+	for i, v := range p.Vertices[n] {
+		f[i] = v.String()
+	}
+	return strings.Join(f, ", ")
+}
+
+// Append is identical to Path.Append except that the Path other is placed at
+// the int64 coordinate (atX, atY). This is the way to place the tiles traced
+// separately on the whole canvas. Append modifies p itself.
+func (p *Path64) Append(other *Path, atX, atY int64) {
+	if w := atX + int64(other.Width); p.Width < w {
+		p.Width = w
+	}
+	if h := atY + int64(other.Height); p.Height < h {
+		p.Height = h
+	}
+	if p.Labels != nil || other.Labels != nil {
+		for len(p.Labels) < len(p.Vertices) {
+			p.Labels = append(p.Labels, "")
+		}
+		for i := range other.Vertices {
+			p.Labels = append(p.Labels, other.PathLabel(i))
+		}
+	}
+	for _, vs := range other.Vertices {
+		nvs := make([]Vertex64, len(vs))
+		for i, v := range vs {
+			nvs[i] = Vertex64{int64(v[0]) + atX, int64(v[1]) + atY}
+		}
+		p.Vertices = append(p.Vertices, nvs)
+	}
+}
+
+// Translate returns a new Path64 with all the vertices moved by (dx, dy), the
+// same as Path.Translate. p itself is not modified.
+func (p *Path64) Translate(dx, dy int64) *Path64 {
+	ret := &Path64{
+		Width:    p.Width + dx,
+		Height:   p.Height + dy,
+		Vertices: make([][]Vertex64, len(p.Vertices)),
+	}
+	for i, vs := range p.Vertices {
+		nvs := make([]Vertex64, len(vs))
+		for j, v := range vs {
+			nvs[j] = Vertex64{v[0] + dx, v[1] + dy}
+		}
+		ret.Vertices[i] = nvs
+	}
+	if p.Labels != nil {
+		ret.Labels = append([]string(nil), p.Labels...)
+	}
+	return ret
+}
+
+// SVGDString is identical to WriteSVGD except that it returns a string instead
+// of writing to io.Writer.
+func (p *Path64) SVGDString() string {
+	var sb strings.Builder
+	_ = p.WriteSVGD(&sb)
+	return sb.String()
+}
+
+// WriteSVGD is identical to Path.WriteSVGD except that it writes p.
+func (p *Path64) WriteSVGD(w io.Writer) error {
+	var sb strings.Builder
+	var z Vertex64
+	for _, vs := range p.Vertices {
+		if len(vs) == 0 {
+			continue
+		}
+		c := vs[0]
+		cm := ","
+		if c[1]-z[1] < 0 {
+			cm = ""
+		}
+		fmt.Fprintf(&sb, "m%d%s%d", c[0]-z[0], cm, c[1]-z[1])
+		for _, v := range vs[1:] {
+			switch {
+			case v[0] == c[0]:
+				fmt.Fprintf(&sb, "v%d", v[1]-c[1])
+			case v[1] == c[1]:
+				fmt.Fprintf(&sb, "h%d", v[0]-c[0])
+			}
+			c = v
+		}
+		sb.WriteString("z")
+		if _, err := io.WriteString(w, sb.String()); err != nil {
+			return fmt.Errorf("write failure: %w", err)
+		}
+		sb.Reset()
+		z = vs[0]
+	}
+	return nil
+}
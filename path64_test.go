@@ -0,0 +1,67 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/tunabay/go-bitarray"
+	"github.com/tunabay/go-bmppath"
+)
+
+func ExamplePath64_Append() {
+	tile, err := bmppath.New(bitarray.NewBufferFromBitArray(bitarray.MustParse("0110")), 2)
+	if err != nil {
+		panic(err)
+	}
+
+	whole := &bmppath.Path64{}
+	whole.Append(tile, 0, 0)
+	whole.Append(tile, 1<<40, 1<<33)
+	fmt.Printf("%dx%d\n", whole.Width, whole.Height)
+	fmt.Println(whole.PathString(1))
+	fmt.Println(whole.SVGDString())
+
+	// Output:
+	// 1099511627778x8589934594
+	// (1099511627777, 8589934592), (1099511627778, 8589934592), (1099511627778, 8589934593), (1099511627777, 8589934593), (1099511627777, 8589934594), (1099511627776, 8589934594), (1099511627776, 8589934593), (1099511627777, 8589934593)
+	// m1,0h1v1h-1v1h-1v-1h1zm1099511627776,8589934592h1v1h-1v1h-1v-1h1z
+}
+
+func TestPath64_Path(t *testing.T) {
+	bmp := bitarray.NewBufferFromBitArray(
+		bitarray.MustParse(strings.Join([]string{
+			"1110",
+			"1010",
+			"1111",
+		}, "")),
+	)
+	path, err := bmppath.New(bmp, 4)
+	if err != nil {
+		t.Fatalf("New(): %v", err)
+	}
+	path.Labels = []string{"a"}
+	p64 := path.Int64()
+	if got := p64.SVGDString(); got != path.SVGDString() {
+		t.Errorf("unexpected d: got %q, want %q", got, path.SVGDString())
+	}
+	back, err := p64.Translate(3, 4).Translate(-3, -4).Path()
+	if err != nil {
+		t.Fatalf("Path(): %v", err)
+	}
+	if !back.Equal(path) || back.PathLabel(0) != "a" {
+		t.Errorf("round trip failed:\n%s", bmppath.Diff(path, back))
+	}
+
+	// the coordinates always fit in int on the 64-bit platforms
+	if ^uint(0)>>32 == 0 {
+		if _, err := p64.Translate(1<<40, 0).Path(); !errors.Is(err, bmppath.ErrTooLarge) {
+			t.Errorf("unexpected error: %v", err)
+		}
+	}
+}
@@ -109,3 +109,24 @@ func ExamplePath_SVGDString() {
 	// Output:
 	// m0,0h2v2h-2zm3,0h1v2h-1z
 }
+
+func ExamplePath_WriteSVGDAbsolute() {
+	bmp := bitarray.NewBufferFromBitArray(
+		bitarray.MustParse(strings.Join([]string{
+			"1101",
+			"1101",
+		}, "")),
+	)
+	path, err := bmppath.New(bmp, 4)
+	if err != nil {
+		panic(err)
+	}
+
+	if err := path.WriteSVGDAbsolute(os.Stdout); err != nil {
+		panic(err)
+	}
+	fmt.Println()
+
+	// Output:
+	// M0,0H2V2H0ZM3,0H4V2H3Z
+}
@@ -0,0 +1,158 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// PDFOptions represents the options for WritePDF.
+type PDFOptions struct {
+	// Width and Height are the physical size of the page in points, 1/72
+	// inch. For example, 50mm is 50 * 72 / 25.4 points. If one of them is
+	// zero, it is determined from the other keeping the aspect ratio of the
+	// image. If both are zero, each pixel is one point.
+	Width, Height float64
+
+	// FillRule is the rule to fill the closed paths. The paths are filled
+	// in the same way with both rules unless they have been modified so
+	// that the outlines and the holes are not oriented oppositely.
+	FillRule FillRule
+
+	// Marks, if not nil, adds the bleed and the printer's marks around the
+	// page of the size Width x Height, which becomes the TrimBox.
+	Marks *PrintMarks
+
+	// Grid, if not nil, records the pixel grid of the source raster as the
+	// PixelGrid dictionary in the document information dictionary, with the
+	// keys such as /PixelWidth.
+	Grid *PixelGrid
+}
+
+// WritePDF writes the closed paths as a minimal single-page PDF document, on
+// which the paths are filled with black on the page of the size specified by
+// opts, with the printer's marks and the metadata of the pixel grid if
+// specified. nil opts is the same as the zero value of PDFOptions.
+func (p *Path) WritePDF(w io.Writer, opts *PDFOptions) error {
+	if opts == nil {
+		opts = &PDFOptions{}
+	}
+	pw, ph := opts.Width, opts.Height
+	switch {
+	case pw == 0 && ph == 0:
+		pw, ph = float64(p.Width), float64(p.Height)
+	case pw == 0 && p.Height != 0:
+		pw = ph * float64(p.Width) / float64(p.Height)
+	case ph == 0 && p.Width != 0:
+		ph = pw * float64(p.Height) / float64(p.Width)
+	}
+	sx, sy := 1.0, 1.0
+	if p.Width != 0 {
+		sx = pw / float64(p.Width)
+	}
+	if p.Height != 0 {
+		sy = ph / float64(p.Height)
+	}
+	f := FormatCoord(-1, "")
+	e := opts.Marks.extent()
+
+	// the marks, then the paths flipped to the screen coordinates
+	var cs strings.Builder
+	if lines, circles := opts.Marks.shapes(pw, ph); len(lines) != 0 || len(circles) != 0 {
+		_, _, lw := opts.Marks.params()
+		pt := func(x, y float64) string { return f(e+x) + " " + f(e+ph-y) }
+		fmt.Fprintf(&cs, "q\n%s w\n", f(lw))
+		for _, l := range lines {
+			fmt.Fprintf(&cs, "%s m\n%s l\n", pt(l[0][0], l[0][1]), pt(l[1][0], l[1][1]))
+		}
+		for _, c := range circles {
+			// 4 Bézier curves approximating the quarter circles
+			x, y, r := c.Center[0], c.Center[1], c.Radius
+			k := r * 0.5522847498
+			fmt.Fprintf(&cs, "%s m\n", pt(x+r, y))
+			fmt.Fprintf(&cs, "%s %s %s c\n", pt(x+r, y+k), pt(x+k, y+r), pt(x, y+r))
+			fmt.Fprintf(&cs, "%s %s %s c\n", pt(x-k, y+r), pt(x-r, y+k), pt(x-r, y))
+			fmt.Fprintf(&cs, "%s %s %s c\n", pt(x-r, y-k), pt(x-k, y-r), pt(x, y-r))
+			fmt.Fprintf(&cs, "%s %s %s c\nh\n", pt(x+k, y-r), pt(x+r, y-k), pt(x+r, y))
+		}
+		fmt.Fprint(&cs, "S\nQ\n")
+	}
+	fmt.Fprintf(&cs, "%s 0 0 %s %s %s cm\n", f(sx), f(-sy), f(e), f(e+ph))
+	for _, vs := range p.Vertices {
+		for i, v := range vs {
+			op := "l"
+			if i == 0 {
+				op = "m"
+			}
+			fmt.Fprintf(&cs, "%d %d %s\n", v[0], v[1], op)
+		}
+		if len(vs) != 0 {
+			fmt.Fprintln(&cs, "h")
+		}
+	}
+	if len(p.Vertices) != 0 {
+		if opts.FillRule == EvenOdd {
+			fmt.Fprintln(&cs, "f*")
+		} else {
+			fmt.Fprintln(&cs, "f")
+		}
+	}
+
+	var sb strings.Builder
+	var offsets []int
+	obj := func(format string, a ...interface{}) {
+		offsets = append(offsets, sb.Len())
+		fmt.Fprintf(&sb, "%d 0 obj\n", len(offsets))
+		fmt.Fprintf(&sb, format, a...)
+		fmt.Fprint(&sb, "\nendobj\n")
+	}
+	fmt.Fprint(&sb, "%PDF-1.4\n")
+	obj("<< /Type /Catalog /Pages 2 0 R >>")
+	obj("<< /Type /Pages /Kids [3 0 R] /Count 1 >>")
+	boxes := fmt.Sprintf("/MediaBox [0 0 %s %s]", f(pw+e*2), f(ph+e*2))
+	if m := opts.Marks; m != nil {
+		b := m.Bleed
+		boxes += fmt.Sprintf(" /BleedBox [%s %s %s %s]", f(e-b), f(e-b), f(e+pw+b), f(e+ph+b))
+		boxes += fmt.Sprintf(" /TrimBox [%s %s %s %s]", f(e), f(e), f(e+pw), f(e+ph))
+	}
+	obj("<< /Type /Page /Parent 2 0 R %s /Contents 4 0 R >>", boxes)
+	obj("<< /Length %d >>\nstream\n%sendstream", cs.Len(), cs.String())
+	info := ""
+	if fields := opts.Grid.fields(float64(p.Width), float64(p.Height)); fields != nil {
+		var ds strings.Builder
+		for _, gf := range fields {
+			fmt.Fprintf(&ds, " /%s%s ", strings.ToUpper(gf.name[:1]), gf.name[1:])
+			switch v := gf.value.(type) {
+			case float64:
+				ds.WriteString(f(v))
+			case string:
+				ds.WriteString(pdfString(v))
+			}
+		}
+		obj("<< /PixelGrid <<%s >> >>", ds.String())
+		info = fmt.Sprintf(" /Info %d 0 R", len(offsets))
+	}
+	xref := sb.Len()
+	fmt.Fprintf(&sb, "xref\n0 %d\n", len(offsets)+1)
+	fmt.Fprint(&sb, "0000000000 65535 f \n")
+	for _, off := range offsets {
+		fmt.Fprintf(&sb, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&sb, "trailer\n<< /Size %d /Root 1 0 R%s >>\n", len(offsets)+1, info)
+	fmt.Fprintf(&sb, "startxref\n%d\n%%%%EOF\n", xref)
+	if _, err := fmt.Fprint(w, sb.String()); err != nil {
+		return fmt.Errorf("write failure: %w", err)
+	}
+	return nil
+}
+
+// pdfString returns the PDF literal string of s, with the special characters
+// escaped.
+func pdfString(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `(`, `\(`, `)`, `\)`, "\r", `\r`, "\n", `\n`)
+	return "(" + r.Replace(s) + ")"
+}
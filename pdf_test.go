@@ -0,0 +1,149 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath_test
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/tunabay/go-bitarray"
+	"github.com/tunabay/go-bmppath"
+)
+
+func ExamplePath_WritePDF() {
+	bmp := bitarray.NewBufferFromBitArray(bitarray.MustParse(strings.Join([]string{
+		"1100",
+		"0110",
+	}, "")))
+	path, err := bmppath.New(bmp, 4)
+	if err != nil {
+		panic(err)
+	}
+
+	// 20mm wide, with the height keeping the aspect ratio
+	opts := &bmppath.PDFOptions{Width: 20 * 72 / 25.4}
+	var buf bytes.Buffer
+	if err := path.WritePDF(&buf, opts); err != nil {
+		panic(err)
+	}
+
+	// the objects, followed by the cross-reference table and the trailer
+	doc := buf.String()
+	fmt.Print(doc[:strings.Index(doc, "xref\n")])
+
+	// Output:
+	// %PDF-1.4
+	// 1 0 obj
+	// << /Type /Catalog /Pages 2 0 R >>
+	// endobj
+	// 2 0 obj
+	// << /Type /Pages /Kids [3 0 R] /Count 1 >>
+	// endobj
+	// 3 0 obj
+	// << /Type /Page /Parent 2 0 R /MediaBox [0 0 56.69291338582677 28.346456692913385] /Contents 4 0 R >>
+	// endobj
+	// 4 0 obj
+	// << /Length 119 >>
+	// stream
+	// 14.173228346456693 0 0 -14.173228346456693 0 28.346456692913385 cm
+	// 0 0 m
+	// 2 0 l
+	// 2 1 l
+	// 3 1 l
+	// 3 2 l
+	// 1 2 l
+	// 1 1 l
+	// 0 1 l
+	// h
+	// f
+	// endstream
+	// endobj
+}
+
+func TestPath_WritePDF(t *testing.T) {
+	bmp := bitarray.NewBufferFromBitArray(bitarray.MustParse(strings.Join([]string{
+		"11111",
+		"10001",
+		"10101",
+		"10001",
+		"11111",
+	}, "")))
+	path, err := bmppath.New(bmp, 5)
+	if err != nil {
+		t.Fatalf("New(): %v", err)
+	}
+	for _, opts := range []*bmppath.PDFOptions{
+		nil,
+		{Height: 72},
+		{Width: 100, Height: 50, FillRule: bmppath.EvenOdd},
+		{Grid: &bmppath.PixelGrid{PixelWidth: 0.5, PixelHeight: 0.5, OffsetX: 12, Unit: "mm (scan)"}},
+	} {
+		var buf bytes.Buffer
+		if err := path.WritePDF(&buf, opts); err != nil {
+			t.Fatalf("WritePDF(): %v", err)
+		}
+		doc := buf.Bytes()
+
+		// the cross-reference table must point to the objects
+		m := regexp.MustCompile(`startxref\n(\d+)\n%%EOF\n$`).FindSubmatch(doc)
+		if m == nil {
+			t.Fatalf("startxref not found:\n%s", doc)
+		}
+		xref, _ := strconv.Atoi(string(m[1]))
+		nobj := 4
+		if opts != nil && opts.Grid != nil {
+			nobj = 5
+		}
+		if !bytes.HasPrefix(doc[xref:], []byte(fmt.Sprintf("xref\n0 %d\n", nobj+1))) {
+			t.Fatalf("invalid startxref %d:\n%s", xref, doc)
+		}
+		entries := regexp.MustCompile(`(\d{10}) 00000 n \n`).FindAllSubmatch(doc[xref:], -1)
+		if len(entries) != nobj {
+			t.Fatalf("unexpected number of xref entries: %d", len(entries))
+		}
+		for i, e := range entries {
+			off, _ := strconv.Atoi(string(e[1]))
+			if want := fmt.Sprintf("%d 0 obj\n", i+1); !bytes.HasPrefix(doc[off:], []byte(want)) {
+				t.Errorf("xref entry #%d: offset %d does not point to the object", i+1, off)
+			}
+		}
+
+		// the stream length must be exact
+		m = regexp.MustCompile(`<< /Length (\d+) >>\nstream\n`).FindSubmatch(doc)
+		if m == nil {
+			t.Fatalf("stream not found:\n%s", doc)
+		}
+		n, _ := strconv.Atoi(string(m[1]))
+		start := bytes.Index(doc, m[0]) + len(m[0])
+		if !bytes.HasPrefix(doc[start+n:], []byte("endstream")) {
+			t.Errorf("unexpected stream length %d:\n%s", n, doc)
+		}
+
+		fill := "\nf\n"
+		if opts != nil && opts.FillRule == bmppath.EvenOdd {
+			fill = "\nf*\n"
+		}
+		if got := strings.Count(string(doc), "\nh\n"); got != path.NumPath() {
+			t.Errorf("unexpected number of closed paths: got %d, want %d", got, path.NumPath())
+		}
+		if !bytes.Contains(doc, []byte(fill)) {
+			t.Errorf("fill operator %q not found:\n%s", fill, doc)
+		}
+		if nobj == 5 {
+			for _, want := range []string{
+				"5 0 obj\n<< /PixelGrid << /RasterWidth 5 /RasterHeight 5 /PixelWidth 0.5 /PixelHeight 0.5 /OffsetX 12 /OffsetY 0 /Unit (mm \\(scan\\)) >> >>\nendobj\n",
+				"<< /Size 6 /Root 1 0 R /Info 5 0 R >>",
+			} {
+				if !bytes.Contains(doc, []byte(want)) {
+					t.Errorf("%q not found:\n%s", want, doc)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,125 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath
+
+import (
+	"fmt"
+
+	"github.com/tunabay/go-bitarray"
+)
+
+// Stage is a step of the preprocessing Pipeline, which converts a bitmap image
+// into another one. The users can implement their own cleanup steps as Stages
+// and insert them between the built-in ones. Apply takes the bitmap image bm of
+// the width, and returns the result and its width, which may differ from the
+// original ones, such as for cropping. bm must not be modified.
+type Stage interface {
+	Apply(bm *bitarray.Buffer, width int) (*bitarray.Buffer, int, error)
+}
+
+// StageFunc is an adapter to use an ordinary function as a Stage.
+type StageFunc func(bm *bitarray.Buffer, width int) (*bitarray.Buffer, int, error)
+
+// Apply calls f(bm, width).
+func (f StageFunc) Apply(bm *bitarray.Buffer, width int) (*bitarray.Buffer, int, error) {
+	return f(bm, width)
+}
+
+// bitmapStage is a built-in Stage working on the bitmap of the preprocessing,
+// which keeps the size of the image.
+type bitmapStage func(img *bitmap) *bitmap
+
+func (f bitmapStage) Apply(bm *bitarray.Buffer, width int) (*bitarray.Buffer, int, error) {
+	height, err := bitmapHeight(bm, width)
+	if err != nil {
+		return nil, 0, err
+	}
+	return f(newBitmap(bm, width, height)).buffer(), width, nil
+}
+
+// Deskew returns the Stage straightening the image as Options.Deskew does,
+// with the maximum skew angle maxAngle in degrees. Zero maxAngle means the
+// default value 5.
+func Deskew(maxAngle float64) Stage {
+	if maxAngle <= 0 {
+		maxAngle = 5
+	}
+	return bitmapStage(func(img *bitmap) *bitmap { return img.deskew(maxAngle) })
+}
+
+// Despeckle returns the Stage erasing the specks and the holes of maxArea
+// pixels or smaller as Options.Despeckle does.
+func Despeckle(maxArea int) Stage {
+	return bitmapStage(func(img *bitmap) *bitmap { return img.despeckle(maxArea, nil) })
+}
+
+// CloseGaps returns the Stage applying the morphological closing of the radius
+// r as Options.CloseGaps does.
+func CloseGaps(r int) Stage {
+	return bitmapStage(func(img *bitmap) *bitmap { return img.closeGaps(r) })
+}
+
+// Smooth returns the Stage applying the 3x3 majority filter n times as
+// Options.Smooth does.
+func Smooth(n int) Stage {
+	return bitmapStage(func(img *bitmap) *bitmap { return img.smooth(n) })
+}
+
+// Invert returns the Stage swapping ink and background as specified by pol, as
+// Options.Polarity does.
+func Invert(pol Polarity) Stage {
+	return bitmapStage(func(img *bitmap) *bitmap {
+		if pol.inverted(img) {
+			return img.invert()
+		}
+		return img
+	})
+}
+
+// Pipeline is a sequence of the preprocessing Stages applied in order. For
+// example, a custom cleanup step can be inserted between the built-in steps
+// as:
+//
+//	pl := bmppath.Pipeline{
+//		bmppath.Deskew(0),
+//		bmppath.StageFunc(removeRuledLines),
+//		bmppath.Despeckle(4),
+//	}
+//	path, err := pl.New(bm, width, nil)
+//
+// The binarization of the images is not a Stage, since the Stages work on the
+// bitmap images. It is specified by Options for NewFromImage, which runs
+// Options.Pipeline after it.
+type Pipeline []Stage
+
+// Apply applies the Stages of pl in order to the bitmap image bm of the width,
+// and returns the result and its width. It returns the error returned by a
+// Stage as is, or ErrInvalidBitmap if a Stage returns an invalid bitmap image.
+func (pl Pipeline) Apply(bm *bitarray.Buffer, width int) (*bitarray.Buffer, int, error) {
+	if _, err := bitmapHeight(bm, width); err != nil {
+		return nil, 0, err
+	}
+	for i, s := range pl {
+		var err error
+		if bm, width, err = s.Apply(bm, width); err != nil {
+			return nil, 0, err
+		}
+		if _, err := bitmapHeight(bm, width); err != nil {
+			return nil, 0, fmt.Errorf("stage #%d: %w", i, err)
+		}
+	}
+	return bm, width, nil
+}
+
+// New applies pl to the bitmap image bm of the width, and traces the result
+// with NewWithOptions with opts. nil opts is the same as the zero value of
+// Options.
+func (pl Pipeline) New(bm *bitarray.Buffer, width int, opts *Options) (*Path, error) {
+	bm, width, err := pl.Apply(bm, width)
+	if err != nil {
+		return nil, err
+	}
+	return NewWithOptions(bm, width, opts)
+}
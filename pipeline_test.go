@@ -0,0 +1,90 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/tunabay/go-bitarray"
+	"github.com/tunabay/go-bmppath"
+)
+
+// cropBorder is a custom Stage removing the 1-pixel border of the image, such
+// as the frame left by a scanner.
+func cropBorder(bm *bitarray.Buffer, width int) (*bitarray.Buffer, int, error) {
+	height := bm.Len() / width
+	if width < 3 || height < 3 {
+		return bm, width, nil
+	}
+	ret := bitarray.NewBuffer((width - 2) * (height - 2))
+	for y := 1; y < height-1; y++ {
+		for x := 1; x < width-1; x++ {
+			ret.PutBitAt((width-2)*(y-1)+x-1, bm.BitAt(width*y+x))
+		}
+	}
+	return ret, width - 2, nil
+}
+
+func ExamplePipeline() {
+	bmp := bitarray.NewBufferFromBitArray(
+		bitarray.MustParse(strings.Join([]string{
+			"1111111",
+			"1000001",
+			"1011001",
+			"1011001",
+			"1000011",
+			"1111111",
+		}, "")),
+	)
+
+	pl := bmppath.Pipeline{
+		bmppath.StageFunc(cropBorder),
+		bmppath.Despeckle(1),
+	}
+	path, err := pl.New(bmp, 7, nil)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(path.Width, path.Height)
+	fmt.Println(path.SVGDString())
+
+	// Output:
+	// 5 4
+	// m1,1h2v2h-2z
+}
+
+func TestOptions_pipeline(t *testing.T) {
+	bmp := bitarray.NewBufferFromBitArray(bitarray.MustParse("0110" + "0110"))
+	errStage := errors.New("stage failure")
+
+	opts := &bmppath.Options{
+		Polarity: bmppath.PolarityInverted,
+		Pipeline: bmppath.Pipeline{bmppath.Invert(bmppath.PolarityInverted)},
+	}
+	path, err := bmppath.NewWithOptions(bmp, 4, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := path.SVGDString(), "m1,0h2v2h-2z"; got != want {
+		t.Errorf("unexpected path: got %q, want %q", got, want)
+	}
+
+	opts.Pipeline = bmppath.Pipeline{bmppath.StageFunc(func(*bitarray.Buffer, int) (*bitarray.Buffer, int, error) {
+		return nil, 0, errStage
+	})}
+	if _, err := bmppath.NewWithOptions(bmp, 4, opts); !errors.Is(err, errStage) {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	opts.Pipeline = bmppath.Pipeline{bmppath.StageFunc(func(bm *bitarray.Buffer, _ int) (*bitarray.Buffer, int, error) {
+		return bm, 3, nil
+	})}
+	if _, err := bmppath.NewWithOptions(bmp, 4, opts); !errors.Is(err, bmppath.ErrInvalidBitmap) {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
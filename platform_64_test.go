@@ -0,0 +1,9 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+//go:build !(386 || arm || mips || mipsle)
+
+package bmppath_test
+
+const intSize = 64
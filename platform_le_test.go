@@ -0,0 +1,9 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+//go:build !(mips || mips64 || ppc64 || s390x)
+
+package bmppath_test
+
+const bigEndian = false
@@ -0,0 +1,79 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath_test
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"testing"
+
+	"github.com/tunabay/go-bmppath"
+)
+
+// platformGolden holds the hashes of the paths traced from the conformance
+// corpus, which must be identical on all the platforms.
+var platformGolden = map[string]string{
+	"checker":  "06376df7a1fe3729e12bf7134663932d",
+	"empty":    "28f628133705e9a9702511bccefd034d",
+	"full":     "385a5d0d11cbf064564589e0ca45dbc1",
+	"invader":  "5856d65729b1cb22a518526aa8583cdd",
+	"nested":   "b13246a097cfe4fb90881f7d4c99c3cd",
+	"random-0": "56b494accbac773b84528dbc4b6783ab",
+	"random-1": "707683ac5aa398dcb49379b10db43431",
+	"random-2": "4c2ed282272d431b8173d9abedeea5f9",
+	"random-3": "2b600eb9268d1269073eeb471a31b44f",
+}
+
+// platformGoldenCatalog is the SHA-256 digest of the Catalog containing all
+// the paths of platformGolden, added in the order of their names.
+const platformGoldenCatalog = "b49ddd54ebde9283c40df9f0a0f857bedd44f35b0a9fbf0011065416027b3d4d"
+
+// TestPlatformIndependence checks that tracing and the binary serialization
+// produce identical results on 32-bit and big-endian platforms. intSize and
+// bigEndian are defined in the build-tagged files. Run it on those platforms
+// with, for example, GOARCH=386 or GOARCH=mips under an emulator.
+func TestPlatformIndependence(t *testing.T) {
+	t.Logf("int size: %d, big endian: %t", intSize, bigEndian)
+
+	corpus := conformanceCorpus()
+	names := []string{
+		"checker", "empty", "full", "invader", "nested",
+		"random-0", "random-1", "random-2", "random-3",
+	}
+	c := bmppath.NewCatalog()
+	for _, name := range names {
+		path, err := bmppath.New(corpus[name], 8)
+		if err != nil {
+			t.Fatalf("%s: New(): %v", name, err)
+		}
+		if got := fmt.Sprintf("%x", path.Hash()); got != platformGolden[name] {
+			t.Errorf("%s: unexpected hash: got %s, want %s", name, got, platformGolden[name])
+		}
+		c.Add(name, path)
+	}
+
+	var buf bytes.Buffer
+	if _, err := c.WriteTo(&buf); err != nil {
+		t.Fatalf("WriteTo(): %v", err)
+	}
+	if got := fmt.Sprintf("%x", sha256.Sum256(buf.Bytes())); got != platformGoldenCatalog {
+		t.Errorf("unexpected catalog digest: got %s, want %s", got, platformGoldenCatalog)
+	}
+
+	oc, err := bmppath.OpenCatalog(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("OpenCatalog(): %v", err)
+	}
+	for _, name := range names {
+		path, err := oc.Get(name)
+		if err != nil {
+			t.Fatalf("%s: Get(): %v", name, err)
+		}
+		if got := fmt.Sprintf("%x", path.Hash()); got != platformGolden[name] {
+			t.Errorf("%s: unexpected decoded hash: got %s, want %s", name, got, platformGolden[name])
+		}
+	}
+}
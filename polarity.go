@@ -0,0 +1,67 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath
+
+// Polarity specifies which value of the pixels of the bitmap image is ink.
+type Polarity int
+
+const (
+	// PolarityNormal treats the pixels of 1 as ink.
+	PolarityNormal Polarity = iota
+
+	// PolarityInverted treats the pixels of 0 as ink.
+	PolarityInverted
+
+	// PolarityAuto detects the background from the pixels on the border of
+	// the image. If more than half of them are 1, the image is inverted, so
+	// that the background surrounding the artwork is not traced as ink,
+	// whichever polarity the scanners and the exporting tools produce.
+	PolarityAuto
+)
+
+// inverted reports whether the bitmap image img is inverted by the polarity.
+func (pol Polarity) inverted(img *bitmap) bool {
+	switch pol {
+	case PolarityInverted:
+		return true
+	case PolarityAuto:
+		return img.borderInk()*2 > img.borderLen()
+	}
+	return false
+}
+
+// borderLen returns the number of the pixels on the border of img.
+func (img *bitmap) borderLen() int {
+	if img.width < 3 || img.height < 3 {
+		return img.width * img.height
+	}
+	return 2 * (img.width + img.height - 2)
+}
+
+// borderInk returns the number of the ink pixels on the border of img.
+func (img *bitmap) borderInk() int {
+	n := 0
+	for y := 0; y < img.height; y++ {
+		step := img.width - 1
+		if y == 0 || y == img.height-1 || step < 1 {
+			step = 1
+		}
+		for x := 0; x < img.width; x += step {
+			if img.pix[img.width*y+x] {
+				n++
+			}
+		}
+	}
+	return n
+}
+
+// invert returns a new bitmap with ink and background swapped.
+func (img *bitmap) invert() *bitmap {
+	ret := &bitmap{width: img.width, height: img.height, pix: make([]bool, len(img.pix))}
+	for i, b := range img.pix {
+		ret.pix[i] = !b
+	}
+	return ret
+}
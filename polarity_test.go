@@ -0,0 +1,46 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath_test
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/tunabay/go-bitarray"
+	"github.com/tunabay/go-bmppath"
+)
+
+func ExamplePolarity() {
+	// white ink on black background, as some scanners produce
+	negative := bitarray.NewBufferFromBitArray(
+		bitarray.MustParse(strings.Join([]string{
+			"11111",
+			"10011",
+			"10011",
+			"11111",
+		}, "")),
+	)
+	positive := bitarray.NewBufferFromBitArray(
+		bitarray.MustParse(strings.Join([]string{
+			"00000",
+			"01100",
+			"01100",
+			"00000",
+		}, "")),
+	)
+
+	opts := &bmppath.Options{Polarity: bmppath.PolarityAuto}
+	for _, bmp := range []*bitarray.Buffer{negative, positive} {
+		path, err := bmppath.NewWithOptions(bmp, 5, opts)
+		if err != nil {
+			panic(err)
+		}
+		fmt.Println(path.SVGDString())
+	}
+
+	// Output:
+	// m1,1h2v2h-2z
+	// m1,1h2v2h-2z
+}
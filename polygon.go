@@ -0,0 +1,82 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+)
+
+// PolygonOptions represents the options for WriteSVGPolygons.
+type PolygonOptions struct {
+	// Format formats the coordinates in the points attributes. If nil, the
+	// shortest representation of each value is used.
+	Format CoordFormatter
+}
+
+// WriteSVGPolygons writes the vectorized bitmap image as an SVG document in
+// which each closed path is a <polygon> element, instead of the 'd' string of a
+// <path> element, for the consumers that handle the polygons but not the path
+// syntax, such as some CNC frontends and simple parsers. Since a polygon
+// cannot have holes, the holes are filled with white, and the elements are
+// written in descending order of the enclosed areas, so that each hole is
+// painted over the outline enclosing it, in the same way as
+// SVGOptions.Separate. Unlike WriteSVG, no background is written. nil opts is
+// the same as the zero value of PolygonOptions.
+func (p *Path) WriteSVGPolygons(w io.Writer, opts *PolygonOptions) error {
+	return p.Float().WriteSVGPolygons(w, opts)
+}
+
+// WriteSVGPolygons is identical to Path.WriteSVGPolygons except that it writes
+// fp.
+func (fp *FloatPath) WriteSVGPolygons(w io.Writer, opts *PolygonOptions) error {
+	if opts == nil {
+		opts = &PolygonOptions{}
+	}
+	f := opts.Format
+	if f == nil {
+		f = defaultCoordFormatter
+	}
+	areas := make([]float64, len(fp.Vertices))
+	order := make([]int, 0, len(fp.Vertices))
+	for i, vs := range fp.Vertices {
+		if len(vs) == 0 {
+			continue
+		}
+		areas[i] = floatSignedArea(vs)
+		order = append(order, i)
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return math.Abs(areas[order[j]]) < math.Abs(areas[order[i]])
+	})
+
+	var sb strings.Builder
+	fmt.Fprintln(&sb, `<?xml version="1.0" encoding="utf-8"?>`)
+	fmt.Fprint(&sb, `<svg version="1.1" xmlns="http://www.w3.org/2000/svg"`)
+	fmt.Fprintf(&sb, ` viewBox="0 0 %s %s">`, f(fp.Width), f(fp.Height))
+	fmt.Fprintln(&sb)
+	for _, n := range order {
+		sb.WriteString(`<polygon`)
+		if areas[n] < 0 {
+			sb.WriteString(` fill="#fff"`)
+		}
+		sb.WriteString(` points="`)
+		for i, v := range fp.Vertices[n] {
+			if i != 0 {
+				sb.WriteString(" ")
+			}
+			fmt.Fprintf(&sb, "%s,%s", f(v[0]), f(v[1]))
+		}
+		fmt.Fprintln(&sb, `"/>`)
+	}
+	fmt.Fprintln(&sb, `</svg>`)
+	if _, err := io.WriteString(w, sb.String()); err != nil {
+		return fmt.Errorf("write failure: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath_test
+
+import (
+	"os"
+	"strings"
+
+	"github.com/tunabay/go-bitarray"
+	"github.com/tunabay/go-bmppath"
+)
+
+func ExamplePath_WriteSVGPolygons() {
+	bmp := bitarray.NewBufferFromBitArray(
+		bitarray.MustParse(strings.Join([]string{
+			"11100",
+			"10100",
+			"11101",
+		}, "")),
+	)
+	path, err := bmppath.New(bmp, 5)
+	if err != nil {
+		panic(err)
+	}
+
+	if err := path.WriteSVGPolygons(os.Stdout, nil); err != nil {
+		panic(err)
+	}
+
+	// Output:
+	// <?xml version="1.0" encoding="utf-8"?>
+	// <svg version="1.1" xmlns="http://www.w3.org/2000/svg" viewBox="0 0 5 3">
+	// <polygon points="0,0 3,0 3,3 0,3"/>
+	// <polygon fill="#fff" points="1,1 1,2 2,2 2,1"/>
+	// <polygon points="4,2 5,2 5,3 4,3"/>
+	// </svg>
+}
@@ -0,0 +1,419 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/tunabay/go-bitarray"
+)
+
+// ReadPotraceSVG reads an SVG document written by potrace, with the -s option,
+// and returns its paths as a FloatPath on the canvas of the size of the viewBox,
+// so that the results of potrace can be compared with the ones of this package
+// programmatically. The transform attributes of the <g> and <path> elements are
+// applied, and the curves are flattened with the tolerance tol as Flatten does.
+// The path data may consist of the commands M, L, H, V, C, and Z, in both the
+// absolute and the relative forms, which covers the output of potrace. tol must
+// be positive otherwise it panics.
+func ReadPotraceSVG(r io.Reader, tol float64) (*FloatPath, error) {
+	if !(0 < tol) {
+		panic("bmppath: non-positive tolerance")
+	}
+	dec := xml.NewDecoder(r)
+	var cp *CurvePath
+	var stack []affine
+	for {
+		tok, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidSVG, err)
+		}
+		switch tok := tok.(type) {
+		case xml.StartElement:
+			if cp == nil {
+				if tok.Name.Local != "svg" {
+					return nil, fmt.Errorf("%w: unexpected root element <%s>", ErrInvalidSVG, tok.Name.Local)
+				}
+				f, err := newSVGFrame(tok.Attr)
+				if err != nil {
+					return nil, err
+				}
+				cp = &CurvePath{Width: f.ViewBox[2], Height: f.ViewBox[3]}
+				stack = append(stack, affine{1, 0, 0, 1, -f.ViewBox[0], -f.ViewBox[1]})
+				continue
+			}
+			t := stack[len(stack)-1]
+			var d string
+			for _, attr := range tok.Attr {
+				switch attr.Name.Local {
+				case "transform":
+					u, err := parseSVGTransform(attr.Value)
+					if err != nil {
+						return nil, err
+					}
+					t = t.mul(u)
+				case "d":
+					d = attr.Value
+				}
+			}
+			stack = append(stack, t)
+			if tok.Name.Local == "path" {
+				cs, err := parseSVGPathData(d, t)
+				if err != nil {
+					return nil, err
+				}
+				cp.Curves = append(cp.Curves, cs...)
+			}
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+		}
+	}
+	if cp == nil {
+		return nil, fmt.Errorf("%w: no <svg> element", ErrInvalidSVG)
+	}
+	return cp.Flatten(tol), nil
+}
+
+// affine is an affine transform [a c e; b d f] of SVG.
+type affine [6]float64
+
+// mul returns the transform applying u and then t.
+func (t affine) mul(u affine) affine {
+	return affine{
+		t[0]*u[0] + t[2]*u[1],
+		t[1]*u[0] + t[3]*u[1],
+		t[0]*u[2] + t[2]*u[3],
+		t[1]*u[2] + t[3]*u[3],
+		t[0]*u[4] + t[2]*u[5] + t[4],
+		t[1]*u[4] + t[3]*u[5] + t[5],
+	}
+}
+
+// apply returns the point v transformed by t.
+func (t affine) apply(v FloatVertex) FloatVertex {
+	return FloatVertex{t[0]*v[0] + t[2]*v[1] + t[4], t[1]*v[0] + t[3]*v[1] + t[5]}
+}
+
+// parseSVGTransform parses the transform attribute s consisting of the
+// functions matrix, translate, and scale.
+func parseSVGTransform(s string) (affine, error) {
+	t := affine{1, 0, 0, 1, 0, 0}
+	for rest := strings.TrimSpace(s); rest != ""; rest = strings.TrimLeft(rest, " \t\r\n,") {
+		open, end := strings.IndexByte(rest, '('), strings.IndexByte(rest, ')')
+		if open < 0 || end < open {
+			return t, fmt.Errorf("%w: transform=%q", ErrInvalidSVG, s)
+		}
+		name := strings.TrimSpace(rest[:open])
+		args, err := parseSVGNumbers(rest[open+1 : end])
+		if err != nil {
+			return t, err
+		}
+		var u affine
+		switch {
+		case name == "matrix" && len(args) == 6:
+			copy(u[:], args)
+		case name == "translate" && (len(args) == 1 || len(args) == 2):
+			u = affine{1, 0, 0, 1, args[0], 0}
+			if len(args) == 2 {
+				u[5] = args[1]
+			}
+		case name == "scale" && (len(args) == 1 || len(args) == 2):
+			u = affine{args[0], 0, 0, args[0], 0, 0}
+			if len(args) == 2 {
+				u[3] = args[1]
+			}
+		default:
+			return t, fmt.Errorf("%w: unsupported transform %s(%s)", ErrInvalidSVG, name, rest[open+1:end])
+		}
+		t = t.mul(u)
+		rest = rest[end+1:]
+	}
+	return t, nil
+}
+
+// parseSVGNumbers parses the list of the numbers separated by the commas or the
+// white spaces.
+func parseSVGNumbers(s string) ([]float64, error) {
+	var ret []float64
+	for _, f := range strings.FieldsFunc(s, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t' || r == '\n' || r == '\r'
+	}) {
+		v, err := strconv.ParseFloat(f, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidSVG, err)
+		}
+		ret = append(ret, v)
+	}
+	return ret, nil
+}
+
+// parseSVGPathData parses the path data d, and returns the closed paths
+// transformed by t. The subpaths not closed explicitly are closed implicitly.
+func parseSVGPathData(d string, t affine) ([][]Curve, error) {
+	var ret [][]Curve
+	var cur []Curve
+	var pos, start FloatVertex
+	flush := func() {
+		if len(cur) != 0 {
+			if last := cur[len(cur)-1].End; last != t.apply(start) {
+				cur = append(cur, Curve{Kind: CurveLine, End: t.apply(start)})
+			}
+			ret = append(ret, cur)
+		}
+		cur = nil
+	}
+	var cmd byte
+	i := 0
+	number := func() (float64, error) {
+		for i < len(d) && strings.IndexByte(" \t\r\n,", d[i]) != -1 {
+			i++
+		}
+		j := i
+		if j < len(d) && (d[j] == '+' || d[j] == '-') {
+			j++
+		}
+		dot, exp := false, false
+	scan:
+		for ; j < len(d); j++ {
+			c := d[j]
+			switch {
+			case '0' <= c && c <= '9':
+			case c == '.' && !dot && !exp:
+				dot = true
+			case (c == 'e' || c == 'E') && !exp:
+				exp = true
+				if j+1 < len(d) && (d[j+1] == '+' || d[j+1] == '-') {
+					j++
+				}
+			default:
+				break scan
+			}
+		}
+		v, err := strconv.ParseFloat(d[i:j], 64)
+		if err != nil {
+			return 0, fmt.Errorf("%w: path data at %d: %v", ErrInvalidSVG, i, err)
+		}
+		i = j
+		return v, nil
+	}
+	point := func(rel bool) (FloatVertex, error) {
+		x, err := number()
+		if err != nil {
+			return FloatVertex{}, err
+		}
+		y, err := number()
+		if err != nil {
+			return FloatVertex{}, err
+		}
+		if rel {
+			x, y = x+pos[0], y+pos[1]
+		}
+		return FloatVertex{x, y}, nil
+	}
+	for {
+		for i < len(d) && strings.IndexByte(" \t\r\n,", d[i]) != -1 {
+			i++
+		}
+		if len(d) <= i {
+			break
+		}
+		if c := d[i]; strings.IndexByte("MmLlHhVvCcZz", c) != -1 {
+			cmd = c
+			i++
+		} else if cmd == 0 || cmd == 'Z' || cmd == 'z' {
+			return nil, fmt.Errorf("%w: path data at %d: unexpected %q", ErrInvalidSVG, i, c)
+		}
+		rel := 'a' <= cmd
+		switch cmd {
+		case 'M', 'm':
+			v, err := point(rel)
+			if err != nil {
+				return nil, err
+			}
+			flush()
+			pos, start = v, v
+			// the subsequent pairs are the implicit lineto commands
+			cmd -= 'M' - 'L'
+		case 'L', 'l':
+			v, err := point(rel)
+			if err != nil {
+				return nil, err
+			}
+			cur = append(cur, Curve{Kind: CurveLine, End: t.apply(v)})
+			pos = v
+		case 'H', 'h', 'V', 'v':
+			n, err := number()
+			if err != nil {
+				return nil, err
+			}
+			k := 0
+			if cmd == 'V' || cmd == 'v' {
+				k = 1
+			}
+			if rel {
+				n += pos[k]
+			}
+			pos[k] = n
+			cur = append(cur, Curve{Kind: CurveLine, End: t.apply(pos)})
+		case 'C', 'c':
+			var vs [3]FloatVertex
+			for k := range vs {
+				v, err := point(rel)
+				if err != nil {
+					return nil, err
+				}
+				vs[k] = v
+			}
+			cur = append(cur, Curve{Kind: CurveCubic, C1: t.apply(vs[0]), C2: t.apply(vs[1]), End: t.apply(vs[2])})
+			pos = vs[2]
+		case 'Z', 'z':
+			flush()
+			pos = start
+		}
+	}
+	flush()
+	return ret, nil
+}
+
+// ReadPotraceGeoJSON reads a GeoJSON document written by potrace, with the -b
+// geojson option, and returns its polygons as a FloatPath on the canvas of the
+// size width x height. Since potrace writes the coordinates with the y-axis
+// pointing upward from the lower left corner, they are flipped vertically in
+// the canvas. The document may be a FeatureCollection, a Feature, or a bare
+// geometry, and the geometries may be Polygon or MultiPolygon objects. The
+// closing vertex of each ring is removed.
+func ReadPotraceGeoJSON(r io.Reader, width, height float64) (*FloatPath, error) {
+	var obj geoObject
+	if err := json.NewDecoder(r).Decode(&obj); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidEncoding, err)
+	}
+	ret := &FloatPath{Width: width, Height: height}
+	if err := obj.appendTo(ret); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// geoObject is a GeoJSON object read by ReadPotraceGeoJSON.
+type geoObject struct {
+	Type        string          `json:"type"`
+	Features    []geoObject     `json:"features"`
+	Geometry    *geoObject      `json:"geometry"`
+	Coordinates json.RawMessage `json:"coordinates"`
+}
+
+// appendTo appends the rings of the polygons of o to fp.
+func (o *geoObject) appendTo(fp *FloatPath) error {
+	var polys [][][][2]float64
+	switch o.Type {
+	case "FeatureCollection":
+		for i := range o.Features {
+			if err := o.Features[i].appendTo(fp); err != nil {
+				return err
+			}
+		}
+		return nil
+	case "Feature":
+		if o.Geometry == nil {
+			return nil
+		}
+		return o.Geometry.appendTo(fp)
+	case "Polygon":
+		var poly [][][2]float64
+		if err := json.Unmarshal(o.Coordinates, &poly); err != nil {
+			return fmt.Errorf("%w: %s: %v", ErrInvalidEncoding, o.Type, err)
+		}
+		polys = append(polys, poly)
+	case "MultiPolygon":
+		if err := json.Unmarshal(o.Coordinates, &polys); err != nil {
+			return fmt.Errorf("%w: %s: %v", ErrInvalidEncoding, o.Type, err)
+		}
+	default:
+		return fmt.Errorf("%w: unsupported type %q", ErrInvalidEncoding, o.Type)
+	}
+	for _, poly := range polys {
+		for _, ring := range poly {
+			if 1 < len(ring) && ring[0] == ring[len(ring)-1] {
+				ring = ring[:len(ring)-1]
+			}
+			if len(ring) == 0 {
+				continue
+			}
+			vs := make([]FloatVertex, len(ring))
+			for i, c := range ring {
+				vs[i] = FloatVertex{c[0], fp.Height - c[1]}
+			}
+			fp.Vertices = append(fp.Vertices, vs)
+		}
+	}
+	return nil
+}
+
+// PotraceComparison is the result of comparing a Path with the reference
+// result of potrace, returned by ComparePotrace.
+type PotraceComparison struct {
+	// NumPaths and RefNumPaths are the numbers of the closed paths.
+	NumPaths, RefNumPaths int
+
+	// NumVertices and RefNumVertices are the total numbers of the vertices.
+	// Those of the reference depend on the tolerance of the flattening.
+	NumVertices, RefNumVertices int
+
+	// Area and RefArea are the total areas of the ink, the areas of the
+	// holes subtracted.
+	Area, RefArea float64
+
+	// Diff is the bitmap image of the canvas size, in which the pixels
+	// rasterized differently are set to 1. It can be traced with New to
+	// visualize the differences.
+	Diff *bitarray.Buffer
+
+	// DiffPixels is the number of the pixels set in Diff, and DiffRatio is
+	// DiffPixels divided by the number of the pixels of the canvas.
+	DiffPixels int
+	DiffRatio  float64
+}
+
+// ComparePotrace compares p with the reference ref read by ReadPotraceSVG or
+// ReadPotraceGeoJSON, such as for the migration from potrace across an asset
+// corpus. Both are rasterized with FloatPath.Rasterize to compute the pixel
+// differences, so the canvas sizes of them, rounded up to the integers, must
+// be the same, otherwise ErrInvalidWidth is returned.
+func ComparePotrace(p *Path, ref *FloatPath) (*PotraceComparison, error) {
+	if w, h := int(math.Ceil(ref.Width)), int(math.Ceil(ref.Height)); w != p.Width || h != p.Height {
+		return nil, fmt.Errorf("%w: canvas %dx%d != %dx%d", ErrInvalidWidth, w, h, p.Width, p.Height)
+	}
+	ret := &PotraceComparison{
+		NumPaths:    p.NumPath(),
+		RefNumPaths: ref.NumPath(),
+		NumVertices: p.NumVertices(),
+	}
+	for _, vs := range p.Vertices {
+		ret.Area += float64(signedArea(vs))
+	}
+	// the orientations of the reference are unknown, so the areas are
+	// signed by the nesting depths
+	for _, vs := range orientEvenOdd(ref.Vertices) {
+		ret.RefNumVertices += len(vs)
+		ret.RefArea += floatSignedArea(vs)
+	}
+	ret.Diff = p.Float().Rasterize()
+	ret.Diff.XorAt(0, ref.Rasterize())
+	ret.DiffPixels = ret.Diff.OnesCount()
+	if n := ret.Diff.Len(); n != 0 {
+		ret.DiffRatio = float64(ret.DiffPixels) / float64(n)
+	}
+	return ret, nil
+}
@@ -0,0 +1,109 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/tunabay/go-bitarray"
+	"github.com/tunabay/go-bmppath"
+)
+
+const potraceSVG = `<?xml version="1.0" standalone="no"?>
+<!DOCTYPE svg PUBLIC "-//W3C//DTD SVG 20010904//EN"
+ "http://www.w3.org/TR/2001/REC-SVG-20010904/DTD/svg10.dtd">
+<svg version="1.0" xmlns="http://www.w3.org/2000/svg"
+ width="3.000000pt" height="3.000000pt" viewBox="0 0 3.000000 3.000000"
+ preserveAspectRatio="xMidYMid meet">
+<metadata>
+Created by potrace 1.16, written by Peter Selinger 2001-2019
+</metadata>
+<g transform="translate(0.000000,3.000000) scale(0.100000,-0.100000)"
+fill="#000000" stroke="none">
+<path d="M0 15 c0 -5 0 -10 0 -15 l30 0 0 30 -30 0 0 -15z m20 0 l0 -5 -10 0
+0 10 10 0 0 -5z"/>
+</g>
+</svg>
+`
+
+func ExampleComparePotrace() {
+	bmp := bitarray.NewBufferFromBitArray(
+		bitarray.MustParse(strings.Join([]string{
+			"111",
+			"101",
+			"110",
+		}, "")),
+	)
+	path, err := bmppath.New(bmp, 3)
+	if err != nil {
+		panic(err)
+	}
+
+	ref, err := bmppath.ReadPotraceSVG(strings.NewReader(potraceSVG), 0.1)
+	if err != nil {
+		panic(err)
+	}
+	c, err := bmppath.ComparePotrace(path, ref)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println("paths:", c.NumPaths, c.RefNumPaths)
+	fmt.Println("area:", c.Area, c.RefArea)
+	fmt.Printf("diff: %d (%.3f)\n", c.DiffPixels, c.DiffRatio)
+	fmt.Println(c.Diff)
+
+	// Output:
+	// paths: 1 2
+	// area: 7 8
+	// diff: 1 (0.111)
+	// 000000001
+}
+
+func TestReadPotraceGeoJSON(t *testing.T) {
+	const doc = `{
+"type": "FeatureCollection",
+"features": [
+{ "type": "Feature", "properties": { }, "geometry": { "type": "Polygon", "coordinates": [
+[ [0.000000, 1.500000], [0.000000, 0.000000], [3.000000, 0.000000], [3.000000, 3.000000], [0.000000, 3.000000], [0.000000, 1.500000] ],
+[ [2.000000, 1.500000], [2.000000, 1.000000], [1.000000, 1.000000], [1.000000, 2.000000], [2.000000, 2.000000], [2.000000, 1.500000] ]
+] } }
+]
+}`
+	ref, err := bmppath.ReadPotraceGeoJSON(strings.NewReader(doc), 3, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ps := make([]string, ref.NumPath())
+	for i := range ps {
+		ps[i] = ref.PathString(i)
+	}
+	got := strings.Join(ps, "\n")
+	want := "(0, 1.5), (0, 3), (3, 3), (3, 0), (0, 0)\n(2, 1.5), (2, 2), (1, 2), (1, 1), (2, 1)"
+	if got != want {
+		t.Errorf("unexpected paths:\ngot:\n%s\nwant:\n%s", got, want)
+	}
+
+	bmp := bitarray.NewBufferFromBitArray(bitarray.MustParse("111101111"))
+	path, err := bmppath.New(bmp, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := bmppath.ComparePotrace(path, ref)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.DiffPixels != 0 || c.Area != 8 || c.RefArea != 8 {
+		t.Errorf("unexpected comparison: %+v", c)
+	}
+
+	if _, err := bmppath.ComparePotrace(path, &bmppath.FloatPath{Width: 4, Height: 3}); err == nil {
+		t.Error("no error for the canvas size mismatch")
+	}
+	if _, err := bmppath.ReadPotraceGeoJSON(strings.NewReader(`{"type":"Point","coordinates":[0,0]}`), 3, 3); err == nil {
+		t.Error("no error for the unsupported type")
+	}
+}
@@ -0,0 +1,63 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath
+
+// Quantize returns a new Path with all the coordinates, the Width and the
+// Height snapped to the nearest multiples of grid, such as the font units or
+// the step resolution of CNC machines. The segments collapsed by snapping are
+// removed, and so are the closed paths collapsed into lines or points, so that
+// the result consists only of the closed paths with non-zero areas. grid must be
+// a positive integer otherwise it panics. p itself is not modified.
+func (p *Path) Quantize(grid int) *Path {
+	if grid < 1 {
+		panic("bmppath: non-positive grid")
+	}
+	snap := func(n int) int {
+		n += grid / 2
+		if n < 0 {
+			n -= grid - 1
+		}
+		return n / grid * grid
+	}
+	ret := &Path{
+		Width:    snap(p.Width),
+		Height:   snap(p.Height),
+		Vertices: make([][]Vertex, 0, len(p.Vertices)),
+	}
+	for i, vs := range p.Vertices {
+		nvs := make([]Vertex, len(vs))
+		for j, v := range vs {
+			nvs[j] = Vertex{snap(v[0]), snap(v[1])}
+		}
+		if nvs = simplifyVertices(nvs); len(nvs) < 4 || signedArea(nvs) == 0 {
+			continue
+		}
+		normalizeVertices(nvs)
+		ret.Vertices = append(ret.Vertices, nvs)
+		if p.Labels != nil {
+			ret.Labels = append(ret.Labels, p.PathLabel(i))
+		}
+	}
+	return ret
+}
+
+// simplifyVertices removes the duplicate vertices and the vertices between
+// collinear segments, including the ones at the tips of the spikes, from the
+// closed path vs, and returns the result.
+func simplifyVertices(vs []Vertex) []Vertex {
+	for changed := true; changed; {
+		changed = false
+		for i := 0; i < len(vs) && 2 < len(vs); {
+			a, b, c := vs[(i+len(vs)-1)%len(vs)], vs[i], vs[(i+1)%len(vs)]
+			if a == b || a[0] == b[0] && b[0] == c[0] || a[1] == b[1] && b[1] == c[1] {
+				vs = append(vs[:i], vs[i+1:]...)
+				changed = true
+				continue
+			}
+			i++
+		}
+	}
+	return vs
+}
@@ -0,0 +1,37 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath_test
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/tunabay/go-bitarray"
+	"github.com/tunabay/go-bmppath"
+)
+
+func ExamplePath_Quantize() {
+	bmp := bitarray.NewBufferFromBitArray(
+		bitarray.MustParse(strings.Join([]string{
+			"111100",
+			"111110",
+			"111100",
+			"000000",
+			"000001",
+		}, "")),
+	)
+	path, err := bmppath.New(bmp, 6)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(path.SVGDString())
+
+	q := path.Quantize(2)
+	fmt.Printf("%dx%d %s\n", q.Width, q.Height, q.SVGDString())
+
+	// Output:
+	// m0,0h4v1h1v1h-1v1h-4zm5,4h1v1h-1z
+	// 6x6 m0,0h4v4h-4z
+}
@@ -0,0 +1,103 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath
+
+import (
+	"image"
+	"sort"
+
+	"github.com/tunabay/go-bitarray"
+)
+
+// Rasterize fills the closed paths back into a binary bitmap image of the size
+// p.Width x p.Height, which is the reverse conversion of New. The pixels inside
+// the paths are set to 1, using the even-odd rule. The parts of the paths
+// outside the canvas are clipped.
+func (p *Path) Rasterize() *bitarray.Buffer {
+	return p.rasterize(0, 0, p.Width, p.Height)
+}
+
+// RasterizeScaled is identical to Rasterize except that the image is magnified
+// by the integer factor s, resulting in a bitmap image of the size
+// (p.Width * s) x (p.Height * s). s must be a positive integer otherwise it
+// panics.
+func (p *Path) RasterizeScaled(s int) *bitarray.Buffer {
+	return p.Scale(s).Rasterize()
+}
+
+// rasterize fills the closed paths of p into a bitmap of the size w x h whose
+// upper left corner is at (x0, y0), using the even-odd rule.
+func (p *Path) rasterize(x0, y0, w, h int) *bitarray.Buffer {
+	buf := bitarray.NewBuffer(w * h)
+	xs := make([][]int, h)
+	for _, vs := range p.Vertices {
+		for i, v0 := range vs {
+			v1 := vs[(i+1)%len(vs)]
+			if v0[0] != v1[0] {
+				continue
+			}
+			ya, yb := v0[1]-y0, v1[1]-y0
+			if yb < ya {
+				ya, yb = yb, ya
+			}
+			if ya < 0 {
+				ya = 0
+			}
+			if h < yb {
+				yb = h
+			}
+			for y := ya; y < yb; y++ {
+				xs[y] = append(xs[y], v0[0]-x0)
+			}
+		}
+	}
+	for y, row := range xs {
+		sort.Ints(row)
+		for i := 0; i+1 < len(row); i += 2 {
+			xa, xb := row[i], row[i+1]
+			if xa < 0 {
+				xa = 0
+			}
+			if w < xb {
+				xb = w
+			}
+			if xa < xb {
+				buf.FillBitsAt(w*y+xa, xb-xa, 1)
+			}
+		}
+	}
+	return buf
+}
+
+// fromBitmap traces the bitmap bm of the size w x h whose upper left corner is
+// at (x0, y0), and returns the result as a Path of the size width x height. The
+// closed paths are normalized and ordered in the coordinates of the canvas, so
+// that the result is identical to the one New returns for the whole canvas. It
+// panics if the grid points of bm overflow int, which can happen only on the
+// 32-bit platforms for the bitmaps of nearly 2^31 pixels.
+func fromBitmap(bm *bitarray.Buffer, x0, y0, w, h, width, height int) *Path {
+	ret := &Path{Width: width, Height: height}
+	if w < 1 || h < 1 {
+		return ret
+	}
+	g, err := newEdgeGrid(bm, w, h)
+	if err != nil {
+		panic("bmppath: " + err.Error())
+	}
+	g.origin = Vertex{x0, y0}
+	traced, err := g.trace(nil, &arena{})
+	if err != nil {
+		panic("bmppath: " + err.Error())
+	}
+	ret.Vertices = traced.Vertices
+	return ret
+}
+
+// bounds returns the rectangle that contains both the canvas and all the
+// vertices of p.
+func (p *Path) bounds() image.Rectangle {
+	r, _ := vertexBounds(append([][]Vertex{{{0, 0}, {p.Width, p.Height}}}, p.Vertices...)...)
+	return r
+}
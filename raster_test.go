@@ -0,0 +1,60 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath_test
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/tunabay/go-bitarray"
+	"github.com/tunabay/go-bmppath"
+)
+
+func TestPath_Rasterize(t *testing.T) {
+	const width = 37
+	h := "00000000000000000000000000000000000000fed4abf804" +
+		"119090402e8c4eba0174b0b5d00babd3ae804154550403fa" +
+		"aaafe000019000007f4c31880086d63f4020deeb90008123" +
+		"4e20053fbd5380109aaf6c031b5534000986af1900477688" +
+		"6806a0a9ffc026d3e2f80171a607b00abb74ff80007f546c" +
+		"03fb8cea001054931900baba2ff005d49cea002eaa9fe401" +
+		"054d59a00fe1276e00000000000000000000000000000000" +
+		"00000000"
+	src, _ := hex.DecodeString(h)
+	buf := bitarray.NewBufferFromByteSlice(src).Slice(0, width*width)
+	path, err := bmppath.New(buf, width)
+	if err != nil {
+		t.Fatalf("New(): %v", err)
+	}
+	if got, want := path.Rasterize().BitArray(), buf.BitArray(); !got.Equal(want) {
+		t.Errorf("round trip mismatch:\ngot:  %#b\nwant: %#b", got, want)
+	}
+}
+
+func ExamplePath_RasterizeScaled() {
+	bmp := bitarray.NewBufferFromBitArray(
+		bitarray.MustParse(strings.Join([]string{
+			"10",
+			"01",
+		}, "")),
+	)
+	path, err := bmppath.New(bmp, 2)
+	if err != nil {
+		panic(err)
+	}
+
+	scaled := path.RasterizeScaled(2)
+	for y := 0; y < 4; y++ {
+		fmt.Println(scaled.Slice(y*4, y*4+4))
+	}
+
+	// Output:
+	// 1100
+	// 1100
+	// 0011
+	// 0011
+}
@@ -0,0 +1,83 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath
+
+import (
+	"image"
+)
+
+// KeepRect returns a new Path that covers only the area inside the rectangle
+// r, which is the intersection of p and r. Only the area inside r is
+// re-traced, so this is much faster than Intersect for the common viewport
+// edits. The Width and Height are not changed. p itself is not modified.
+func (p *Path) KeepRect(r image.Rectangle) *Path {
+	r = r.Canon()
+	w, h := r.Dx(), r.Dy()
+	if w < 1 || h < 1 {
+		return &Path{Width: p.Width, Height: p.Height}
+	}
+	bm := p.rasterize(r.Min.X, r.Min.Y, w, h)
+	return fromBitmap(bm, r.Min.X, r.Min.Y, w, h, p.Width, p.Height)
+}
+
+// PunchRect returns a new Path with the area inside the rectangle r removed,
+// which is the difference of p and r. Only the closed paths around r are
+// re-traced, and the others are kept as they are, so this is much faster than
+// Subtract for the common margin edits. All the closed paths are then started
+// and ordered as New does, except that the ties of the distances between them
+// may be broken differently. The Width and Height are not changed. p itself is
+// not modified.
+func (p *Path) PunchRect(r image.Rectangle) *Path {
+	r = r.Canon()
+	boxes := make([]image.Rectangle, len(p.Vertices))
+	for i, vs := range p.Vertices {
+		boxes[i], _ = vertexBounds(vs)
+	}
+
+	// The region to re-trace must contain whole closed paths, including the
+	// holes of the outlines overlapping r, so it is grown until no other
+	// closed path overlaps it. The closed paths touching it at a corner are
+	// also included, since New merges them into one.
+	affected := make([]bool, len(p.Vertices))
+	region := r
+	for changed := !r.Empty(); changed; {
+		changed = false
+		for i, box := range boxes {
+			if !affected[i] && box.Overlaps(region.Inset(-1)) {
+				affected[i] = true
+				region = region.Union(box)
+				changed = true
+			}
+		}
+	}
+
+	ret := &Path{Width: p.Width, Height: p.Height}
+	sub := &Path{}
+	for i, vs := range p.Vertices {
+		if affected[i] {
+			sub.Vertices = append(sub.Vertices, vs)
+			continue
+		}
+		ret.Vertices = append(ret.Vertices, append([]Vertex(nil), vs...))
+	}
+	if len(sub.Vertices) == 0 {
+		return ret
+	}
+	w, h := region.Dx(), region.Dy()
+	bm := sub.rasterize(region.Min.X, region.Min.Y, w, h)
+	if hole := r.Intersect(region); !hole.Empty() {
+		for y := hole.Min.Y; y < hole.Max.Y; y++ {
+			off := w*(y-region.Min.Y) + hole.Min.X - region.Min.X
+			bm.FillBitsAt(off, hole.Dx(), 0)
+		}
+	}
+	traced := fromBitmap(bm, region.Min.X, region.Min.Y, w, h, p.Width, p.Height)
+	ret.Vertices = append(ret.Vertices, traced.Vertices...)
+	for _, vs := range ret.Vertices {
+		normalizeVertices(vs)
+	}
+	sortNearest(ret.Vertices)
+	return ret
+}
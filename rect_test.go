@@ -0,0 +1,119 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath_test
+
+import (
+	"image"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/tunabay/go-bitarray"
+	"github.com/tunabay/go-bmppath"
+)
+
+func TestPath_PunchRect(t *testing.T) {
+	bmp := bitarray.NewBufferFromBitArray(
+		bitarray.MustParse(strings.Join([]string{
+			"11111001",
+			"10001000",
+			"10101011",
+			"10001011",
+			"11111000",
+			"00000011",
+		}, "")),
+	)
+	path, err := bmppath.New(bmp, 8)
+	if err != nil {
+		t.Fatalf("New(): %v", err)
+	}
+	frame, err := bmppath.New(bitarray.NewBufferFromBitArray(bitarray.MustParse(strings.Repeat("1", 48))), 8)
+	if err != nil {
+		t.Fatalf("New(): %v", err)
+	}
+
+	rects := []image.Rectangle{
+		image.Rect(0, 0, 0, 0),
+		image.Rect(2, 2, 3, 3),
+		image.Rect(1, 1, 3, 2),
+		image.Rect(4, 0, 7, 3),
+		image.Rect(6, 6, 2, 0),
+		image.Rect(-5, -5, 20, 20),
+		image.Rect(-5, 1, 20, 2),
+	}
+	for _, r := range rects {
+		rp, err := bmppath.New(bitarray.NewBuffer(48), 8)
+		if err != nil {
+			t.Fatalf("New(): %v", err)
+		}
+		if c := r.Canon().Intersect(image.Rect(0, 0, 8, 6)); !c.Empty() {
+			rp = frame.KeepRect(c)
+		}
+
+		if got, want := path.PunchRect(r), path.Subtract(rp); !got.Equal(want) {
+			t.Errorf("PunchRect(%v): unexpected result:\n%s", r, bmppath.Diff(want, got))
+		}
+		if got, want := path.KeepRect(r), path.Intersect(rp); !got.Equal(want) {
+			t.Errorf("KeepRect(%v): unexpected result:\n%s", r, bmppath.Diff(want, got))
+		}
+	}
+}
+
+func TestPath_KeepRect_canonical(t *testing.T) {
+	const width, height = 12, 10
+	r := rand.New(rand.NewSource(1))
+	for n := 0; n < 100; n++ {
+		bmp := bitarray.NewBuffer(width * height)
+		for i := 0; i < width*height; i++ {
+			bmp.PutBitAt(i, byte(r.Intn(2)))
+		}
+		path, err := bmppath.New(bmp, width)
+		if err != nil {
+			t.Fatalf("New(): %v", err)
+		}
+		x0, y0 := r.Intn(width), r.Intn(height)
+		rect := image.Rect(x0, y0, x0+1+r.Intn(width-x0), y0+1+r.Intn(height-y0))
+
+		// the pixels outside rect are cleared
+		masked := bitarray.NewBuffer(width * height)
+		for y := rect.Min.Y; y < rect.Max.Y; y++ {
+			for x := rect.Min.X; x < rect.Max.X; x++ {
+				masked.PutBitAt(width*y+x, bmp.BitAt(width*y+x))
+			}
+		}
+		want, err := bmppath.New(masked, width)
+		if err != nil {
+			t.Fatalf("New(): %v", err)
+		}
+		if got := path.KeepRect(rect); !reflect.DeepEqual(got, want) {
+			t.Errorf("#%d: KeepRect(%v): got %s, want %s", n, rect, got.SVGDString(), want.SVGDString())
+		}
+
+		// the pixels inside rect are cleared
+		punched := bitarray.NewBuffer(width * height)
+		for i := 0; i < width*height; i++ {
+			if !image.Pt(i%width, i/width).In(rect) {
+				punched.PutBitAt(i, bmp.BitAt(i))
+			}
+		}
+		if want, err = bmppath.New(punched, width); err != nil {
+			t.Fatalf("New(): %v", err)
+		}
+		// the order of the closed paths at the same distance is not
+		// determined
+		got := path.PunchRect(rect)
+		if !got.Equal(want) {
+			t.Errorf("#%d: PunchRect(%v): got %s, want %s", n, rect, got.SVGDString(), want.SVGDString())
+		}
+		for i, vs := range got.Vertices {
+			for _, v := range vs {
+				if v[0]*v[0]+v[1]*v[1] < vs[0][0]*vs[0][0]+vs[0][1]*vs[0][1] {
+					t.Errorf("#%d: PunchRect(%v): closed path #%d starts at %v, not at %v", n, rect, i, vs[0], v)
+				}
+			}
+		}
+	}
+}
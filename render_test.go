@@ -0,0 +1,94 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath_test
+
+import (
+	"image"
+	"strings"
+	"testing"
+
+	"github.com/tunabay/go-bitarray"
+	"github.com/tunabay/go-bmppath"
+)
+
+func TestFloatPath_Render(t *testing.T) {
+	fp := &bmppath.FloatPath{
+		Width:  2,
+		Height: 2,
+		Vertices: [][]bmppath.FloatVertex{
+			{{0.5, 0.5}, {1.5, 0.5}, {1.5, 1.5}, {0.5, 1.5}},
+		},
+	}
+	img, ok := fp.Render(1).(*image.Gray)
+	if !ok {
+		t.Fatalf("unexpected type: %T", fp.Render(1))
+	}
+	if want := []uint8{191, 191, 191, 191}; string(img.Pix) != string(want) {
+		t.Errorf("unexpected pixels: %v, want %v", img.Pix, want)
+	}
+
+	// the holes are not filled, and the paths outside the canvas are clipped
+	fp = &bmppath.FloatPath{
+		Width:  4,
+		Height: 4,
+		Vertices: [][]bmppath.FloatVertex{
+			{{-2, -2}, {4, -2}, {4, 4}, {-2, 4}},
+			{{1, 1}, {1, 3}, {3, 3}, {3, 1}},
+		},
+	}
+	img = fp.Render(2).(*image.Gray)
+	if img.Bounds() != image.Rect(0, 0, 8, 8) {
+		t.Fatalf("unexpected bounds: %v", img.Bounds())
+	}
+	for y := 0; y < 8; y++ {
+		for x := 0; x < 8; x++ {
+			want := uint8(0)
+			if 2 <= x && x < 6 && 2 <= y && y < 6 {
+				want = 255
+			}
+			if got := img.GrayAt(x, y).Y; got != want {
+				t.Errorf("(%d, %d): unexpected value: %d, want %d", x, y, got, want)
+			}
+		}
+	}
+}
+
+func TestPath_Render(t *testing.T) {
+	bmp := bitarray.NewBufferFromBitArray(
+		bitarray.MustParse(strings.Join([]string{
+			"0000",
+			"0110",
+			"0110",
+			"0000",
+		}, "")),
+	)
+	path, err := bmppath.New(bmp, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	img := path.Render(8).(*image.Gray)
+	if img.Bounds() != image.Rect(0, 0, 32, 32) {
+		t.Fatalf("unexpected bounds: %v", img.Bounds())
+	}
+	if v := img.GrayAt(16, 16).Y; v != 0 {
+		t.Errorf("center: unexpected value: %d", v)
+	}
+	if v := img.GrayAt(1, 1).Y; v != 255 {
+		t.Errorf("background: unexpected value: %d", v)
+	}
+	// the corners of the square are rounded with the shades of gray
+	if v := img.GrayAt(8, 8).Y; v != 255 {
+		t.Errorf("corner: unexpected value: %d", v)
+	}
+	gray := 0
+	for _, v := range img.Pix {
+		if v != 0 && v != 255 {
+			gray++
+		}
+	}
+	if gray == 0 {
+		t.Error("not anti-aliased")
+	}
+}
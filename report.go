@@ -0,0 +1,82 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath
+
+// Report is the statistics of the closed paths for the quality assurance,
+// returned by Inspect. The traces of the noisy scans, such as the ones with
+// moiré patterns, typically have many tiny closed paths and deep nesting, and
+// the broken or hand-edited Paths have the orientation errors or the segments
+// off the axes.
+type Report struct {
+	// NumPaths is the number of closed paths.
+	NumPaths int
+
+	// Clockwise and Counterclockwise are the numbers of the closed paths
+	// running in each direction on the screen coordinates. The closed paths
+	// with zero areas are not counted in either.
+	Clockwise, Counterclockwise int
+
+	// Depths is the distribution of the nesting depths, where Depths[d] is
+	// the number of the closed paths at the depth d.
+	Depths []int
+
+	// OrientationErrors is the number of the closed paths whose directions
+	// do not match their nesting depths, i.e. the outlines at even depths
+	// should run clockwise and the holes at odd depths counterclockwise.
+	OrientationErrors int
+
+	// NonRectilinear is the number of the segments that are neither
+	// horizontal nor vertical.
+	NonRectilinear int
+
+	// Degenerate is the number of the segments of zero length, and the
+	// vertices between collinear segments which could be removed.
+	Degenerate int
+
+	// Tiny is the number of the closed paths enclosing 4 pixels or less.
+	Tiny int
+}
+
+// Inspect returns the statistics of the closed paths of p, so that the
+// suspicious traces can be flagged before further processing.
+func (p *Path) Inspect() *Report {
+	r := &Report{NumPaths: len(p.Vertices)}
+	parents := p.parents()
+	for i, vs := range p.Vertices {
+		a := signedArea(vs)
+		switch {
+		case 0 < a:
+			r.Clockwise++
+		case a < 0:
+			r.Counterclockwise++
+		}
+		if abs64(a) <= 4 {
+			r.Tiny++
+		}
+		d := 0
+		for j := parents[i]; j != -1; j = parents[j] {
+			d++
+		}
+		for len(r.Depths) <= d {
+			r.Depths = append(r.Depths, 0)
+		}
+		r.Depths[d]++
+		if a != 0 && (a < 0) != (d%2 == 1) {
+			r.OrientationErrors++
+		}
+		for k, v0 := range vs {
+			v1, v2 := vs[(k+1)%len(vs)], vs[(k+2)%len(vs)]
+			switch {
+			case v0 == v1:
+				r.Degenerate++
+			case v0[0] != v1[0] && v0[1] != v1[1]:
+				r.NonRectilinear++
+			case v1 != v2 && (v0[0] == v1[0] && v1[0] == v2[0] || v0[1] == v1[1] && v1[1] == v2[1]):
+				r.Degenerate++
+			}
+		}
+	}
+	return r
+}
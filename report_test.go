@@ -0,0 +1,41 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath_test
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/tunabay/go-bitarray"
+	"github.com/tunabay/go-bmppath"
+)
+
+func ExamplePath_Inspect() {
+	bmp := bitarray.NewBufferFromBitArray(
+		bitarray.MustParse(strings.Join([]string{
+			"11111110",
+			"10000010",
+			"10111010",
+			"10101010",
+			"10111010",
+			"10000010",
+			"11111110",
+			"00000001",
+		}, "")),
+	)
+	path, err := bmppath.New(bmp, 8)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Printf("%+v\n", *path.Inspect())
+
+	// broken one with a reversed outline and a diagonal segment
+	path.Vertices = append(path.Vertices, []bmppath.Vertex{{8, 0}, {10, 2}, {8, 2}})
+	fmt.Printf("%+v\n", *path.ReversePath(0).Inspect())
+
+	// Output:
+	// {NumPaths:4 Clockwise:2 Counterclockwise:2 Depths:[1 1 1 1] OrientationErrors:0 NonRectilinear:0 Degenerate:0 Tiny:1}
+	// {NumPaths:5 Clockwise:2 Counterclockwise:3 Depths:[2 1 1 1] OrientationErrors:1 NonRectilinear:1 Degenerate:0 Tiny:2}
+}
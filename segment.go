@@ -0,0 +1,42 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath
+
+import (
+	"fmt"
+)
+
+// Segment represents an edge of a closed path, the line segment from the
+// vertex A to the vertex B.
+type Segment struct {
+	A, B Vertex
+}
+
+// String returns the string representation of a Segment in "(x, y)-(x, y)"
+// format.
+func (s Segment) String() string { return fmt.Sprintf("%s-%s", s.A, s.B) }
+
+// Horizontal reports whether the Segment is parallel to the x-axis.
+func (s Segment) Horizontal() bool { return s.A[1] == s.B[1] && s.A[0] != s.B[0] }
+
+// Vertical reports whether the Segment is parallel to the y-axis.
+func (s Segment) Vertical() bool { return s.A[0] == s.B[0] && s.A[1] != s.B[1] }
+
+// Len returns the length of the Segment. Since the paths created by New
+// consist only of horizontal and vertical edges, it is measured in the
+// Manhattan distance.
+func (s Segment) Len() int { return abs(s.B[0]-s.A[0]) + abs(s.B[1]-s.A[1]) }
+
+// PathSegments returns the edges of the closed path specified by the index n,
+// including the closing edge from the last vertex to the first one. n must be
+// less than p.NumPath() otherwise it panics.
+func (p *Path) PathSegments(n int) []Segment {
+	vs := p.Vertices[n]
+	ret := make([]Segment, len(vs))
+	for i, v := range vs {
+		ret[i] = Segment{A: v, B: vs[(i+1)%len(vs)]}
+	}
+	return ret
+}
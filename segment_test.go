@@ -0,0 +1,38 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath_test
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/tunabay/go-bitarray"
+	"github.com/tunabay/go-bmppath"
+)
+
+func ExamplePath_PathSegments() {
+	bmp := bitarray.NewBufferFromBitArray(
+		bitarray.MustParse(strings.Join([]string{
+			"110",
+			"100",
+		}, "")),
+	)
+	path, err := bmppath.New(bmp, 3)
+	if err != nil {
+		panic(err)
+	}
+
+	for _, s := range path.PathSegments(0) {
+		fmt.Printf("%s: len=%d, h=%t, v=%t\n", s, s.Len(), s.Horizontal(), s.Vertical())
+	}
+
+	// Output:
+	// (0, 0)-(2, 0): len=2, h=true, v=false
+	// (2, 0)-(2, 1): len=1, h=false, v=true
+	// (2, 1)-(1, 1): len=1, h=true, v=false
+	// (1, 1)-(1, 2): len=1, h=false, v=true
+	// (1, 2)-(0, 2): len=1, h=true, v=false
+	// (0, 2)-(0, 0): len=2, h=false, v=true
+}
@@ -0,0 +1,122 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/tunabay/go-bitarray"
+)
+
+// ErrOutOfBounds is the error thrown when the specified pixel is outside the
+// bitmap image.
+var ErrOutOfBounds = errors.New("out of bounds")
+
+// TraceContourAt traces only the ink component containing the pixel at (x, y)
+// of the bitmap image bm, skipping the rest of the image. The ink component
+// consists of the ink pixels 8-connected to (x, y), which New traces as one
+// outline with its holes. This is much faster than tracing the whole image for
+// the interactive tools selecting a shape by click. The returned Path has the
+// same canvas as the whole image. If the pixel at (x, y) is not ink, the
+// returned Path has no closed paths.
+func TraceContourAt(bm *bitarray.Buffer, width, x, y int) (*Path, error) {
+	height, err := bitmapHeight(bm, width)
+	if err != nil {
+		return nil, err
+	}
+	if x < 0 || y < 0 || width <= x || height <= y {
+		return nil, fmt.Errorf("%w: (%d, %d) not in %dx%d", ErrOutOfBounds, x, y, width, height)
+	}
+	if bm.BitAt(width*y+x) == 0 {
+		return &Path{Width: width, Height: height}, nil
+	}
+	comp, lo, hi := floodFill(bm, width, height, x, y, Connect8)
+	w, h := hi[0]-lo[0], hi[1]-lo[1]
+	sub := bitarray.NewBuffer(w * h)
+	for _, i := range comp {
+		sub.PutBitAt(w*(i/width-lo[1])+i%width-lo[0], 1)
+	}
+	return fromBitmap(sub, lo[0], lo[1], w, h, width, height), nil
+}
+
+// Connectivity specifies which neighboring pixels are considered connected.
+type Connectivity int
+
+const (
+	// Connect4 connects the pixels sharing an edge.
+	Connect4 Connectivity = 4
+
+	// Connect8 connects the pixels sharing an edge or a corner.
+	Connect8 Connectivity = 8
+)
+
+// FloodComponent returns the bit mask of the connected component containing
+// the pixel at (x, y) of the bitmap image bm. The component consists of the
+// pixels of the same value as (x, y), connected as specified by conn, and the
+// pixels in the returned mask of the same size as bm are set to 1. Combined
+// with TraceContourAt, it can be used to implement the selection tools.
+func FloodComponent(bm *bitarray.Buffer, width, x, y int, conn Connectivity) (*bitarray.Buffer, error) {
+	height, err := bitmapHeight(bm, width)
+	if err != nil {
+		return nil, err
+	}
+	if x < 0 || y < 0 || width <= x || height <= y {
+		return nil, fmt.Errorf("%w: (%d, %d) not in %dx%d", ErrOutOfBounds, x, y, width, height)
+	}
+	mask := bitarray.NewBuffer(width * height)
+	comp, _, _ := floodFill(bm, width, height, x, y, conn)
+	for _, i := range comp {
+		mask.PutBitAt(i, 1)
+	}
+	return mask, nil
+}
+
+// floodFill returns the indexes of the pixels of the component containing the
+// pixel at (x, y) of the bitmap image bm, along with its bounding box as the
+// upper left and lower right corners. The component consists of the pixels of
+// the same value as (x, y), connected as specified by conn.
+func floodFill(bm *bitarray.Buffer, width, height, x, y int, conn Connectivity) ([]int, Vertex, Vertex) {
+	start := width*y + x
+	val := bm.BitAt(start)
+	seen := map[int]bool{start: true}
+	stack, comp := []int{start}, []int(nil)
+	lo, hi := Vertex{x, y}, Vertex{x + 1, y + 1}
+	for len(stack) != 0 {
+		i := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		comp = append(comp, i)
+		cx, cy := i%width, i/width
+		if cx < lo[0] {
+			lo[0] = cx
+		}
+		if cy < lo[1] {
+			lo[1] = cy
+		}
+		if hi[0] <= cx {
+			hi[0] = cx + 1
+		}
+		if hi[1] <= cy {
+			hi[1] = cy + 1
+		}
+		for dy := -1; dy <= 1; dy++ {
+			for dx := -1; dx <= 1; dx++ {
+				if (dx == 0 && dy == 0) || (conn != Connect8 && dx != 0 && dy != 0) {
+					continue
+				}
+				nx, ny := cx+dx, cy+dy
+				if nx < 0 || ny < 0 || width <= nx || height <= ny {
+					continue
+				}
+				j := width*ny + nx
+				if !seen[j] && bm.BitAt(j) == val {
+					seen[j] = true
+					stack = append(stack, j)
+				}
+			}
+		}
+	}
+	return comp, lo, hi
+}
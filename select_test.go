@@ -0,0 +1,103 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/tunabay/go-bitarray"
+	"github.com/tunabay/go-bmppath"
+)
+
+func ExampleTraceContourAt() {
+	bmp := bitarray.NewBufferFromBitArray(
+		bitarray.MustParse(strings.Join([]string{
+			"11100",
+			"10100",
+			"11110",
+			"00001",
+			"11000",
+		}, "")),
+	)
+
+	for _, pt := range [][2]int{{0, 0}, {4, 3}, {1, 4}, {1, 1}} {
+		path, err := bmppath.TraceContourAt(bmp, 5, pt[0], pt[1])
+		if err != nil {
+			panic(err)
+		}
+		fmt.Printf("%v: %s\n", pt, path.SVGDString())
+	}
+
+	// Output:
+	// [0 0]: m0,0h3v2h1v1h1v1h-1v-1h-4zm1,1v1h1v-1z
+	// [4 3]: m0,0h3v2h1v1h1v1h-1v-1h-4zm1,1v1h1v-1z
+	// [1 4]: m0,4h2v1h-2z
+	// [1 1]:
+}
+
+func TestTraceContourAt_error(t *testing.T) {
+	bmp := bitarray.NewBuffer(20)
+	if _, err := bmppath.TraceContourAt(bmp, 5, 5, 0); !errors.Is(err, bmppath.ErrOutOfBounds) {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if _, err := bmppath.TraceContourAt(bmp, 3, 0, 0); !errors.Is(err, bmppath.ErrInvalidBitmap) {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func ExampleFloodComponent() {
+	bmp := bitarray.NewBufferFromBitArray(
+		bitarray.MustParse(strings.Join([]string{
+			"1100",
+			"1010",
+			"0011",
+		}, "")),
+	)
+
+	for _, conn := range []bmppath.Connectivity{bmppath.Connect4, bmppath.Connect8} {
+		mask, err := bmppath.FloodComponent(bmp, 4, 0, 0, conn)
+		if err != nil {
+			panic(err)
+		}
+		fmt.Println(mask)
+	}
+	mask, err := bmppath.FloodComponent(bmp, 4, 3, 0, bmppath.Connect4)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(mask)
+
+	// Output:
+	// 110010000000
+	// 110010100011
+	// 001100010000
+}
+
+func TestTraceContourAt_canonical(t *testing.T) {
+	// the sub-image of the component starts at (4, 0), where both (5, 0)
+	// and (4, 1) are the nearest to its upper left corner, but only (4, 1)
+	// is the nearest to the origin of the canvas
+	bmp := bitarray.NewBufferFromBitArray(
+		bitarray.MustParse(strings.Join([]string{
+			"00000110",
+			"00001110",
+			"00001100",
+		}, "")),
+	)
+	want, err := bmppath.New(bmp, 8)
+	if err != nil {
+		t.Fatalf("New(): %v", err)
+	}
+	got, err := bmppath.TraceContourAt(bmp, 8, 5, 1)
+	if err != nil {
+		t.Fatalf("TraceContourAt(): %v", err)
+	}
+	if got.SVGDString() != want.SVGDString() {
+		t.Errorf("unexpected result: got %s, want %s", got.SVGDString(), want.SVGDString())
+	}
+}
@@ -0,0 +1,515 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package sfntpath
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"github.com/tunabay/go-bmppath"
+)
+
+// ErrOutOfRange is returned when a value does not fit in the font file.
+var ErrOutOfRange = errors.New("out of range")
+
+// ErrInvalidRune is returned when a rune is not a valid Unicode code point.
+var ErrInvalidRune = errors.New("invalid rune")
+
+// FontOptions represents the options for WriteFont.
+type FontOptions struct {
+	// FamilyName is the family name of the font. If empty, "Bitmap" is
+	// used.
+	FamilyName string
+
+	// StyleName is the style name of the font. If empty, "Regular" is used.
+	StyleName string
+
+	// UnitsPerEm is the number of the font units per em. Zero means the
+	// default value 1000.
+	UnitsPerEm int
+
+	// EmPixels is the number of the pixels per em of the bitmap font. Zero
+	// means the height of the tallest canvas of the glyphs.
+	EmPixels int
+
+	// Baseline is the y-coordinate of the baseline in pixels, from the top
+	// of the canvas of each glyph. The pixels below it are the descenders.
+	// Zero means EmPixels, that is, the font has no descenders.
+	Baseline int
+
+	// Advance is the advance width of all the glyphs in pixels, for the
+	// monospaced fonts. Zero means the width of the canvas of each glyph.
+	Advance int
+}
+
+// WriteFont writes a minimal TrueType font file, which can be installed with
+// the .ttf or .otf extension, consisting of the glyphs converted from the Paths
+// by Contours in the TrueType format. The glyphs are mapped from the runes,
+// the keys of glyphs, with the cmap table. The Paths are usually traced from
+// the individual glyphs of a bitmap font, on the canvases of the same height
+// sharing the baseline. A Path with no closed paths, such as the one for the
+// space, results in an empty glyph with only the advance width. nil opts is the
+// same as the zero value of FontOptions.
+func WriteFont(w io.Writer, glyphs map[rune]*bmppath.Path, opts *FontOptions) error {
+	if opts == nil {
+		opts = &FontOptions{}
+	}
+	fb, err := newFontBuilder(glyphs, opts)
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(fb.build()); err != nil {
+		return fmt.Errorf("write failure: %w", err)
+	}
+	return nil
+}
+
+// fontGlyph is a glyph of the font being built.
+type fontGlyph struct {
+	contours [][]Point
+	advance  int
+	xMin     int
+	yMin     int
+	xMax     int
+	yMax     int
+}
+
+// fontBuilder holds the glyphs and the metrics of the font being built.
+type fontBuilder struct {
+	family, style    string
+	upm              int
+	ascent, descent  int
+	runes            []rune
+	glyphs           []*fontGlyph // glyphs[0] is .notdef
+	xMin, yMin       int
+	xMax, yMax       int
+	maxPoints        int
+	maxContours      int
+	maxAdvance       int
+	fixedPitch       bool
+	firstCh, lastCh  int
+	avgAdvance       int
+	minLSB, minRSB   int
+	maxExtent        int
+	hasSupplementary bool
+}
+
+func newFontBuilder(glyphs map[rune]*bmppath.Path, opts *FontOptions) (*fontBuilder, error) {
+	fb := &fontBuilder{family: opts.FamilyName, style: opts.StyleName, upm: opts.UnitsPerEm}
+	if fb.family == "" {
+		fb.family = "Bitmap"
+	}
+	if fb.style == "" {
+		fb.style = "Regular"
+	}
+	if fb.upm == 0 {
+		fb.upm = 1000
+	}
+	if fb.upm < 16 || 16384 < fb.upm {
+		return nil, fmt.Errorf("%w: units per em %d", ErrOutOfRange, fb.upm)
+	}
+	em := opts.EmPixels
+	for _, p := range glyphs {
+		if opts.EmPixels == 0 && em < p.Height {
+			em = p.Height
+		}
+	}
+	if em == 0 {
+		em = 1
+	}
+	baseline := opts.Baseline
+	if baseline == 0 {
+		baseline = em
+	}
+	s := float64(fb.upm) / float64(em)
+	units := func(px int) int { return int(math.Round(float64(px) * s)) }
+	fb.ascent, fb.descent = units(baseline), units(em-baseline)
+
+	for r := range glyphs {
+		if !utf8.ValidRune(r) {
+			return nil, fmt.Errorf("%w: %U", ErrInvalidRune, r)
+		}
+		fb.runes = append(fb.runes, r)
+		if 0xffff <= r {
+			fb.hasSupplementary = true
+		}
+	}
+	sort.Slice(fb.runes, func(i, j int) bool { return fb.runes[i] < fb.runes[j] })
+	if 0xfffe < len(fb.runes) {
+		return nil, fmt.Errorf("%w: %d glyphs", ErrOutOfRange, len(fb.runes))
+	}
+
+	defAdvance := units(opts.Advance)
+	if defAdvance == 0 {
+		defAdvance = fb.upm / 2
+	}
+	fb.glyphs = append(fb.glyphs, &fontGlyph{advance: defAdvance}) // .notdef
+	copts := &Options{Format: TrueType, UnitsPerEm: fb.upm, EmPixels: em, Baseline: baseline}
+	for _, r := range fb.runes {
+		p := glyphs[r]
+		g := &fontGlyph{contours: Contours(p, copts), advance: units(opts.Advance)}
+		if opts.Advance == 0 {
+			g.advance = units(p.Width)
+		}
+		npts := 0
+		for i, c := range g.contours {
+			for j, q := range c {
+				if i == 0 && j == 0 {
+					g.xMin, g.yMin, g.xMax, g.yMax = q.X, q.Y, q.X, q.Y
+				}
+				g.xMin, g.xMax = minInt(g.xMin, q.X), maxInt(g.xMax, q.X)
+				g.yMin, g.yMax = minInt(g.yMin, q.Y), maxInt(g.yMax, q.Y)
+			}
+			npts += len(c)
+		}
+		for _, v := range []int{g.xMin, g.yMin, g.xMax, g.yMax} {
+			if v < math.MinInt16 || math.MaxInt16 < v {
+				return nil, fmt.Errorf("%w: coordinate %d of %U", ErrOutOfRange, v, r)
+			}
+		}
+		if g.advance < 0 || math.MaxUint16 < g.advance || math.MaxUint16 < npts {
+			return nil, fmt.Errorf("%w: glyph %U", ErrOutOfRange, r)
+		}
+		fb.maxPoints = maxInt(fb.maxPoints, npts)
+		fb.maxContours = maxInt(fb.maxContours, len(g.contours))
+		fb.glyphs = append(fb.glyphs, g)
+	}
+
+	// the global metrics
+	first := true
+	fb.fixedPitch = true
+	total := 0
+	for _, g := range fb.glyphs {
+		total += g.advance
+		fb.maxAdvance = maxInt(fb.maxAdvance, g.advance)
+		if g.advance != fb.glyphs[0].advance {
+			fb.fixedPitch = false
+		}
+		if len(g.contours) == 0 {
+			continue
+		}
+		if first {
+			fb.xMin, fb.yMin, fb.xMax, fb.yMax = g.xMin, g.yMin, g.xMax, g.yMax
+			fb.minLSB, fb.minRSB, fb.maxExtent = g.xMin, g.advance-g.xMax, g.xMax
+			first = false
+		}
+		fb.xMin, fb.xMax = minInt(fb.xMin, g.xMin), maxInt(fb.xMax, g.xMax)
+		fb.yMin, fb.yMax = minInt(fb.yMin, g.yMin), maxInt(fb.yMax, g.yMax)
+		fb.minLSB = minInt(fb.minLSB, g.xMin)
+		fb.minRSB = minInt(fb.minRSB, g.advance-g.xMax)
+		fb.maxExtent = maxInt(fb.maxExtent, g.xMax)
+	}
+	fb.avgAdvance = total / len(fb.glyphs)
+	if len(fb.runes) != 0 {
+		fb.firstCh = minInt(int(fb.runes[0]), 0xffff)
+		fb.lastCh = minInt(int(fb.runes[len(fb.runes)-1]), 0xffff)
+	}
+	return fb, nil
+}
+
+// build returns the font file.
+func (fb *fontBuilder) build() []byte {
+	glyf, loca := fb.glyf()
+	tables := []struct {
+		tag  string
+		data []byte
+	}{
+		{"OS/2", fb.os2()},
+		{"cmap", fb.cmap()},
+		{"glyf", glyf},
+		{"head", fb.head()},
+		{"hhea", fb.hhea()},
+		{"hmtx", fb.hmtx()},
+		{"loca", loca},
+		{"maxp", fb.maxp()},
+		{"name", fb.name()},
+		{"post", fb.post()},
+	}
+	var f fontBuf
+	es := floorLog2(len(tables))
+	f.u32(0x00010000)
+	f.u16(len(tables), 16<<es, es, len(tables)*16-16<<es)
+	offset := 12 + len(tables)*16
+	headAt := 0
+	for _, t := range tables {
+		if t.tag == "head" {
+			headAt = offset
+		}
+		f.b = append(f.b, t.tag...)
+		f.u32(int(checksum(t.data)), offset, len(t.data))
+		offset += (len(t.data) + 3) &^ 3
+	}
+	for _, t := range tables {
+		f.b = append(f.b, t.data...)
+		f.pad()
+	}
+	// checkSumAdjustment of the head table
+	adj := 0xb1b0afba - checksum(f.b)
+	f.b[headAt+8], f.b[headAt+9], f.b[headAt+10], f.b[headAt+11] = byte(adj>>24), byte(adj>>16), byte(adj>>8), byte(adj)
+	return f.b
+}
+
+// glyf returns the glyf table and the loca table in the long format.
+func (fb *fontBuilder) glyf() ([]byte, []byte) {
+	var glyf, loca fontBuf
+	for _, g := range fb.glyphs {
+		loca.u32(len(glyf.b))
+		if len(g.contours) == 0 {
+			continue
+		}
+		glyf.u16(len(g.contours), g.xMin, g.yMin, g.xMax, g.yMax)
+		end := -1
+		for _, c := range g.contours {
+			end += len(c)
+			glyf.u16(end)
+		}
+		glyf.u16(0) // instructionLength
+		for _, c := range g.contours {
+			for range c {
+				glyf.b = append(glyf.b, 0x01) // ON_CURVE_POINT, int16 deltas
+			}
+		}
+		for axis := 0; axis < 2; axis++ {
+			prev := 0
+			for _, c := range g.contours {
+				for _, q := range c {
+					v := q.X
+					if axis == 1 {
+						v = q.Y
+					}
+					glyf.u16(v - prev)
+					prev = v
+				}
+			}
+		}
+		glyf.pad()
+	}
+	loca.u32(len(glyf.b))
+	return glyf.b, loca.b
+}
+
+func (fb *fontBuilder) head() []byte {
+	var f fontBuf
+	f.u32(0x00010000, 0x00010000, 0, 0x5f0f3cf5)
+	f.u16(0x0009, fb.upm) // baseline at y=0, integer scaling
+	f.u32(0, 0, 0, 0)     // created and modified
+	f.u16(fb.xMin, fb.yMin, fb.xMax, fb.yMax)
+	f.u16(0, 8, 2, 1, 0) // macStyle, lowestRecPPEM, fontDirectionHint, long loca, glyphDataFormat
+	return f.b
+}
+
+func (fb *fontBuilder) hhea() []byte {
+	var f fontBuf
+	f.u32(0x00010000)
+	f.u16(fb.ascent, -fb.descent, 0, fb.maxAdvance, fb.minLSB, fb.minRSB, fb.maxExtent)
+	f.u16(1, 0, 0, 0, 0, 0, 0, 0, len(fb.glyphs))
+	return f.b
+}
+
+func (fb *fontBuilder) hmtx() []byte {
+	var f fontBuf
+	for _, g := range fb.glyphs {
+		f.u16(g.advance, g.xMin)
+	}
+	return f.b
+}
+
+func (fb *fontBuilder) maxp() []byte {
+	var f fontBuf
+	f.u32(0x00010000)
+	f.u16(len(fb.glyphs), fb.maxPoints, fb.maxContours, 0, 0, 2, 0, 0, 0, 0, 0, 0, 0, 0)
+	return f.b
+}
+
+// cmap returns the cmap table with the format 4 subtable for the BMP, and the
+// format 12 subtable for all the runes if there are supplementary ones.
+func (fb *fontBuilder) cmap() []byte {
+	// the runs of the consecutive runes, which are mapped to the consecutive
+	// glyphs
+	type run struct{ start, end, glyph int }
+	var runs []run
+	for i, r := range fb.runes {
+		if n := len(runs); n != 0 && runs[n-1].end+1 == int(r) {
+			runs[n-1].end++
+			continue
+		}
+		runs = append(runs, run{int(r), int(r), i + 1})
+	}
+
+	var f4 fontBuf
+	var segs []run
+	for _, r := range runs {
+		if 0xffff <= r.start {
+			break
+		}
+		if 0xffff <= r.end {
+			r.end = 0xfffe
+		}
+		segs = append(segs, r)
+	}
+	segs = append(segs, run{0xffff, 0xffff, 1})
+	n := len(segs)
+	es := floorLog2(n)
+	f4.u16(4, 16+n*8, 0, n*2, 2<<es, es, n*2-2<<es)
+	for _, s := range segs {
+		f4.u16(s.end)
+	}
+	f4.u16(0)
+	for _, s := range segs {
+		f4.u16(s.start)
+	}
+	for _, s := range segs {
+		f4.u16(s.glyph - s.start)
+	}
+	for range segs {
+		f4.u16(0)
+	}
+
+	var f fontBuf
+	if !fb.hasSupplementary {
+		f.u16(0, 1, 3, 1)
+		f.u32(12)
+		f.b = append(f.b, f4.b...)
+		return f.b
+	}
+	var f12 fontBuf
+	f12.u16(12, 0)
+	f12.u32(16+len(runs)*12, 0, len(runs))
+	for _, r := range runs {
+		f12.u32(r.start, r.end, r.glyph)
+	}
+	f.u16(0, 2, 3, 1)
+	f.u32(20)
+	f.u16(3, 10)
+	f.u32(20 + len(f4.b))
+	f.b = append(f.b, f4.b...)
+	f.b = append(f.b, f12.b...)
+	return f.b
+}
+
+// name returns the name table with the names for the Windows platform.
+func (fb *fontBuilder) name() []byte {
+	ps := strings.Map(func(r rune) rune {
+		if r <= ' ' || 0x7e < r || strings.ContainsRune("[](){}<>/%", r) {
+			return -1
+		}
+		return r
+	}, fb.family+"-"+fb.style)
+	names := []string{
+		1: fb.family,
+		2: fb.style,
+		3: fb.family + " " + fb.style,
+		4: fb.family + " " + fb.style,
+		5: "Version 1.000",
+		6: ps,
+	}
+	var recs, strs fontBuf
+	for id, s := range names[1:] {
+		var b fontBuf
+		for _, c := range utf16.Encode([]rune(s)) {
+			b.u16(int(c))
+		}
+		recs.u16(3, 1, 0x0409, id+1, len(b.b), len(strs.b))
+		strs.b = append(strs.b, b.b...)
+	}
+	var f fontBuf
+	f.u16(0, len(names)-1, 6+len(recs.b))
+	f.b = append(f.b, recs.b...)
+	f.b = append(f.b, strs.b...)
+	return f.b
+}
+
+func (fb *fontBuilder) post() []byte {
+	var f fontBuf
+	f.u32(0x00030000, 0)
+	f.u16(-fb.descent/2, maxInt(fb.upm/20, 1))
+	fixed := 0
+	if fb.fixedPitch {
+		fixed = 1
+	}
+	f.u32(fixed, 0, 0, 0, 0)
+	return f.b
+}
+
+func (fb *fontBuilder) os2() []byte {
+	var f fontBuf
+	sub, sup := fb.upm*13/20, fb.upm*7/20
+	f.u16(4, fb.avgAdvance, 400, 5, 0)
+	f.u16(sub, sub, 0, fb.upm/7, sub, sub, 0, sup)
+	f.u16(fb.upm/20, fb.ascent/3, 0)
+	f.b = append(f.b, make([]byte, 10)...) // panose
+	f.u32(0, 0, 0, 0)                      // ulUnicodeRange
+	f.b = append(f.b, "NONE"...)
+	f.u16(0x0040, fb.firstCh, fb.lastCh)
+	f.u16(fb.ascent, -fb.descent, 0, maxInt(fb.ascent, fb.yMax), maxInt(fb.descent, -fb.yMin))
+	f.u32(1, 0) // ulCodePageRange, Latin 1
+	f.u16(fb.ascent/2, fb.ascent, 0, ' ', 1)
+	return f.b
+}
+
+// fontBuf is a buffer to build the big-endian binary data of the font.
+type fontBuf struct{ b []byte }
+
+// u16 appends the values as 16-bit integers, either signed or unsigned.
+func (f *fontBuf) u16(vs ...int) {
+	for _, v := range vs {
+		f.b = append(f.b, byte(v>>8), byte(v))
+	}
+}
+
+// u32 appends the values as 32-bit integers.
+func (f *fontBuf) u32(vs ...int) {
+	for _, v := range vs {
+		f.b = append(f.b, byte(v>>24), byte(v>>16), byte(v>>8), byte(v))
+	}
+}
+
+// pad appends zeros to align the length to 4 bytes.
+func (f *fontBuf) pad() {
+	for len(f.b)%4 != 0 {
+		f.b = append(f.b, 0)
+	}
+}
+
+// checksum returns the checksum of the table data b.
+func checksum(b []byte) uint32 {
+	var sum uint32
+	for i := 0; i < len(b); i += 4 {
+		var w [4]byte
+		copy(w[:], b[i:])
+		sum += uint32(w[0])<<24 | uint32(w[1])<<16 | uint32(w[2])<<8 | uint32(w[3])
+	}
+	return sum
+}
+
+// floorLog2 returns floor(log2(n)) for positive n.
+func floorLog2(n int) int {
+	e := 0
+	for 2 <= n {
+		n >>= 1
+		e++
+	}
+	return e
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxInt(a, b int) int {
+	if a < b {
+		return b
+	}
+	return a
+}
@@ -0,0 +1,124 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package sfntpath_test
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/tunabay/go-bitarray"
+	"github.com/tunabay/go-bmppath"
+	"github.com/tunabay/go-bmppath/sfntpath"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/sfnt"
+	"golang.org/x/image/math/fixed"
+)
+
+// trace traces the rows of a glyph, where '#' is ink.
+func trace(rows ...string) *bmppath.Path {
+	s := strings.Map(func(r rune) rune {
+		if r == '#' {
+			return '1'
+		}
+		return '0'
+	}, strings.Join(rows, ""))
+	p, err := bmppath.New(bitarray.NewBufferFromBitArray(bitarray.MustParse(s)), len(rows[0]))
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+func TestWriteFont(t *testing.T) {
+	glyphs := map[rune]*bmppath.Path{
+		' ':     trace(".....", ".....", ".....", ".....", ".....", "....."),
+		'O':     trace(".###.", ".#.#.", ".#.#.", ".#.#.", ".###.", "....."),
+		'j':     trace("...#.", ".....", "...#.", "...#.", "...#.", ".##.."),
+		0x1f642: trace("#####", "#.#.#", "#####", "#...#", "#####", "....."),
+	}
+	opts := &sfntpath.FontOptions{FamilyName: "Pixel Test", UnitsPerEm: 1200, Baseline: 5}
+	var buf bytes.Buffer
+	if err := sfntpath.WriteFont(&buf, glyphs, opts); err != nil {
+		t.Fatalf("WriteFont(): %v", err)
+	}
+
+	// the checksum of the whole font is adjusted by the head table
+	var sum uint32
+	data := buf.Bytes()
+	for i := 0; i+4 <= len(data); i += 4 {
+		sum += uint32(data[i])<<24 | uint32(data[i+1])<<16 | uint32(data[i+2])<<8 | uint32(data[i+3])
+	}
+	if len(data)%4 != 0 || sum != 0xb1b0afba {
+		t.Errorf("unexpected checksum: %08x", sum)
+	}
+
+	f, err := sfnt.Parse(data)
+	if err != nil {
+		t.Fatalf("Parse(): %v", err)
+	}
+	if n := f.NumGlyphs(); n != 5 {
+		t.Errorf("unexpected number of glyphs: %d", n)
+	}
+	var sb sfnt.Buffer
+	for _, tc := range []struct {
+		id    sfnt.NameID
+		value string
+	}{
+		{sfnt.NameIDFamily, "Pixel Test"},
+		{sfnt.NameIDSubfamily, "Regular"},
+		{sfnt.NameIDFull, "Pixel Test Regular"},
+		{sfnt.NameIDPostScript, "PixelTest-Regular"},
+	} {
+		if got, err := f.Name(&sb, tc.id); err != nil || got != tc.value {
+			t.Errorf("name %d: got %q, %v, want %q", tc.id, got, err, tc.value)
+		}
+	}
+	if upm := f.UnitsPerEm(); upm != 1200 {
+		t.Errorf("unexpected units per em: %d", upm)
+	}
+
+	// 1200 units per em of 6 pixels, at 24 ppem: 4 pixels per pixel
+	ppem := fixed.I(24)
+	m, err := f.Metrics(&sb, ppem, font.HintingNone)
+	if err != nil {
+		t.Fatalf("Metrics(): %v", err)
+	}
+	if m.Ascent != fixed.I(20) || m.Descent != fixed.I(4) {
+		t.Errorf("unexpected metrics: ascent %v, descent %v", m.Ascent, m.Descent)
+	}
+	for r, p := range glyphs {
+		idx, err := f.GlyphIndex(&sb, r)
+		if err != nil || idx == 0 {
+			t.Errorf("%U: GlyphIndex(): %d, %v", r, idx, err)
+			continue
+		}
+		adv, err := f.GlyphAdvance(&sb, idx, ppem, font.HintingNone)
+		if err != nil || adv != fixed.I(20) {
+			t.Errorf("%U: GlyphAdvance(): %v, %v", r, adv, err)
+		}
+		segs, err := f.LoadGlyph(&sb, idx, ppem, nil)
+		if err != nil {
+			t.Errorf("%U: LoadGlyph(): %v", r, err)
+			continue
+		}
+		want := sfntpath.Segments(p, &sfntpath.Options{UnitsPerEm: 1200, EmPixels: 6, Baseline: 5}, ppem)
+		if got, want := segs.Bounds(), want.Bounds(); got != want {
+			t.Errorf("%U: unexpected bounds: got %v, want %v", r, got, want)
+		}
+		if n := len(segs); n != len(want) {
+			t.Errorf("%U: unexpected number of segments: got %d, want %d", r, n, len(want))
+		}
+	}
+	if idx, err := f.GlyphIndex(&sb, 'x'); err != nil || idx != 0 {
+		t.Errorf("unmapped rune: GlyphIndex(): %d, %v", idx, err)
+	}
+
+	glyphs[-1] = trace("#")
+	if err := sfntpath.WriteFont(&buf, glyphs, nil); !errors.Is(err, sfntpath.ErrInvalidRune) {
+		t.Errorf("unexpected error for invalid rune: %v", err)
+	}
+}
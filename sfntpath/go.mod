@@ -0,0 +1,13 @@
+module github.com/tunabay/go-bmppath/sfntpath
+
+go 1.17
+
+require (
+	github.com/tunabay/go-bitarray v1.3.1
+	github.com/tunabay/go-bmppath v0.0.0-00010101000000-000000000000
+	golang.org/x/image v0.10.0
+)
+
+require golang.org/x/text v0.11.0 // indirect
+
+replace github.com/tunabay/go-bmppath => ../
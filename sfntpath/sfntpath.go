@@ -0,0 +1,152 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+// Package sfntpath converts the paths of bmppath into the glyph outlines of the
+// sfnt fonts, TrueType and OpenType, so that the bitmap fonts can be turned into
+// the outline fonts.
+//
+// Contours returns the outline data to be stored in the glyf or CFF table, in
+// the font units with the y-axis pointing upward, and with the contours wound
+// as each format requires. Segments returns the same outline in the form of
+// sfnt.Segments of golang.org/x/image/font/sfnt, as sfnt.Font.LoadGlyph would
+// return for the built font, so that it can be consumed by the code written for
+// the sfnt package, such as the rasterization with golang.org/x/image/vector.
+//
+// Since the paths of bmppath consist only of the straight segments, all the
+// points are on the curve; they are the degenerate quadratic segments of the
+// TrueType outlines.
+package sfntpath
+
+import (
+	"math"
+
+	"github.com/tunabay/go-bmppath"
+	"golang.org/x/image/font/sfnt"
+	"golang.org/x/image/math/fixed"
+)
+
+// Format specifies the outline format, which determines the winding direction
+// of the contours.
+type Format int
+
+const (
+	// TrueType is the format of the glyf table, where the outer contours
+	// run clockwise and the holes counterclockwise with the y-axis pointing
+	// upward.
+	TrueType Format = iota
+
+	// CFF is the format of the CFF table of OpenType, where the outer
+	// contours run counterclockwise and the holes clockwise.
+	CFF
+)
+
+// Options represents the options for Contours and Segments.
+type Options struct {
+	// Format is the outline format.
+	Format Format
+
+	// UnitsPerEm is the number of the font units per em. Zero means the
+	// default value 1000. TrueType fonts typically use 2048.
+	UnitsPerEm int
+
+	// EmPixels is the number of the pixels per em of the bitmap font. Zero
+	// means the height of the canvas of the Path.
+	EmPixels int
+
+	// Baseline is the y-coordinate of the baseline in pixels. The pixels
+	// above it are placed above the baseline of the font. Zero means the
+	// bottom of the canvas of the Path.
+	Baseline int
+}
+
+// Point is a point of a contour in the font units, with the y-axis pointing
+// upward.
+type Point struct {
+	X, Y int
+
+	// OnCurve reports whether the point is on the curve. It is always true
+	// for the contours converted from the paths of bmppath.
+	OnCurve bool
+}
+
+// Contours converts the closed paths of p into the contours of a glyph in the
+// font units, scaled by UnitsPerEm / EmPixels and rounded to the nearest
+// integers. The origin is the left end of the baseline. The contours are wound
+// as opts.Format requires. nil opts is the same as the zero value of Options.
+func Contours(p *bmppath.Path, opts *Options) [][]Point {
+	if opts == nil {
+		opts = &Options{}
+	}
+	upm, em, baseline := opts.params(p)
+	s := float64(upm) / float64(em)
+	ret := make([][]Point, 0, len(p.Vertices))
+	for _, vs := range p.Vertices {
+		if len(vs) == 0 {
+			continue
+		}
+		c := make([]Point, len(vs))
+		for i, v := range vs {
+			c[i] = Point{
+				X:       int(math.Round(float64(v[0]) * s)),
+				Y:       int(math.Round(float64(baseline-v[1]) * s)),
+				OnCurve: true,
+			}
+		}
+		// the outlines of bmppath run clockwise on the screen, and remain
+		// clockwise with both the y-axis and the coordinates flipped
+		if opts.Format == CFF {
+			for i, j := 1, len(c)-1; i < j; i, j = i+1, j-1 {
+				c[i], c[j] = c[j], c[i]
+			}
+		}
+		ret = append(ret, c)
+	}
+	return ret
+}
+
+// Segments converts the closed paths of p into sfnt.Segments, as
+// sfnt.Font.LoadGlyph returns for the glyph of the contours returned by
+// Contours, rendered at ppem pixels per em. The coordinates are relative to the
+// origin on the baseline, with the y-axis pointing downward. nil opts is the
+// same as the zero value of Options.
+func Segments(p *bmppath.Path, opts *Options, ppem fixed.Int26_6) sfnt.Segments {
+	if opts == nil {
+		opts = &Options{}
+	}
+	upm, _, _ := opts.params(p)
+	pt := func(q Point) fixed.Point26_6 {
+		return fixed.Point26_6{
+			X: fixed.Int26_6(int64(q.X) * int64(ppem) / int64(upm)),
+			Y: fixed.Int26_6(-int64(q.Y) * int64(ppem) / int64(upm)),
+		}
+	}
+	var ret sfnt.Segments
+	for _, c := range Contours(p, opts) {
+		ret = append(ret, sfnt.Segment{Op: sfnt.SegmentOpMoveTo, Args: [3]fixed.Point26_6{pt(c[0])}})
+		for i := range c {
+			q := c[(i+1)%len(c)]
+			ret = append(ret, sfnt.Segment{Op: sfnt.SegmentOpLineTo, Args: [3]fixed.Point26_6{pt(q)}})
+		}
+	}
+	return ret
+}
+
+// params returns the units per em, the pixels per em, and the baseline with the
+// default values applied.
+func (opts *Options) params(p *bmppath.Path) (int, int, int) {
+	upm, em, baseline := opts.UnitsPerEm, opts.EmPixels, opts.Baseline
+	if upm == 0 {
+		upm = 1000
+	}
+	if em == 0 {
+		em = p.Height
+	}
+	if em == 0 {
+		em = 1
+	}
+	if baseline == 0 {
+		baseline = p.Height
+	}
+	return upm, em, baseline
+}
@@ -0,0 +1,125 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package sfntpath_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/tunabay/go-bitarray"
+	"github.com/tunabay/go-bmppath"
+	"github.com/tunabay/go-bmppath/sfntpath"
+	"golang.org/x/image/font/sfnt"
+	"golang.org/x/image/math/fixed"
+)
+
+// glyphO is a 4x5 "O" with a hole, on the canvas with a descender row below
+// the baseline at y = 5.
+func glyphO() *bmppath.Path {
+	bmp := bitarray.NewBufferFromBitArray(
+		bitarray.MustParse(strings.Join([]string{
+			"1111",
+			"1001",
+			"1001",
+			"1001",
+			"1111",
+			"0000",
+		}, "")),
+	)
+	p, err := bmppath.New(bmp, 4)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+// area returns twice the signed area of the contour c, positive for the
+// counterclockwise contours with the y-axis pointing upward.
+func area(c []sfntpath.Point) int {
+	a := 0
+	for i, p0 := range c {
+		p1 := c[(i+1)%len(c)]
+		a += p0.X*p1.Y - p1.X*p0.Y
+	}
+	return a
+}
+
+func ExampleContours() {
+	opts := &sfntpath.Options{UnitsPerEm: 1200, EmPixels: 6, Baseline: 5}
+	for _, c := range sfntpath.Contours(glyphO(), opts) {
+		fmt.Println(c, area(c) < 0)
+	}
+
+	// Output:
+	// [{0 1000 true} {800 1000 true} {800 0 true} {0 0 true}] true
+	// [{200 800 true} {200 200 true} {600 200 true} {600 800 true}] false
+}
+
+func TestContours(t *testing.T) {
+	p := glyphO()
+	for _, tc := range []struct {
+		format   sfntpath.Format
+		outerCCW bool
+	}{
+		{sfntpath.TrueType, false},
+		{sfntpath.CFF, true},
+	} {
+		cs := sfntpath.Contours(p, &sfntpath.Options{Format: tc.format})
+		if len(cs) != 2 {
+			t.Fatalf("format %d: unexpected number of contours: %d", tc.format, len(cs))
+		}
+		abs := func(n int) int {
+			if n < 0 {
+				return -n
+			}
+			return n
+		}
+		outer, hole := cs[0], cs[1]
+		if abs(area(outer)) < abs(area(hole)) {
+			outer, hole = hole, outer
+		}
+		if got := 0 < area(outer); got != tc.outerCCW {
+			t.Errorf("format %d: outer contour counterclockwise = %v", tc.format, got)
+		}
+		if got := 0 < area(hole); got == tc.outerCCW {
+			t.Errorf("format %d: hole counterclockwise = %v", tc.format, got)
+		}
+	}
+}
+
+func TestSegments(t *testing.T) {
+	// 1000 units per em of 6 pixels, at 12 ppem: 2 pixels per pixel
+	opts := &sfntpath.Options{EmPixels: 6, Baseline: 5}
+	segs := sfntpath.Segments(glyphO(), opts, fixed.I(12))
+	if len(segs) != 10 {
+		t.Fatalf("unexpected number of segments: %d", len(segs))
+	}
+	for i, seg := range segs {
+		op := sfnt.SegmentOpLineTo
+		if i%5 == 0 {
+			op = sfnt.SegmentOpMoveTo
+		}
+		if seg.Op != op {
+			t.Errorf("#%d: unexpected op: %d", i, seg.Op)
+		}
+	}
+	bounds := segs.Bounds()
+	want := fixed.Rectangle26_6{
+		Min: fixed.Point26_6{X: 0, Y: -fixed.I(10)},
+		Max: fixed.Point26_6{X: fixed.I(8), Y: 0},
+	}
+	// the rounding of the font units may shift the bounds by 1/64 pixel
+	d := func(a, b fixed.Int26_6) fixed.Int26_6 {
+		if a < b {
+			return b - a
+		}
+		return a - b
+	}
+	if 1 < d(bounds.Min.X, want.Min.X) || 1 < d(bounds.Min.Y, want.Min.Y) ||
+		1 < d(bounds.Max.X, want.Max.X) || 1 < d(bounds.Max.Y, want.Max.Y) {
+		t.Errorf("unexpected bounds: got %v, want %v", bounds, want)
+	}
+}
@@ -0,0 +1,192 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strings"
+)
+
+// Smoothing specifies the parameters of Smooth, which correspond to those of
+// potrace.
+type Smoothing struct {
+	// AlphaMax is the corner threshold. The vertices sharper than it are kept
+	// as corners, and the others are replaced by curves. 0 keeps all the
+	// corners, and 4/3 or more smooths all of them. potrace uses 1 by
+	// default.
+	AlphaMax float64
+
+	// Epsilon is the tolerance of the polygon simplification applied before
+	// the smoothing, in pixels. It straightens the staircases of the slanted
+	// edges. 0 disables the simplification.
+	Epsilon float64
+}
+
+// defaultSmoothings are the candidates compared by WriteSmoothingPreview when
+// none is specified.
+var defaultSmoothings = []Smoothing{
+	{AlphaMax: 0},
+	{AlphaMax: 0.5},
+	{AlphaMax: 1},
+	{AlphaMax: 1.5},
+}
+
+// Smooth returns a new CurvePath with the closed paths of p smoothed in the
+// manner of potrace. Each closed path is first simplified with the tolerance
+// s.Epsilon, then each of its vertices is either kept as a corner or replaced
+// by a cubic Bézier curve between the midpoints of the adjacent edges,
+// depending on s.AlphaMax.
+func (p *Path) Smooth(s Smoothing) *CurvePath {
+	return p.Float().Smooth(s)
+}
+
+// Smooth is identical to Path.Smooth except that it smooths fp.
+func (fp *FloatPath) Smooth(s Smoothing) *CurvePath {
+	ret := &CurvePath{
+		Width:  fp.Width,
+		Height: fp.Height,
+		Curves: make([][]Curve, 0, len(fp.Vertices)),
+	}
+	for _, vs := range fp.Vertices {
+		vs = simplifyPolygon(vs, s.Epsilon)
+		n := len(vs)
+		if n < 3 {
+			continue
+		}
+		mid := func(i int) FloatVertex {
+			a, b := vs[i%n], vs[(i+1)%n]
+			return FloatVertex{(a[0] + b[0]) / 2, (a[1] + b[1]) / 2}
+		}
+		cs := make([]Curve, 0, n*2)
+		for i, v := range vs {
+			a, b := mid(i+n-1), mid(i)
+			// the distance of the vertex from the chord between the
+			// midpoints, in half pixels, determines the sharpness
+			var alpha float64
+			if l := math.Hypot(b[0]-a[0], b[1]-a[1]); l != 0 {
+				dd := 2 * math.Abs((v[0]-a[0])*(b[1]-a[1])-(v[1]-a[1])*(b[0]-a[0])) / l
+				if 1 < dd {
+					alpha = (1 - 1/dd) / 0.75
+				}
+			}
+			if s.AlphaMax <= alpha {
+				cs = append(cs,
+					Curve{Kind: CurveLine, End: v},
+					Curve{Kind: CurveLine, End: b},
+				)
+				continue
+			}
+			t := 0.5 + 0.5*math.Max(0.55, math.Min(1, alpha))
+			cs = append(cs, Curve{
+				Kind: CurveCubic,
+				C1:   FloatVertex{a[0] + (v[0]-a[0])*t, a[1] + (v[1]-a[1])*t},
+				C2:   FloatVertex{b[0] + (v[0]-b[0])*t, b[1] + (v[1]-b[1])*t},
+				End:  b,
+			})
+		}
+		ret.Curves = append(ret.Curves, cs)
+	}
+	return ret
+}
+
+// WriteSmoothingPreview writes an SVG document showing the results of Smooth
+// with each of the candidates side by side, labeled with their parameters, so
+// that the smoothing strength can be chosen by inspecting them. The curves are
+// written with 3 digits after the decimal point. If candidates is empty,
+// AlphaMax of 0, 0.5, 1, and 1.5 are compared without the simplification.
+func (p *Path) WriteSmoothingPreview(w io.Writer, candidates []Smoothing) error {
+	if len(candidates) == 0 {
+		candidates = defaultSmoothings
+	}
+	f := defaultCoordFormatter
+	cw, ch := float64(p.Width), float64(p.Height)
+	fs := math.Max(1, cw/6)
+	gap := fs
+	tw := (cw+gap)*float64(len(candidates)) - gap
+	th := ch + fs*1.5
+
+	var sb strings.Builder
+	fmt.Fprintln(&sb, `<?xml version="1.0" encoding="utf-8"?>`)
+	fmt.Fprint(&sb, `<svg version="1.1" xmlns="http://www.w3.org/2000/svg"`)
+	fmt.Fprintf(&sb, ` viewBox="0 0 %s %s">`, f(tw), f(th))
+	fmt.Fprintln(&sb)
+	fmt.Fprintf(&sb, `<path fill="#fff" d="m0,0h%sv%sh%sz"/>`, f(tw), f(th), f(-tw))
+	fmt.Fprintln(&sb)
+	for i, s := range candidates {
+		fmt.Fprintf(&sb, `<g transform="translate(%s,0)">`, f((cw+gap)*float64(i)))
+		sb.WriteString(`<path d="`)
+		_ = p.Smooth(s).WriteSVGDFormat(&sb, FormatCoord(3, ""))
+		sb.WriteString(`"/>`)
+		fmt.Fprintf(&sb, `<text x="%s" y="%s" font-size="%s" text-anchor="middle">`, f(cw/2), f(ch+fs*1.25), f(fs))
+		fmt.Fprintf(&sb, `alphamax=%s epsilon=%s</text></g>`, f(s.AlphaMax), f(s.Epsilon))
+		fmt.Fprintln(&sb)
+	}
+	fmt.Fprintln(&sb, `</svg>`)
+	if _, err := fmt.Fprint(w, sb.String()); err != nil {
+		return fmt.Errorf("write failure: %w", err)
+	}
+	return nil
+}
+
+// simplifyPolygon simplifies the closed polygon vs with the Douglas-Peucker
+// algorithm, so that the removed vertices are within eps from the result. vs
+// is returned as it is if eps is not positive or the result would degenerate.
+func simplifyPolygon(vs []FloatVertex, eps float64) []FloatVertex {
+	n := len(vs)
+	if eps <= 0 || n < 4 {
+		return vs
+	}
+	// split at the vertex farthest from the first one
+	far, maxd := 0, 0.0
+	for i, v := range vs {
+		if d := math.Hypot(v[0]-vs[0][0], v[1]-vs[0][1]); maxd < d {
+			far, maxd = i, d
+		}
+	}
+	keep := make([]bool, n)
+	keep[0], keep[far] = true, true
+	var dp func(i, j int)
+	dp = func(i, j int) {
+		a, b := vs[i%n], vs[j%n]
+		k, maxd := -1, eps
+		for m := i + 1; m < j; m++ {
+			if d := segmentDist(vs[m%n], a, b); maxd < d {
+				k, maxd = m, d
+			}
+		}
+		if k < 0 {
+			return
+		}
+		keep[k%n] = true
+		dp(i, k)
+		dp(k, j)
+	}
+	dp(0, far)
+	dp(far, n)
+
+	ret := make([]FloatVertex, 0, n)
+	for i, v := range vs {
+		if keep[i] {
+			ret = append(ret, v)
+		}
+	}
+	if len(ret) < 3 {
+		return vs
+	}
+	return ret
+}
+
+// segmentDist returns the distance between the point v and the line segment
+// between a and b.
+func segmentDist(v, a, b FloatVertex) float64 {
+	dx, dy := b[0]-a[0], b[1]-a[1]
+	t := 0.0
+	if l2 := dx*dx + dy*dy; l2 != 0 {
+		t = math.Max(0, math.Min(1, ((v[0]-a[0])*dx+(v[1]-a[1])*dy)/l2))
+	}
+	return math.Hypot(v[0]-a[0]-dx*t, v[1]-a[1]-dy*t)
+}
@@ -0,0 +1,117 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath_test
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/tunabay/go-bitarray"
+	"github.com/tunabay/go-bmppath"
+)
+
+func ExamplePath_Smooth() {
+	bmp := bitarray.NewBufferFromBitArray(bitarray.MustParse(strings.Repeat("1", 64)))
+	path, err := bmppath.New(bmp, 8)
+	if err != nil {
+		panic(err)
+	}
+
+	f := bmppath.FormatCoord(2, "")
+	for _, alphaMax := range []float64{1, 1.5} {
+		cp := path.Smooth(bmppath.Smoothing{AlphaMax: alphaMax})
+		if err := cp.WriteSVGDFormat(os.Stdout, f); err != nil {
+			panic(err)
+		}
+		fmt.Println()
+	}
+
+	// Output:
+	// m0,4v-4h4h4v4v4h-4h-4v-4z
+	// m0,4c0-4,0-4,4-4c4,0,4,0,4,4c0,4,0,4-4,4c-4,0-4,0-4-4z
+}
+
+func TestPath_Smooth_epsilon(t *testing.T) {
+	bmp := bitarray.NewBufferFromBitArray(bitarray.MustParse(strings.Join([]string{
+		"10000000",
+		"11000000",
+		"11100000",
+		"11110000",
+		"11111000",
+		"11111100",
+		"11111110",
+		"11111111",
+	}, "")))
+	path, err := bmppath.New(bmp, 8)
+	if err != nil {
+		t.Fatalf("New(): %v", err)
+	}
+	numCurves := func(eps float64) int {
+		cp := path.Smooth(bmppath.Smoothing{AlphaMax: 0, Epsilon: eps})
+		if cp.NumPath() != 1 {
+			t.Fatalf("eps=%g: unexpected number of paths: %d", eps, cp.NumPath())
+		}
+		return len(cp.Curves[0])
+	}
+	if got, want := numCurves(0), path.PathLen(0)*2; got != want {
+		t.Errorf("eps=0: unexpected number of curves: got %d, want %d", got, want)
+	}
+	if got := numCurves(1); got != 6 {
+		t.Errorf("eps=1: unexpected number of curves: got %d, want 6", got)
+	}
+}
+
+func TestPath_WriteSmoothingPreview(t *testing.T) {
+	bmp := bitarray.NewBufferFromBitArray(bitarray.MustParse(strings.Join([]string{
+		"0110",
+		"1111",
+		"1111",
+		"0110",
+	}, "")))
+	path, err := bmppath.New(bmp, 4)
+	if err != nil {
+		t.Fatalf("New(): %v", err)
+	}
+	candidates := []bmppath.Smoothing{
+		{AlphaMax: 0},
+		{AlphaMax: 1, Epsilon: 0.5},
+		{AlphaMax: 1.2, Epsilon: 1},
+	}
+	var buf bytes.Buffer
+	if err := path.WriteSmoothingPreview(&buf, candidates); err != nil {
+		t.Fatalf("WriteSmoothingPreview(): %v", err)
+	}
+	var doc struct {
+		Groups []struct {
+			Path struct {
+				D string `xml:"d,attr"`
+			} `xml:"path"`
+			Text string `xml:"text"`
+		} `xml:"g"`
+	}
+	if err := xml.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("invalid document: %v\n%s", err, buf.String())
+	}
+	if len(doc.Groups) != len(candidates) {
+		t.Fatalf("unexpected number of candidates: got %d, want %d", len(doc.Groups), len(candidates))
+	}
+	for i, s := range candidates {
+		g := doc.Groups[i]
+		if want := fmt.Sprintf("alphamax=%g epsilon=%g", s.AlphaMax, s.Epsilon); g.Text != want {
+			t.Errorf("#%d: unexpected label: got %q, want %q", i, g.Text, want)
+		}
+		var want strings.Builder
+		if err := path.Smooth(s).WriteSVGDFormat(&want, bmppath.FormatCoord(3, "")); err != nil {
+			t.Fatalf("WriteSVGDFormat(): %v", err)
+		}
+		if g.Path.D != want.String() {
+			t.Errorf("#%d: unexpected d: got %q, want %q", i, g.Path.D, want.String())
+		}
+	}
+}
@@ -0,0 +1,105 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+)
+
+// SpriteOptions represents the options for WriteSprite.
+type SpriteOptions struct {
+	// Grid also arranges the symbols in a grid with the <use> elements, so
+	// that the sprite sheet shows the whole set when it is opened as it is.
+	// Otherwise, the symbols are only defined, and the document renders
+	// nothing by itself.
+	Grid bool
+
+	// Columns is the number of the columns of the grid. Zero means the
+	// smallest number not less than the square root of the number of the
+	// Paths.
+	Columns int
+
+	// Gap is the space between the cells of the grid, each of which is as
+	// large as the largest canvas of the Paths.
+	Gap float64
+
+	// IDPrefix is prepended to the names of the Paths to make the id
+	// attributes of the symbols.
+	IDPrefix string
+}
+
+// WriteSprite writes all the Paths in the Catalog as an SVG sprite sheet, in
+// which each Path is defined as a <symbol> element with the id of its name and
+// the viewBox of its canvas, so that a whole traced icon set can be shipped as
+// a single file and each icon can be referenced as <use href="file#id"/>. The
+// symbols are written in the order of the names. All the Paths not loaded yet
+// are loaded. nil opts is the same as the zero value of SpriteOptions.
+func (c *Catalog) WriteSprite(w io.Writer, opts *SpriteOptions) error {
+	if opts == nil {
+		opts = &SpriteOptions{}
+	}
+	paths := make([]*Path, len(c.names))
+	cw, ch := 0, 0
+	for i, name := range c.names {
+		p, err := c.Get(name)
+		if err != nil {
+			return err
+		}
+		paths[i] = p
+		if cw < p.Width {
+			cw = p.Width
+		}
+		if ch < p.Height {
+			ch = p.Height
+		}
+	}
+	id := func(sb *strings.Builder, name string) {
+		_ = xml.EscapeText(sb, []byte(opts.IDPrefix+name))
+	}
+	f := defaultCoordFormatter
+
+	var sb strings.Builder
+	fmt.Fprintln(&sb, `<?xml version="1.0" encoding="utf-8"?>`)
+	fmt.Fprint(&sb, `<svg version="1.1" xmlns="http://www.w3.org/2000/svg"`)
+	cols, rows := opts.Columns, 0
+	if opts.Grid && len(paths) != 0 {
+		if cols < 1 {
+			cols = int(math.Ceil(math.Sqrt(float64(len(paths)))))
+		}
+		rows = (len(paths) + cols - 1) / cols
+		if len(paths) < cols {
+			cols = len(paths)
+		}
+		width := float64(cols*cw) + float64(cols-1)*opts.Gap
+		height := float64(rows*ch) + float64(rows-1)*opts.Gap
+		fmt.Fprintf(&sb, ` viewBox="0 0 %s %s"`, f(width), f(height))
+	}
+	fmt.Fprintln(&sb, `>`)
+	for i, p := range paths {
+		sb.WriteString(`<symbol id="`)
+		id(&sb, c.names[i])
+		fmt.Fprintf(&sb, `" viewBox="0 0 %d %d"><path d="`, p.Width, p.Height)
+		_ = p.WriteSVGD(&sb)
+		fmt.Fprintln(&sb, `"/></symbol>`)
+	}
+	if 0 < rows {
+		for i, p := range paths {
+			x := float64(i%cols) * (float64(cw) + opts.Gap)
+			y := float64(i/cols) * (float64(ch) + opts.Gap)
+			sb.WriteString(`<use href="#`)
+			id(&sb, c.names[i])
+			fmt.Fprintf(&sb, `" x="%s" y="%s" width="%d" height="%d"/>`+"\n", f(x), f(y), p.Width, p.Height)
+		}
+	}
+	fmt.Fprintln(&sb, `</svg>`)
+	if _, err := io.WriteString(w, sb.String()); err != nil {
+		return fmt.Errorf("write failure: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath_test
+
+import (
+	"os"
+
+	"github.com/tunabay/go-bitarray"
+	"github.com/tunabay/go-bmppath"
+)
+
+func ExampleCatalog_WriteSprite() {
+	c := bmppath.NewCatalog()
+	for name, s := range map[string]string{
+		"dot":  "0000" + "0110" + "0110" + "0000",
+		"bar":  "1111" + "0000" + "0000" + "0000",
+		"edge": "1000" + "1000" + "1000" + "1000",
+	} {
+		path, err := bmppath.New(bitarray.NewBufferFromBitArray(bitarray.MustParse(s)), 4)
+		if err != nil {
+			panic(err)
+		}
+		c.Add(name, path)
+	}
+
+	opts := &bmppath.SpriteOptions{Grid: true, Columns: 2, Gap: 1, IDPrefix: "icon-"}
+	if err := c.WriteSprite(os.Stdout, opts); err != nil {
+		panic(err)
+	}
+
+	// Output:
+	// <?xml version="1.0" encoding="utf-8"?>
+	// <svg version="1.1" xmlns="http://www.w3.org/2000/svg" viewBox="0 0 9 9">
+	// <symbol id="icon-bar" viewBox="0 0 4 4"><path d="m0,0h4v1h-4z"/></symbol>
+	// <symbol id="icon-dot" viewBox="0 0 4 4"><path d="m1,1h2v2h-2z"/></symbol>
+	// <symbol id="icon-edge" viewBox="0 0 4 4"><path d="m0,0h1v4h-1z"/></symbol>
+	// <use href="#icon-bar" x="0" y="0" width="4" height="4"/>
+	// <use href="#icon-dot" x="5" y="0" width="4" height="4"/>
+	// <use href="#icon-edge" x="0" y="5" width="4" height="4"/>
+	// </svg>
+}
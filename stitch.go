@@ -0,0 +1,152 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidLayout is the error thrown when the specified tile layout is
+// invalid.
+var ErrInvalidLayout = errors.New("invalid layout")
+
+// TileLayout specifies the arrangement of the tiles for Stitch.
+type TileLayout struct {
+	// Columns is the number of tiles in each row. The tiles are arranged
+	// from left to right, then from top to bottom. The last row may have
+	// fewer tiles.
+	Columns int
+}
+
+// Stitch combines the separately traced tiles into one seamless Path. Each tile
+// is placed to the right of the previous tile in the same row, and each row is
+// placed below the tallest tile of the previous row. Unlike Append, the closed
+// paths crossing the tile borders are welded together, so that the result is
+// identical to the one traced from the whole image at once. The edges running
+// along the shared tile borders are matched and cancelled out, and the rest of
+// the edges are relinked as they are, so the cost is proportional to the number
+// of the vertices and the total length of the tile borders, not to the area of
+// the whole image. This is intended for the workflows that trace a huge image
+// in parallel across machines. The tiles must be the results of New or the
+// like, otherwise it returns ErrInvalidLayout. The labels of the tiles are not
+// preserved.
+func Stitch(tiles []*Path, layout TileLayout) (*Path, error) {
+	if layout.Columns < 1 {
+		return nil, fmt.Errorf("%w: columns=%d < 1", ErrInvalidLayout, layout.Columns)
+	}
+	offsets := make([]Vertex, len(tiles))
+	var width, height, rowHeight, x int
+	for i, tile := range tiles {
+		if tile == nil {
+			return nil, fmt.Errorf("%w: tile #%d is nil", ErrInvalidLayout, i)
+		}
+		if i%layout.Columns == 0 {
+			height += rowHeight
+			rowHeight, x = 0, 0
+		}
+		offsets[i] = Vertex{x, height}
+		if x+tile.Width < x || height+tile.Height < height {
+			return nil, fmt.Errorf("%w: tile #%d", ErrTooLarge, i)
+		}
+		x += tile.Width
+		if width < x {
+			width = x
+		}
+		if rowHeight < tile.Height {
+			rowHeight = tile.Height
+		}
+	}
+	height += rowHeight
+	if width < 1 || height < 1 {
+		return &Path{Width: width, Height: height}, nil
+	}
+
+	w := &welder{
+		linker:  newLinker(),
+		borders: make(map[weldEdge]bool),
+	}
+	for i, tile := range tiles {
+		if err := w.addTile(tile, offsets[i]); err != nil {
+			return nil, fmt.Errorf("%w: tile #%d: %v", ErrInvalidLayout, i, err)
+		}
+	}
+	for e := range w.borders {
+		w.addSegment(e.from, step(e.from, e.dir), e.dir)
+	}
+
+	ret, err := w.link(width, height)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidLayout, err)
+	}
+
+	return ret, nil
+}
+
+// weldEdge is the unit edge from the grid point from in the direction dir.
+type weldEdge struct {
+	from Vertex
+	dir  int
+}
+
+// welder welds the closed paths of the tiles. The edges on the borders of the
+// tiles are held as the unit edges in borders, where the pairs of the opposite
+// edges between the adjacent tiles cancel each other out. All the other edges
+// are passed to the linker as they are.
+type welder struct {
+	linker
+	borders map[weldEdge]bool
+}
+
+// addTile adds the edges of the closed paths of tile placed at off.
+func (w *welder) addTile(tile *Path, off Vertex) error {
+	for _, vs := range tile.Vertices {
+		for i, v0 := range vs {
+			v1 := vs[(i+1)%len(vs)]
+			if v0 == v1 {
+				continue
+			}
+			for _, v := range [2]Vertex{v0, v1} {
+				if v[0] < 0 || tile.Width < v[0] || v[1] < 0 || tile.Height < v[1] {
+					return fmt.Errorf("vertex %v out of the canvas", v)
+				}
+			}
+			var dir int
+			switch {
+			case v0[0] == v1[0] && v1[1] < v0[1]:
+				dir = 0
+			case v0[1] == v1[1] && v0[0] < v1[0]:
+				dir = 1
+			case v0[0] == v1[0]:
+				dir = 2
+			case v0[1] == v1[1]:
+				dir = 3
+			default:
+				return fmt.Errorf("diagonal edge %v-%v", v0, v1)
+			}
+			v0, v1 = Vertex{v0[0] + off[0], v0[1] + off[1]}, Vertex{v1[0] + off[0], v1[1] + off[1]}
+			if dir%2 == 0 && (vs[i][0] == 0 || vs[i][0] == tile.Width) ||
+				dir%2 == 1 && (vs[i][1] == 0 || vs[i][1] == tile.Height) {
+				for v := v0; v != v1; v = step(v, dir) {
+					w.addBorderEdge(v, dir)
+				}
+				continue
+			}
+			w.addSegment(v0, v1, dir)
+		}
+	}
+	return nil
+}
+
+// addBorderEdge adds the unit edge on the border of a tile, or removes the
+// opposite edge of the adjacent tile if any.
+func (w *welder) addBorderEdge(v Vertex, dir int) {
+	rev := weldEdge{from: step(v, dir), dir: (dir + 2) % 4}
+	if w.borders[rev] {
+		delete(w.borders, rev)
+		return
+	}
+	w.borders[weldEdge{from: v, dir: dir}] = true
+}
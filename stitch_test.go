@@ -0,0 +1,157 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath_test
+
+import (
+	"errors"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/tunabay/go-bitarray"
+	"github.com/tunabay/go-bmppath"
+)
+
+func TestStitch(t *testing.T) {
+	rows := []string{
+		"0110",
+		"1111",
+		"1001",
+		"1111",
+	}
+	whole, err := bmppath.New(bitarray.NewBufferFromBitArray(bitarray.MustParse(strings.Join(rows, ""))), 4)
+	if err != nil {
+		t.Fatalf("New(): %v", err)
+	}
+
+	var tiles []*bmppath.Path
+	for ty := 0; ty < 2; ty++ {
+		for tx := 0; tx < 2; tx++ {
+			s := rows[ty*2][tx*2:tx*2+2] + rows[ty*2+1][tx*2:tx*2+2]
+			tile, err := bmppath.New(bitarray.NewBufferFromBitArray(bitarray.MustParse(s)), 2)
+			if err != nil {
+				t.Fatalf("New(): %v", err)
+			}
+			tiles = append(tiles, tile)
+		}
+	}
+
+	stitched, err := bmppath.Stitch(tiles, bmppath.TileLayout{Columns: 2})
+	if err != nil {
+		t.Fatalf("Stitch(): %v", err)
+	}
+	if !stitched.Equal(whole) {
+		t.Errorf("unexpected result:\n%s", bmppath.Diff(whole, stitched))
+	}
+
+	if _, err := bmppath.Stitch(tiles, bmppath.TileLayout{}); !errors.Is(err, bmppath.ErrInvalidLayout) {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestStitch_tooLarge(t *testing.T) {
+	const maxInt = int(^uint(0) >> 1)
+	huge := &bmppath.Path{Width: maxInt/2 + 1, Height: 1}
+	if _, err := bmppath.Stitch([]*bmppath.Path{huge, huge}, bmppath.TileLayout{Columns: 2}); !errors.Is(err, bmppath.ErrTooLarge) {
+		t.Errorf("unexpected error: %v", err)
+	}
+	// the size of the whole image does not matter as long as it fits in
+	// int, since the image is not rasterized
+	wide := &bmppath.Path{Width: maxInt / 2, Height: 3}
+	got, err := bmppath.Stitch([]*bmppath.Path{wide, wide}, bmppath.TileLayout{Columns: 1})
+	if err != nil {
+		t.Fatalf("Stitch(): %v", err)
+	}
+	if got.Width != maxInt/2 || got.Height != 6 || len(got.Vertices) != 0 {
+		t.Errorf("unexpected result: %dx%d, %d closed paths", got.Width, got.Height, len(got.Vertices))
+	}
+}
+
+func TestStitch_random(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	bitmap := func(pix []byte) *bitarray.Buffer {
+		bmp := bitarray.NewBuffer(len(pix))
+		for i, b := range pix {
+			bmp.PutBitAt(i, b)
+		}
+		return bmp
+	}
+	for n := 0; n < 200; n++ {
+		// uneven column widths and row heights, with some of them being
+		// a single pixel
+		var cols, rows []int
+		width, height := 0, 0
+		for i := 1 + r.Intn(4); 0 < i; i-- {
+			cols = append(cols, 1+r.Intn(6))
+			width += cols[len(cols)-1]
+		}
+		for i := 1 + r.Intn(4); 0 < i; i-- {
+			rows = append(rows, 1+r.Intn(6))
+			height += rows[len(rows)-1]
+		}
+		pix := make([]byte, width*height)
+		for i := range pix {
+			pix[i] = byte(r.Intn(2))
+		}
+		whole, err := bmppath.New(bitmap(pix), width)
+		if err != nil {
+			t.Fatalf("New(): %v", err)
+		}
+
+		var tiles []*bmppath.Path
+		y0 := 0
+		for _, th := range rows {
+			x0 := 0
+			for _, tw := range cols {
+				sub := make([]byte, 0, tw*th)
+				for y := y0; y < y0+th; y++ {
+					sub = append(sub, pix[width*y+x0:width*y+x0+tw]...)
+				}
+				tile, err := bmppath.New(bitmap(sub), tw)
+				if err != nil {
+					t.Fatalf("New(): %v", err)
+				}
+				tiles = append(tiles, tile)
+				x0 += tw
+			}
+			y0 += th
+		}
+
+		stitched, err := bmppath.Stitch(tiles, bmppath.TileLayout{Columns: len(cols)})
+		if err != nil {
+			t.Fatalf("#%d: Stitch(): %v", n, err)
+		}
+		if !reflect.DeepEqual(stitched, whole) {
+			t.Errorf("#%d: cols=%v, rows=%v: unexpected result:\n%s", n, cols, rows, bmppath.Diff(whole, stitched))
+		}
+	}
+}
+
+func TestStitch_invalid(t *testing.T) {
+	// the same closed path twice, which cannot be linked
+	dup := &bmppath.Path{Width: 2, Height: 2, Vertices: [][]bmppath.Vertex{
+		{{0, 0}, {1, 0}, {1, 1}, {0, 1}},
+		{{0, 0}, {1, 0}, {1, 1}, {0, 1}},
+	}}
+	diagonal := &bmppath.Path{Width: 2, Height: 2, Vertices: [][]bmppath.Vertex{{{0, 0}, {2, 2}, {0, 2}}}}
+	outside := &bmppath.Path{Width: 1, Height: 1, Vertices: [][]bmppath.Vertex{{{0, 0}, {2, 0}, {2, 1}, {0, 1}}}}
+	for i, tiles := range [][]*bmppath.Path{{dup}, {diagonal}, {outside}} {
+		if _, err := bmppath.Stitch(tiles, bmppath.TileLayout{Columns: 2}); !errors.Is(err, bmppath.ErrInvalidLayout) {
+			t.Errorf("#%d: unexpected error: %v", i, err)
+		}
+	}
+
+	// the rectangles sharing the border are welded into one
+	half := &bmppath.Path{Width: 2, Height: 1, Vertices: [][]bmppath.Vertex{{{0, 0}, {2, 0}, {2, 1}, {0, 1}}}}
+	got, err := bmppath.Stitch([]*bmppath.Path{half, half}, bmppath.TileLayout{Columns: 2})
+	if err != nil {
+		t.Fatalf("Stitch(): %v", err)
+	}
+	want := &bmppath.Path{Width: 4, Height: 1, Vertices: [][]bmppath.Vertex{{{0, 0}, {4, 0}, {4, 1}, {0, 1}}}}
+	if !got.Equal(want) {
+		t.Errorf("unexpected result:\n%s", bmppath.Diff(want, got))
+	}
+}
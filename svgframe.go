@@ -0,0 +1,147 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidSVG is the error thrown when the SVG document to read is invalid.
+var ErrInvalidSVG = errors.New("invalid SVG")
+
+// SVGFrame represents the coordinate system of an existing SVG document, read
+// from the attributes of its root <svg> element.
+type SVGFrame struct {
+	// Width and Height are the size of the viewport in px. They are zero if
+	// the attributes are missing.
+	Width, Height float64
+
+	// ViewBox is the viewBox attribute as min-x, min-y, width, and height.
+	// If the attribute is missing, it is derived from Width and Height.
+	ViewBox [4]float64
+}
+
+// Transform represents an affine transform consisting of a scaling followed by
+// a translation.
+type Transform struct {
+	ScaleX, ScaleY         float64
+	TranslateX, TranslateY float64
+}
+
+// String returns the string representation of Transform that can be used as
+// the 'transform' attribute of SVG elements.
+func (t Transform) String() string {
+	return fmt.Sprintf("translate(%g %g) scale(%g %g)", t.TranslateX, t.TranslateY, t.ScaleX, t.ScaleY)
+}
+
+// Apply returns the point v transformed by t.
+func (t Transform) Apply(v FloatVertex) FloatVertex {
+	return FloatVertex{v[0]*t.ScaleX + t.TranslateX, v[1]*t.ScaleY + t.TranslateY}
+}
+
+// ReadSVGFrame reads an SVG document from r and returns the coordinate system
+// specified by its root <svg> element. The width and height attributes may
+// have the absolute units such as "mm" or "in", but not percentages.
+func ReadSVGFrame(r io.Reader) (*SVGFrame, error) {
+	dec := xml.NewDecoder(r)
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, fmt.Errorf("%w: no <svg> element: %v", ErrInvalidSVG, err)
+		}
+		se, ok := tok.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		if se.Name.Local != "svg" {
+			return nil, fmt.Errorf("%w: unexpected root element <%s>", ErrInvalidSVG, se.Name.Local)
+		}
+		return newSVGFrame(se.Attr)
+	}
+}
+
+func newSVGFrame(attrs []xml.Attr) (*SVGFrame, error) {
+	f := &SVGFrame{}
+	hasViewBox := false
+	for _, attr := range attrs {
+		var err error
+		switch attr.Name.Local {
+		case "width":
+			f.Width, err = parseSVGLength(attr.Value)
+		case "height":
+			f.Height, err = parseSVGLength(attr.Value)
+		case "viewBox":
+			fs := strings.FieldsFunc(attr.Value, func(r rune) bool {
+				return r == ',' || r == ' ' || r == '\t' || r == '\n' || r == '\r'
+			})
+			if len(fs) != 4 {
+				return nil, fmt.Errorf("%w: viewBox=%q", ErrInvalidSVG, attr.Value)
+			}
+			for i, s := range fs {
+				if f.ViewBox[i], err = strconv.ParseFloat(s, 64); err != nil {
+					break
+				}
+			}
+			hasViewBox = true
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s=%q: %v", ErrInvalidSVG, attr.Name.Local, attr.Value, err)
+		}
+	}
+	if !hasViewBox {
+		f.ViewBox = [4]float64{0, 0, f.Width, f.Height}
+	}
+	if f.ViewBox[2] <= 0 || f.ViewBox[3] <= 0 {
+		return nil, fmt.Errorf("%w: no valid viewBox, width, or height", ErrInvalidSVG)
+	}
+	return f, nil
+}
+
+// svgUnits maps the absolute length units of SVG to px.
+var svgUnits = map[string]float64{
+	"":   1,
+	"px": 1,
+	"in": 96,
+	"cm": 96 / 2.54,
+	"mm": 96 / 25.4,
+	"pt": 96.0 / 72,
+	"pc": 16,
+}
+
+func parseSVGLength(s string) (float64, error) {
+	s = strings.TrimSpace(s)
+	i := len(s)
+	for 0 < i && ('a' <= s[i-1] && s[i-1] <= 'z' || s[i-1] == '%') {
+		i--
+	}
+	unit, ok := svgUnits[s[i:]]
+	if !ok {
+		return 0, fmt.Errorf("%w: unsupported unit %q", ErrInvalidSVG, s[i:])
+	}
+	v, err := strconv.ParseFloat(s[:i], 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrInvalidSVG, err)
+	}
+	return v * unit, nil
+}
+
+// Overlay returns the transform that maps the canvas of p onto the viewBox of
+// the frame f, so that the traced image exactly covers the existing document.
+// This is useful for merging the traced content into hand-drawn documents
+// that share the same source image: the path can be placed in the document as
+// <path transform="..." d="..."/> with the result of String.
+func (f *SVGFrame) Overlay(p *Path) Transform {
+	return Transform{
+		ScaleX:     f.ViewBox[2] / float64(p.Width),
+		ScaleY:     f.ViewBox[3] / float64(p.Height),
+		TranslateX: f.ViewBox[0],
+		TranslateY: f.ViewBox[1],
+	}
+}
@@ -0,0 +1,69 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/tunabay/go-bitarray"
+	"github.com/tunabay/go-bmppath"
+)
+
+func ExampleSVGFrame_Overlay() {
+	doc := `<?xml version="1.0"?>
+<svg xmlns="http://www.w3.org/2000/svg" width="80mm" height="40mm" viewBox="10 20 160 80">
+<rect x="10" y="20" width="160" height="80"/>
+</svg>`
+
+	frame, err := bmppath.ReadSVGFrame(strings.NewReader(doc))
+	if err != nil {
+		panic(err)
+	}
+	path, err := bmppath.New(bitarray.NewBuffer(32*16), 32)
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Println(frame.Overlay(path))
+
+	// Output:
+	// translate(10 20) scale(5 5)
+}
+
+func TestReadSVGFrame(t *testing.T) {
+	tcs := []struct {
+		doc  string
+		want bmppath.SVGFrame
+	}{
+		{`<svg width="100" height="50"/>`, bmppath.SVGFrame{100, 50, [4]float64{0, 0, 100, 50}}},
+		{`<svg width="1in" height="2.54cm"/>`, bmppath.SVGFrame{96, 96, [4]float64{0, 0, 96, 96}}},
+		{`<svg viewBox="-1,-2, 3 4"/>`, bmppath.SVGFrame{0, 0, [4]float64{-1, -2, 3, 4}}},
+	}
+	for _, tc := range tcs {
+		f, err := bmppath.ReadSVGFrame(strings.NewReader(tc.doc))
+		if err != nil {
+			t.Errorf("%s: %v", tc.doc, err)
+			continue
+		}
+		if *f != tc.want {
+			t.Errorf("%s: got %+v, want %+v", tc.doc, *f, tc.want)
+		}
+	}
+
+	for _, doc := range []string{
+		``,
+		`<html/>`,
+		`<svg/>`,
+		`<svg width="50%" height="50%"/>`,
+		`<svg viewBox="0 0 10"/>`,
+	} {
+		if _, err := bmppath.ReadSVGFrame(strings.NewReader(doc)); !errors.Is(err, bmppath.ErrInvalidSVG) {
+			t.Errorf("%s: unexpected error: %v", doc, err)
+		}
+	}
+}
@@ -0,0 +1,28 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+)
+
+// WriteSVGZ writes the same SVG document as WriteSVG compressed with gzip, as
+// in the .svgz files. Most browsers and editors accept it directly, and it is
+// usually much smaller for the large images.
+func (p *Path) WriteSVGZ(w io.Writer) error {
+	zw, err := gzip.NewWriterLevel(w, gzip.BestCompression)
+	if err != nil {
+		return fmt.Errorf("gzip failure: %w", err)
+	}
+	if err := p.WriteSVG(zw); err != nil {
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("write failure: %w", err)
+	}
+	return nil
+}
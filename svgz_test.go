@@ -0,0 +1,44 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"testing"
+
+	"github.com/tunabay/go-bitarray"
+	"github.com/tunabay/go-bmppath"
+)
+
+func TestPath_WriteSVGZ(t *testing.T) {
+	bmp := bitarray.NewBufferFromByteSlice([]byte{
+		0b_10000001, 0b_01000010, 0b_00111100, 0b_01111110,
+		0b_11011011, 0b_01111110, 0b_00100100, 0b_11000011,
+	})
+	path, err := bmppath.New(bmp, 8)
+	if err != nil {
+		t.Fatalf("New(): %v", err)
+	}
+	var want, buf bytes.Buffer
+	if err := path.WriteSVG(&want); err != nil {
+		t.Fatalf("WriteSVG(): %v", err)
+	}
+	if err := path.WriteSVGZ(&buf); err != nil {
+		t.Fatalf("WriteSVGZ(): %v", err)
+	}
+	zr, err := gzip.NewReader(&buf)
+	if err != nil {
+		t.Fatalf("gzip.NewReader(): %v", err)
+	}
+	got, err := io.ReadAll(zr)
+	if err != nil {
+		t.Fatalf("ReadAll(): %v", err)
+	}
+	if !bytes.Equal(got, want.Bytes()) {
+		t.Errorf("unexpected document:\ngot:  %s\nwant: %s", got, want.Bytes())
+	}
+}
@@ -0,0 +1,167 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath
+
+import (
+	"image"
+	"sort"
+)
+
+// TextLine is a line of text found by SegmentText.
+type TextLine struct {
+	// Bounds is the bounding box of the line on the canvas.
+	Bounds image.Rectangle
+
+	// Glyphs is the glyph candidates in the line, from left to right.
+	Glyphs []*Glyph
+}
+
+// Glyph is a glyph candidate found by SegmentText, a group of the regions that
+// are likely to form a single glyph, such as the dot and the stem of "i".
+type Glyph struct {
+	// Bounds is the bounding box of the glyph on the canvas.
+	Bounds image.Rectangle
+
+	// Indexes is the indexes of the closed paths of the glyph, the outlines
+	// and the holes inside them, in the original Path.
+	Indexes []int
+
+	// Path is the closed paths of the glyph on the canvas fitted to Bounds,
+	// in the same manner as ExtractRegion.
+	Path *Path
+}
+
+// SegmentTextOptions represents the options for SegmentText.
+type SegmentTextOptions struct {
+	// MaxGap is the maximum vertical gap between the regions stacked in the
+	// same column that are merged into one glyph. Zero means the default
+	// value, the half of the median height of the regions. A negative value
+	// disables the merging.
+	MaxGap int
+}
+
+// SegmentText splits the traced scan of text into the lines and the glyph
+// candidates, as a preprocessing step for OCR or font-capture workflows. First,
+// each outline with the holes directly inside it becomes a region, and the
+// regions overlapping horizontally and separated vertically by no more than
+// the gap specified by opts are merged into a glyph. Then, the glyphs whose
+// vertical extents overlap are grouped into a line. The lines are returned in
+// the reading order, from top to bottom, each with the glyphs from left to
+// right. The text is assumed to be horizontal; Straighten the skewed scans in
+// advance. nil opts is the same as the zero value of SegmentTextOptions. p
+// itself is not modified.
+func (p *Path) SegmentText(opts *SegmentTextOptions) []*TextLine {
+	if opts == nil {
+		opts = &SegmentTextOptions{}
+	}
+	regions := p.groups()
+	if len(regions) == 0 {
+		return nil
+	}
+	bounds := make([]image.Rectangle, len(regions))
+	for i, g := range regions {
+		bounds[i] = p.PathBounds(g[0])
+		for _, n := range g[1:] {
+			bounds[i] = bounds[i].Union(p.PathBounds(n))
+		}
+	}
+	gap := opts.MaxGap
+	if gap == 0 {
+		hs := make([]int, len(bounds))
+		for i, r := range bounds {
+			hs[i] = r.Dy()
+		}
+		sort.Ints(hs)
+		gap = hs[len(hs)/2] / 2
+	}
+
+	// union-find of the regions merged into the glyphs
+	root := make([]int, len(regions))
+	for i := range root {
+		root[i] = i
+	}
+	var find func(i int) int
+	find = func(i int) int {
+		if root[i] != i {
+			root[i] = find(root[i])
+		}
+		return root[i]
+	}
+	if 0 <= gap {
+		for i, a := range bounds {
+			for j := i + 1; j < len(bounds); j++ {
+				if stacked(a, bounds[j], gap) {
+					root[find(j)] = find(i)
+				}
+			}
+		}
+	}
+	glyphIdx := make(map[int]*Glyph)
+	var glyphs []*Glyph
+	for i, g := range regions {
+		r := find(i)
+		gl, ok := glyphIdx[r]
+		if !ok {
+			gl = &Glyph{Bounds: bounds[i]}
+			glyphIdx[r] = gl
+			glyphs = append(glyphs, gl)
+		}
+		gl.Bounds = gl.Bounds.Union(bounds[i])
+		gl.Indexes = append(gl.Indexes, g...)
+	}
+	for _, gl := range glyphs {
+		sort.Ints(gl.Indexes)
+		gl.Path = p.extract(gl.Indexes)
+	}
+
+	// the lines by the overlaps of the vertical extents
+	sort.SliceStable(glyphs, func(i, j int) bool { return glyphs[i].Bounds.Min.Y < glyphs[j].Bounds.Min.Y })
+	var ret []*TextLine
+	for _, gl := range glyphs {
+		if n := len(ret); n != 0 && gl.Bounds.Min.Y < ret[n-1].Bounds.Max.Y {
+			ret[n-1].Bounds = ret[n-1].Bounds.Union(gl.Bounds)
+			ret[n-1].Glyphs = append(ret[n-1].Glyphs, gl)
+			continue
+		}
+		ret = append(ret, &TextLine{Bounds: gl.Bounds, Glyphs: []*Glyph{gl}})
+	}
+	for _, l := range ret {
+		gls := l.Glyphs
+		sort.SliceStable(gls, func(i, j int) bool { return gls[i].Bounds.Min.X < gls[j].Bounds.Min.X })
+	}
+	return ret
+}
+
+// stacked reports whether the regions of the bounding boxes a and b are stacked
+// vertically with the gap no more than gap, overlapping horizontally by at
+// least the half of the narrower one, or one of them contains the other.
+func stacked(a, b image.Rectangle, gap int) bool {
+	if a.In(b) || b.In(a) {
+		return true
+	}
+	ox := a.Max.X
+	if b.Max.X < ox {
+		ox = b.Max.X
+	}
+	if a.Min.X < b.Min.X {
+		ox -= b.Min.X
+	} else {
+		ox -= a.Min.X
+	}
+	nw := a.Dx()
+	if b.Dx() < nw {
+		nw = b.Dx()
+	}
+	if ox*2 < nw {
+		return false
+	}
+	switch {
+	case a.Max.Y <= b.Min.Y:
+		return b.Min.Y-a.Max.Y <= gap
+	case b.Max.Y <= a.Min.Y:
+		return a.Min.Y-b.Max.Y <= gap
+	}
+	return false
+}
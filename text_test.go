@@ -0,0 +1,54 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath_test
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/tunabay/go-bitarray"
+	"github.com/tunabay/go-bmppath"
+)
+
+func ExamplePath_SegmentText() {
+	bmp := bitarray.NewBufferFromBitArray(
+		bitarray.MustParse(strings.Join([]string{
+			"100000000",
+			"000000000",
+			"101110111",
+			"100000101",
+			"101110111",
+			"000000000",
+			"000000000",
+			"100000000",
+			"100111000",
+			"100000000",
+		}, "")),
+	)
+	path, err := bmppath.New(bmp, 9)
+	if err != nil {
+		panic(err)
+	}
+
+	for _, opts := range []*bmppath.SegmentTextOptions{{MaxGap: 1}, {MaxGap: -1}} {
+		fmt.Printf("MaxGap=%d:\n", opts.MaxGap)
+		for i, line := range path.SegmentText(opts) {
+			fmt.Printf("line %d %v:", i, line.Bounds)
+			for _, g := range line.Glyphs {
+				fmt.Printf(" %v%v", g.Bounds, g.Indexes)
+			}
+			fmt.Println()
+		}
+	}
+
+	// Output:
+	// MaxGap=1:
+	// line 0 (0,0)-(9,5): (0,0)-(1,5)[0 1] (2,2)-(5,5)[2 3] (6,2)-(9,5)[6 7]
+	// line 1 (0,7)-(6,10): (0,7)-(1,10)[4] (3,8)-(6,9)[5]
+	// MaxGap=-1:
+	// line 0 (0,0)-(1,1): (0,0)-(1,1)[0]
+	// line 1 (0,2)-(9,5): (0,2)-(1,5)[1] (2,2)-(5,3)[2] (2,4)-(5,5)[3] (6,2)-(9,5)[6 7]
+	// line 2 (0,7)-(6,10): (0,7)-(1,10)[4] (3,8)-(6,9)[5]
+}
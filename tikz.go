@@ -0,0 +1,96 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// TikZOptions represents the options for WriteTikZ.
+type TikZOptions struct {
+	// Scale is the size of a pixel in Unit. Zero means the default value 1.
+	Scale float64
+
+	// Unit is the TeX unit of Scale, such as "pt", "mm", or "cm". If empty,
+	// "pt" is used.
+	Unit string
+
+	// Fill is the fill color, such as "black" or "blue!50". If empty, "black"
+	// is used. "none" disables the fill.
+	Fill string
+
+	// Draw is the stroke color of the outlines. If empty, the outlines are
+	// not stroked.
+	Draw string
+
+	// Standalone wraps the picture in a complete LaTeX document of the
+	// standalone class, which can be compiled on its own. Otherwise, only
+	// the tikzpicture environment is written, to be included in a document
+	// loading the tikz package.
+	Standalone bool
+}
+
+// WriteTikZ writes the vectorized bitmap image as a TikZ picture for LaTeX, so
+// that the traced diagrams can be embedded in the documents without converting
+// the SVG with external tools. All the closed paths are written as a single
+// path of the \fill, \draw, or \filldraw command, each of which is a sequence of
+// the coordinates joined by -- and ended by cycle. The y-axis is flipped, since
+// it points upward in TikZ, and the lower left corner of the canvas is placed
+// at the origin. The default nonzero rule of TikZ fills the paths in the same
+// way as WriteSVG. nil opts is the same as the zero value of TikZOptions.
+func (p *Path) WriteTikZ(w io.Writer, opts *TikZOptions) error {
+	if opts == nil {
+		opts = &TikZOptions{}
+	}
+	scale, unit, fill := opts.Scale, opts.Unit, opts.Fill
+	if scale == 0 {
+		scale = 1
+	}
+	if unit == "" {
+		unit = "pt"
+	}
+	if fill == "" {
+		fill = "black"
+	}
+	var cmd string
+	switch {
+	case fill == "none" && opts.Draw == "":
+		cmd = `\path`
+	case fill == "none":
+		cmd = fmt.Sprintf(`\draw[%s]`, opts.Draw)
+	case opts.Draw == "":
+		cmd = fmt.Sprintf(`\fill[%s]`, fill)
+	default:
+		cmd = fmt.Sprintf(`\filldraw[fill=%s, draw=%s]`, fill, opts.Draw)
+	}
+	s := FormatCoord(6, unit)(scale)
+
+	var sb strings.Builder
+	if opts.Standalone {
+		sb.WriteString("\\documentclass[tikz]{standalone}\n\\begin{document}\n")
+	}
+	fmt.Fprintf(&sb, "\\begin{tikzpicture}[x=%s, y=%s]\n", s, s)
+	sb.WriteString(cmd)
+	for _, vs := range p.Vertices {
+		if len(vs) == 0 {
+			continue
+		}
+		sb.WriteString("\n ")
+		for _, v := range vs {
+			fmt.Fprintf(&sb, " (%d,%d) --", v[0], p.Height-v[1])
+		}
+		sb.WriteString(" cycle")
+	}
+	sb.WriteString(";\n\\end{tikzpicture}\n")
+	if opts.Standalone {
+		sb.WriteString("\\end{document}\n")
+	}
+	if _, err := io.WriteString(w, sb.String()); err != nil {
+		return fmt.Errorf("write failure: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath_test
+
+import (
+	"os"
+	"strings"
+
+	"github.com/tunabay/go-bitarray"
+	"github.com/tunabay/go-bmppath"
+)
+
+func ExamplePath_WriteTikZ() {
+	bmp := bitarray.NewBufferFromBitArray(
+		bitarray.MustParse(strings.Join([]string{
+			"1110",
+			"1010",
+			"1110",
+		}, "")),
+	)
+	path, err := bmppath.New(bmp, 4)
+	if err != nil {
+		panic(err)
+	}
+
+	opts := &bmppath.TikZOptions{
+		Scale: 0.5,
+		Unit:  "mm",
+		Fill:  "blue!50",
+		Draw:  "black",
+	}
+	if err := path.WriteTikZ(os.Stdout, opts); err != nil {
+		panic(err)
+	}
+
+	// Output:
+	// \begin{tikzpicture}[x=0.5mm, y=0.5mm]
+	// \filldraw[fill=blue!50, draw=black]
+	//   (0,3) -- (3,3) -- (3,0) -- (0,0) -- cycle
+	//   (1,2) -- (1,1) -- (2,1) -- (2,2) -- cycle;
+	// \end{tikzpicture}
+}
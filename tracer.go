@@ -0,0 +1,48 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath
+
+import (
+	"github.com/tunabay/go-bitarray"
+)
+
+// Tracer traces the bitmap images in the same way as NewWithOptions, reusing
+// the internal buffers across the calls, such as the edges and the vertices of
+// the closed paths. It reduces the allocations when many bitmap images are
+// traced, such as the frames of an animation or the pages of a scan. A Tracer
+// is not safe for concurrent use.
+type Tracer struct {
+	opts  *Options
+	edges []uint64
+	arena arena
+}
+
+// NewTracer creates a Tracer tracing the bitmap images with opts. nil opts is
+// the same as the zero value of Options.
+func NewTracer(opts *Options) *Tracer {
+	return &Tracer{opts: opts}
+}
+
+// Trace is identical to NewWithOptions with the options of t. The returned Path
+// does not share any memory with t, so it remains valid after the next call.
+func (t *Tracer) Trace(bm *bitarray.Buffer, width int) (*Path, error) {
+	height, err := bitmapHeight(bm, width)
+	if err != nil {
+		return nil, err
+	}
+	if t.opts != nil {
+		if bm, width, height, err = t.opts.prepare(bm, width, height); err != nil {
+			return nil, err
+		}
+	}
+
+	g, err := newEdgeGridBuffer(bm, width, height, t.edges)
+	if err != nil {
+		return nil, err
+	}
+	t.edges = g.planes[0][:cap(g.planes[0])]
+	t.arena.reset()
+	return g.trace(t.opts, &t.arena)
+}
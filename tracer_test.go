@@ -0,0 +1,88 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath_test
+
+import (
+	"errors"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/tunabay/go-bitarray"
+	"github.com/tunabay/go-bmppath"
+)
+
+func TestTracer(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	random := func(width, height int) *bitarray.Buffer {
+		bmp := bitarray.NewBuffer(width * height)
+		for i := 0; i < width*height; i++ {
+			if r.Intn(3) == 0 {
+				bmp.PutBitAt(i, 1)
+			}
+		}
+		return bmp
+	}
+	opts := &bmppath.Options{Workers: 1}
+	tr := bmppath.NewTracer(opts)
+	// the smaller images reuse the buffers of the larger ones
+	for _, size := range [][2]int{{64, 48}, {8, 8}, {100, 20}, {1, 1}, {64, 48}} {
+		bmp := random(size[0], size[1])
+		want, err := bmppath.NewWithOptions(bmp, size[0], opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := tr.Trace(bmp, size[0])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%dx%d: unexpected path: %v, want %v", size[0], size[1], got, want)
+		}
+	}
+	if _, err := tr.Trace(bitarray.NewBuffer(10), 3); err == nil {
+		t.Error("error expected for the invalid width")
+	}
+
+	// the vertices are not allocated one by one, and the buffers are reused
+	bmp := random(64, 48)
+	path, _ := tr.Trace(bmp, 64)
+	allocsNew := testing.AllocsPerRun(10, func() { _, _ = bmppath.NewWithOptions(bmp, 64, opts) })
+	allocsTracer := testing.AllocsPerRun(10, func() { _, _ = tr.Trace(bmp, 64) })
+	if max := float64(2 * len(path.Vertices)); max < allocsTracer {
+		t.Errorf("too many allocations: %.0f for %d closed paths", allocsTracer, len(path.Vertices))
+	}
+	if allocsNew <= allocsTracer {
+		t.Errorf("Tracer allocates %.0f times, not less than New %.0f", allocsTracer, allocsNew)
+	}
+}
+
+func TestTracer_stopped(t *testing.T) {
+	// the goroutines tracing the bands must not read the edges reused by
+	// the next call after Visit stops the tracing, which is reported by
+	// the race detector
+	const width, height = 512, 512
+	r := rand.New(rand.NewSource(1))
+	bmp := bitarray.NewBuffer(width * height)
+	for i := 0; i < width*height; i++ {
+		if r.Intn(3) == 0 {
+			bmp.PutBitAt(i, 1)
+		}
+	}
+	tr := bmppath.NewTracer(&bmppath.Options{
+		Workers: 4,
+		Partial: true,
+		Visit:   func([]bmppath.Vertex) bool { return false },
+	})
+	for i := 0; i < 8; i++ {
+		path, err := tr.Trace(bmp, width)
+		if !errors.Is(err, bmppath.ErrStopped) {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !path.Incomplete {
+			t.Error("path not marked as incomplete")
+		}
+	}
+}
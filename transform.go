@@ -0,0 +1,196 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath
+
+// Translate returns a new Path with all the vertices moved by dx and dy. The
+// Width and Height are also increased by dx and dy respectively, so that the
+// translated paths keep the same margins to the right and bottom edges of the
+// canvas. It panics if the Width or the Height of the result is negative or
+// overflows int. p itself is not modified.
+func (p *Path) Translate(dx, dy int) *Path {
+	width, ok := addInt(p.Width, dx)
+	if !ok || width < 0 {
+		panic("bmppath: translated width out of range")
+	}
+	height, ok := addInt(p.Height, dy)
+	if !ok || height < 0 {
+		panic("bmppath: translated height out of range")
+	}
+	return p.mapVertices(width, height, func(v Vertex) Vertex {
+		return Vertex{v[0] + dx, v[1] + dy}
+	})
+}
+
+// Scale returns a new Path magnified by the integer factor s. All the
+// coordinates, the Width and the Height are multiplied by s. s must be a
+// positive integer otherwise it panics. It also panics if the Width or the
+// Height of the result overflows int. p itself is not modified.
+func (p *Path) Scale(s int) *Path {
+	if s < 1 {
+		panic("bmppath: non-positive scale factor")
+	}
+	if _, ok := mulInt(p.Width, s); !ok {
+		panic("bmppath: scale factor too large")
+	}
+	if _, ok := mulInt(p.Height, s); !ok {
+		panic("bmppath: scale factor too large")
+	}
+	return p.mapVertices(p.Width*s, p.Height*s, func(v Vertex) Vertex {
+		return Vertex{v[0] * s, v[1] * s}
+	})
+}
+
+// mapVertices returns a new Path of the size width x height, with each vertex
+// of p converted by f.
+func (p *Path) mapVertices(width, height int, f func(Vertex) Vertex) *Path {
+	ret := &Path{
+		Width:    width,
+		Height:   height,
+		Vertices: make([][]Vertex, len(p.Vertices)),
+	}
+	for i, vs := range p.Vertices {
+		nvs := make([]Vertex, len(vs))
+		for j, v := range vs {
+			nvs[j] = f(v)
+		}
+		ret.Vertices[i] = nvs
+	}
+	if p.Labels != nil {
+		ret.Labels = append([]string(nil), p.Labels...)
+	}
+	return ret
+}
+
+// FlipH returns a new Path flipped horizontally. The vertices of each closed
+// path are reordered so that the outlines and holes keep running in the same
+// directions as before. p itself is not modified.
+func (p *Path) FlipH() *Path {
+	ret := p.mapVertices(p.Width, p.Height, func(v Vertex) Vertex {
+		return Vertex{p.Width - v[0], v[1]}
+	})
+	for _, vs := range ret.Vertices {
+		reverseVertices(vs)
+		normalizeVertices(vs)
+	}
+	return ret
+}
+
+// FlipV returns a new Path flipped vertically. The vertices of each closed path
+// are reordered so that the outlines and holes keep running in the same
+// directions as before. p itself is not modified.
+func (p *Path) FlipV() *Path {
+	ret := p.mapVertices(p.Width, p.Height, func(v Vertex) Vertex {
+		return Vertex{v[0], p.Height - v[1]}
+	})
+	for _, vs := range ret.Vertices {
+		reverseVertices(vs)
+		normalizeVertices(vs)
+	}
+	return ret
+}
+
+// Rotate90 returns a new Path rotated clockwise by n * 90 degrees on the screen
+// coordinates. n may be negative to rotate counterclockwise. When the rotation
+// is an odd multiple of 90 degrees, the Width and Height are swapped. p itself
+// is not modified.
+func (p *Path) Rotate90(n int) *Path {
+	var ret *Path
+	switch n & 3 {
+	case 0:
+		ret = p.mapVertices(p.Width, p.Height, func(v Vertex) Vertex {
+			return v
+		})
+	case 1:
+		ret = p.mapVertices(p.Height, p.Width, func(v Vertex) Vertex {
+			return Vertex{p.Height - v[1], v[0]}
+		})
+	case 2:
+		ret = p.mapVertices(p.Width, p.Height, func(v Vertex) Vertex {
+			return Vertex{p.Width - v[0], p.Height - v[1]}
+		})
+	case 3:
+		ret = p.mapVertices(p.Height, p.Width, func(v Vertex) Vertex {
+			return Vertex{v[1], p.Width - v[0]}
+		})
+	}
+	for _, vs := range ret.Vertices {
+		normalizeVertices(vs)
+	}
+	return ret
+}
+
+// Reverse returns a new Path with the directions of all the closed paths
+// reversed. Each closed path keeps its first vertex. p itself is not modified.
+func (p *Path) Reverse() *Path {
+	ret := p.mapVertices(p.Width, p.Height, func(v Vertex) Vertex { return v })
+	for _, vs := range ret.Vertices {
+		reverseVertices(vs)
+	}
+	return ret
+}
+
+// ReversePath returns a new Path with the direction of the closed path
+// specified by the index n reversed. The closed path keeps its first vertex.
+// n must be less than p.NumPath() otherwise it panics. p itself is not
+// modified.
+func (p *Path) ReversePath(n int) *Path {
+	_ = p.Vertices[n]
+	ret := p.mapVertices(p.Width, p.Height, func(v Vertex) Vertex { return v })
+	reverseVertices(ret.Vertices[n])
+	return ret
+}
+
+// reverseVertices reverses the direction of the closed path vs in place, while
+// keeping the first vertex.
+func reverseVertices(vs []Vertex) {
+	for i, j := 1, len(vs)-1; i < j; i, j = i+1, j-1 {
+		vs[i], vs[j] = vs[j], vs[i]
+	}
+}
+
+// normalizeVertices rotates the closed path vs in place so that it starts from
+// the vertex closest to the origin, as New does.
+func normalizeVertices(vs []Vertex) {
+	var mind int64
+	mini := -1
+	for i, v := range vs {
+		if d := sqDist(v[0], v[1]); mini == -1 || d < mind {
+			mind, mini = d, i
+		}
+	}
+	if mini < 1 {
+		return
+	}
+	tmp := append([]Vertex(nil), vs[:mini]...)
+	copy(vs, vs[mini:])
+	copy(vs[len(vs)-mini:], tmp)
+}
+
+// sortNearest reorders the closed paths vss in place, starting from the one
+// nearest to the origin and then the one nearest to the previous one, as New
+// does. The ties are broken by the current order.
+func sortNearest(vss [][]Vertex) {
+	var cur Vertex
+	for i := range vss {
+		var mind int64
+		mini := -1
+		for j := i; j < len(vss); j++ {
+			var d int64
+			if len(vss[j]) != 0 {
+				d = sqDist(vss[j][0][0]-cur[0], vss[j][0][1]-cur[1])
+			}
+			if mini == -1 || d < mind {
+				mind, mini = d, j
+			}
+		}
+		// the rest are shifted rather than swapped to keep their order
+		vs := vss[mini]
+		copy(vss[i+1:mini+1], vss[i:mini])
+		vss[i] = vs
+		if len(vs) != 0 {
+			cur = vs[0]
+		}
+	}
+}
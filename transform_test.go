@@ -0,0 +1,145 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/tunabay/go-bitarray"
+	"github.com/tunabay/go-bmppath"
+)
+
+func ExamplePath_Translate() {
+	bmp := bitarray.NewBufferFromBitArray(
+		bitarray.MustParse(strings.Join([]string{
+			"110",
+			"100",
+		}, "")),
+	)
+	path, err := bmppath.New(bmp, 3)
+	if err != nil {
+		panic(err)
+	}
+
+	moved := path.Translate(2, 1)
+	fmt.Printf("%dx%d: %s\n", moved.Width, moved.Height, moved.PathString(0))
+
+	// Output:
+	// 5x3: (2, 1), (4, 1), (4, 2), (3, 2), (3, 3), (2, 3)
+}
+
+func ExamplePath_Scale() {
+	bmp := bitarray.NewBufferFromBitArray(
+		bitarray.MustParse(strings.Join([]string{
+			"110",
+			"100",
+		}, "")),
+	)
+	path, err := bmppath.New(bmp, 3)
+	if err != nil {
+		panic(err)
+	}
+
+	scaled := path.Scale(3)
+	fmt.Printf("%dx%d: %s\n", scaled.Width, scaled.Height, scaled.SVGDString())
+
+	// Output:
+	// 9x6: m0,0h6v3h-3v3h-3z
+}
+
+func ExamplePath_FlipH() {
+	bmp := bitarray.NewBufferFromBitArray(
+		bitarray.MustParse(strings.Join([]string{
+			"110",
+			"100",
+		}, "")),
+	)
+	path, err := bmppath.New(bmp, 3)
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Println(path.FlipH().PathString(0))
+	fmt.Println(path.FlipV().PathString(0))
+
+	// Output:
+	// (1, 0), (3, 0), (3, 2), (2, 2), (2, 1), (1, 1)
+	// (0, 0), (1, 0), (1, 1), (2, 1), (2, 2), (0, 2)
+}
+
+func ExamplePath_Rotate90() {
+	bmp := bitarray.NewBufferFromBitArray(
+		bitarray.MustParse(strings.Join([]string{
+			"110",
+			"100",
+		}, "")),
+	)
+	path, err := bmppath.New(bmp, 3)
+	if err != nil {
+		panic(err)
+	}
+
+	for n := 0; n < 4; n++ {
+		rotated := path.Rotate90(n)
+		fmt.Printf("%d: %dx%d: %s\n", n, rotated.Width, rotated.Height, rotated.PathString(0))
+	}
+
+	// Output:
+	// 0: 3x2: (0, 0), (2, 0), (2, 1), (1, 1), (1, 2), (0, 2)
+	// 1: 2x3: (0, 0), (2, 0), (2, 2), (1, 2), (1, 1), (0, 1)
+	// 2: 3x2: (1, 1), (2, 1), (2, 0), (3, 0), (3, 2), (1, 2)
+	// 3: 2x3: (0, 1), (1, 1), (1, 2), (2, 2), (2, 3), (0, 3)
+}
+
+func ExamplePath_Reverse() {
+	bmp := bitarray.NewBufferFromBitArray(
+		bitarray.MustParse(strings.Join([]string{
+			"111",
+			"101",
+			"111",
+		}, "")),
+	)
+	path, err := bmppath.New(bmp, 3)
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Println(path.Reverse().SVGDString())
+	fmt.Println(path.ReversePath(1).SVGDString())
+
+	// Output:
+	// m0,0v3h3v-3zm1,1h1v1h-1z
+	// m0,0h3v3h-3zm1,1h1v1h-1z
+}
+
+func TestPath_Scale_overflow(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("no panic")
+		}
+	}()
+	p := &bmppath.Path{Width: int(^uint(0)>>1)/2 + 1, Height: 1}
+	_ = p.Scale(2)
+}
+
+func TestPath_Translate_outOfRange(t *testing.T) {
+	for _, d := range [][2]int{{-4, 0}, {0, -5}, {int(^uint(0) >> 1), 0}} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%v: no panic", d)
+				}
+			}()
+			p := &bmppath.Path{Width: 3, Height: 4}
+			_ = p.Translate(d[0], d[1])
+		}()
+	}
+	// the size may shrink to zero
+	if p := (&bmppath.Path{Width: 3, Height: 4}).Translate(-3, -4); p.Width != 0 || p.Height != 0 {
+		t.Errorf("unexpected size: %dx%d", p.Width, p.Height)
+	}
+}
@@ -0,0 +1,109 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// VectorDrawableOptions represents the options for WriteVectorDrawable.
+type VectorDrawableOptions struct {
+	// Width and Height are the intrinsic size of the drawable in dp. Zero
+	// means the size of the canvas, one dp per pixel.
+	Width, Height float64
+
+	// FillColor is the fill color of the path, in the Android color format
+	// such as "#FF000000", or a resource reference such as
+	// "@color/icon". If empty, "#FF000000" is used.
+	FillColor string
+
+	// Name is the name of the path, referenced by the animations of
+	// AnimatedVectorDrawable. If empty, the name is omitted.
+	Name string
+}
+
+// WriteVectorDrawable writes the vectorized bitmap image as an Android
+// VectorDrawable XML resource, a <vector> element with a single <path> element.
+// The viewport is the canvas of p, so that the pathData is in pixels regardless
+// of the size of the drawable. The pathData is written with the absolute
+// commands only, one M per closed path, and with the explicit separators
+// between all the numbers, since the path parsers of the older Android versions
+// do not handle some compact forms of the SVG path data written by WriteSVGD.
+// The default nonZero fillType fills the paths in the same way as WriteSVG.
+// nil opts is the same as the zero value of VectorDrawableOptions.
+func (p *Path) WriteVectorDrawable(w io.Writer, opts *VectorDrawableOptions) error {
+	if opts == nil {
+		opts = &VectorDrawableOptions{}
+	}
+	width, height, color := opts.Width, opts.Height, opts.FillColor
+	if width == 0 {
+		width = float64(p.Width)
+	}
+	if height == 0 {
+		height = float64(p.Height)
+	}
+	if color == "" {
+		color = "#FF000000"
+	}
+	f := FormatCoord(3, "dp")
+
+	var sb strings.Builder
+	sb.WriteString(`<?xml version="1.0" encoding="utf-8"?>` + "\n")
+	sb.WriteString(`<vector xmlns:android="http://schemas.android.com/apk/res/android"` + "\n")
+	fmt.Fprintf(&sb, "    android:width=%q\n", f(width))
+	fmt.Fprintf(&sb, "    android:height=%q\n", f(height))
+	fmt.Fprintf(&sb, "    android:viewportWidth=\"%d\"\n", p.Width)
+	fmt.Fprintf(&sb, "    android:viewportHeight=\"%d\">\n", p.Height)
+	if d := p.vectorDrawablePathData(); d != "" {
+		attr := func(name, value string) {
+			sb.WriteString("        android:" + name + `="`)
+			_ = xml.EscapeText(&sb, []byte(value))
+			sb.WriteString("\"\n")
+		}
+		sb.WriteString("    <path\n")
+		if opts.Name != "" {
+			attr("name", opts.Name)
+		}
+		attr("fillColor", color)
+		fmt.Fprintf(&sb, "        android:pathData=%q/>\n", d)
+	}
+	sb.WriteString("</vector>\n")
+	if _, err := io.WriteString(w, sb.String()); err != nil {
+		return fmt.Errorf("write failure: %w", err)
+	}
+	return nil
+}
+
+// vectorDrawablePathData returns the pathData of the VectorDrawable, written
+// with the absolute commands M, H, V, L, and Z.
+func (p *Path) vectorDrawablePathData() string {
+	var sb strings.Builder
+	for _, vs := range p.Vertices {
+		if len(vs) == 0 {
+			continue
+		}
+		if sb.Len() != 0 {
+			sb.WriteByte(' ')
+		}
+		c := vs[0]
+		fmt.Fprintf(&sb, "M%d,%d", c[0], c[1])
+		for _, v := range vs[1:] {
+			switch {
+			case v[1] == c[1]:
+				fmt.Fprintf(&sb, " H%d", v[0])
+			case v[0] == c[0]:
+				fmt.Fprintf(&sb, " V%d", v[1])
+			default:
+				fmt.Fprintf(&sb, " L%d,%d", v[0], v[1])
+			}
+			c = v
+		}
+		sb.WriteString(" Z")
+	}
+	return sb.String()
+}
@@ -0,0 +1,51 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath_test
+
+import (
+	"os"
+	"strings"
+
+	"github.com/tunabay/go-bitarray"
+	"github.com/tunabay/go-bmppath"
+)
+
+func ExamplePath_WriteVectorDrawable() {
+	bmp := bitarray.NewBufferFromBitArray(
+		bitarray.MustParse(strings.Join([]string{
+			"1110",
+			"1010",
+			"1110",
+			"0001",
+		}, "")),
+	)
+	path, err := bmppath.New(bmp, 4)
+	if err != nil {
+		panic(err)
+	}
+
+	opts := &bmppath.VectorDrawableOptions{
+		Width:     24,
+		Height:    24,
+		FillColor: "@color/icon",
+		Name:      "glyph",
+	}
+	if err := path.WriteVectorDrawable(os.Stdout, opts); err != nil {
+		panic(err)
+	}
+
+	// Output:
+	// <?xml version="1.0" encoding="utf-8"?>
+	// <vector xmlns:android="http://schemas.android.com/apk/res/android"
+	//     android:width="24dp"
+	//     android:height="24dp"
+	//     android:viewportWidth="4"
+	//     android:viewportHeight="4">
+	//     <path
+	//         android:name="glyph"
+	//         android:fillColor="@color/icon"
+	//         android:pathData="M0,0 H3 V3 H4 V4 H3 V3 H0 Z M1,1 V2 H2 V1 Z"/>
+	// </vector>
+}
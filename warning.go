@@ -0,0 +1,128 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath
+
+import (
+	"fmt"
+	"image"
+)
+
+// WarningKind specifies the kind of a Warning.
+type WarningKind int
+
+const (
+	// WarnSpeckle is the ink component erased, or the hole filled, by
+	// Options.Despeckle.
+	WarnSpeckle WarningKind = iota
+
+	// WarnMerged is the pair of the closed paths touching each other at a
+	// vertex, merged into one closed path.
+	WarnMerged
+
+	// WarnBorderInk is the closed path touching the border of the canvas,
+	// which suggests that the ink may be clipped by the edge of the source
+	// image.
+	WarnBorderInk
+
+	// WarnDiagonal is the closed path with the segments that are neither
+	// horizontal nor vertical, which are not written by WriteSVGD and the
+	// writers based on it.
+	WarnDiagonal
+
+	// WarnDegenerate is the closed path enclosing no area.
+	WarnDegenerate
+
+	// WarnDuplicateLabel is the closed path with the same label as a
+	// preceding one, for which ExportContours returns ErrInvalidName.
+	WarnDuplicateLabel
+)
+
+// String returns the string representation of k.
+func (k WarningKind) String() string {
+	switch k {
+	case WarnSpeckle:
+		return "speckle"
+	case WarnMerged:
+		return "merged"
+	case WarnBorderInk:
+		return "border-ink"
+	case WarnDiagonal:
+		return "diagonal"
+	case WarnDegenerate:
+		return "degenerate"
+	case WarnDuplicateLabel:
+		return "duplicate-label"
+	}
+	return fmt.Sprintf("WarningKind(%d)", int(k))
+}
+
+// Warning is a non-fatal issue encountered during the tracing or found by
+// Warnings, which does not fail the operation but may be a quality concern.
+type Warning struct {
+	// Kind is the kind of the issue.
+	Kind WarningKind
+
+	// Bounds is the area of the canvas concerned.
+	Bounds image.Rectangle
+
+	// Path is the index of the closed path concerned, or -1 if the issue
+	// is not about a closed path of the resulting Path, such as the ones
+	// reported during the tracing.
+	Path int
+
+	// Message is the human-readable description of the issue.
+	Message string
+}
+
+// String returns the string representation of w, suitable for the log output.
+func (w Warning) String() string {
+	return fmt.Sprintf("%s: %v: %s", w.Kind, w.Bounds, w.Message)
+}
+
+// warn reports the warning of the kind k on the area r through opts.Warn, if
+// specified. opts may be nil.
+func (opts *Options) warn(k WarningKind, r image.Rectangle, format string, a ...interface{}) {
+	if opts == nil || opts.Warn == nil {
+		return
+	}
+	opts.Warn(Warning{Kind: k, Bounds: r, Path: -1, Message: fmt.Sprintf(format, a...)})
+}
+
+// Warnings checks the closed paths of p for the issues that may cause a loss in
+// the export, and returns the warnings found, in the order of the closed paths.
+// It reports the closed paths with the diagonal segments, the ones enclosing no
+// area, and the ones labeled with the same label as a preceding one. It returns
+// nil if no issue is found.
+func (p *Path) Warnings() []Warning {
+	var ret []Warning
+	add := func(n int, k WarningKind, format string, a ...interface{}) {
+		ret = append(ret, Warning{Kind: k, Bounds: p.PathBounds(n), Path: n, Message: fmt.Sprintf(format, a...)})
+	}
+	labels := make(map[string]int)
+	for n, vs := range p.Vertices {
+		if len(vs) == 0 {
+			ret = append(ret, Warning{Kind: WarnDegenerate, Path: n, Message: "empty closed path"})
+			continue
+		}
+		for i, v := range vs {
+			u := vs[(i+1)%len(vs)]
+			if v[0] != u[0] && v[1] != u[1] {
+				add(n, WarnDiagonal, "diagonal segment %v-%v", v, u)
+				break
+			}
+		}
+		if signedArea(vs) == 0 {
+			add(n, WarnDegenerate, "zero area with %d vertices", len(vs))
+		}
+		if label := p.PathLabel(n); label != "" {
+			if m, ok := labels[label]; ok {
+				add(n, WarnDuplicateLabel, "label %q already used by path #%d", label, m)
+			} else {
+				labels[label] = n
+			}
+		}
+	}
+	return ret
+}
@@ -0,0 +1,62 @@
+// Copyright (c) 2021 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package bmppath_test
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/tunabay/go-bitarray"
+	"github.com/tunabay/go-bmppath"
+)
+
+func ExampleOptions_warn() {
+	bmp := bitarray.NewBufferFromBitArray(
+		bitarray.MustParse(strings.Join([]string{
+			"000000",
+			"011000",
+			"100100",
+			"011000",
+			"000001",
+		}, "")),
+	)
+
+	opts := &bmppath.Options{
+		Despeckle: 1,
+		Warn: func(w bmppath.Warning) {
+			fmt.Println(w)
+		},
+	}
+	if _, err := bmppath.NewWithOptions(bmp, 6, opts); err != nil {
+		panic(err)
+	}
+
+	// Output:
+	// speckle: (5,4)-(6,5): ink speck removed, area 1
+	// border-ink: (0,1)-(4,4): closed path touching the border of the canvas
+	// merged: (2,1)-(4,3): closed paths touching at (3, 2) merged
+}
+
+func ExamplePath_Warnings() {
+	path := &bmppath.Path{
+		Width:  8,
+		Height: 4,
+		Vertices: [][]bmppath.Vertex{
+			{{0, 0}, {2, 0}, {2, 2}, {0, 2}},
+			{{3, 0}, {5, 0}, {3, 2}},
+			{{6, 0}, {8, 0}, {8, 2}, {6, 2}},
+			{{0, 3}, {4, 3}},
+		},
+		Labels: []string{"a", "", "a"},
+	}
+	for _, w := range path.Warnings() {
+		fmt.Println(w.Path, w)
+	}
+
+	// Output:
+	// 1 diagonal: (3,0)-(5,2): diagonal segment (5, 0)-(3, 2)
+	// 2 duplicate-label: (6,0)-(8,2): label "a" already used by path #0
+	// 3 degenerate: (0,3)-(4,3): zero area with 2 vertices
+}